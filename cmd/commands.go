@@ -0,0 +1,153 @@
+package main
+
+import (
+	"context"
+	"errors"
+	"flag"
+	"fmt"
+	"io"
+	"os"
+	"os/signal"
+	"syscall"
+
+	"github.com/psyb0t/glogger"
+	app "github.com/psyb0t/telegram-logger/internal/app/v1"
+)
+
+const (
+	commandBackup  = "backup"
+	commandRestore = "restore"
+	commandExport  = "export"
+	commandImport  = "import"
+
+	commandRotateKey = "rotate-key"
+
+	defaultExportFormat = "json"
+
+	// backupFileMode keeps the backups and exports, which hold
+	// every chat ID and token hash, readable by the owner only.
+	backupFileMode = 0o600
+)
+
+var (
+	// errUnknownCommand is returned when the first argument isn't a known command.
+	errUnknownCommand = errors.New("unknown command")
+	// errMissingFileArgument is returned when a command is run without its file argument.
+	errMissingFileArgument = errors.New("missing file argument")
+)
+
+// usage is printed when a command can't be parsed.
+const usage = `Usage: telegram-logger [command]
+
+Without a command, the app is started. The commands use the configured storage:
+
+  backup <file>                  write a backup of the storage (badgerDB only)
+  restore <file>                 load a backup into the empty storage
+  export [--format json] <file>  write all of the data in a format any storage can import
+  import [--format json] <file>  load an export into the empty storage
+  rotate-key <file>              encrypt the storage with the key in the file instead
+                                 of the configured one (badgerDB only)
+`
+
+// runCommand runs the command given by args and returns the exit code of
+// the process. The command is stopped if an interrupt signal is received.
+func runCommand(args []string) int {
+	log := glogger.New(glogger.Caller{
+		Service:  os.Getenv(serviceNameEnvVarName),
+		Package:  "main",
+		Function: "runCommand",
+	})
+
+	ctx, stop := signal.NotifyContext(context.Background(), os.Interrupt, syscall.SIGTERM)
+	defer stop()
+
+	var err error
+
+	switch name := args[0]; name {
+	case commandBackup:
+		err = runFileCommand(name, args[1:], true, func(f *os.File, _ string) error {
+			return app.Backup(ctx, f)
+		})
+	case commandRestore:
+		err = runFileCommand(name, args[1:], false, func(f *os.File, _ string) error {
+			return app.Restore(ctx, f)
+		})
+	case commandExport:
+		err = runFileCommand(name, args[1:], true, func(f *os.File, format string) error {
+			return app.Export(ctx, f, format)
+		})
+	case commandImport:
+		err = runFileCommand(name, args[1:], false, func(f *os.File, format string) error {
+			return app.Import(ctx, f, format)
+		})
+	case commandRotateKey:
+		err = runFileCommand(name, args[1:], false, func(f *os.File, _ string) error {
+			return app.RotateEncryptionKey(f)
+		})
+	default:
+		err = fmt.Errorf("%w: %s", errUnknownCommand, name)
+	}
+
+	if err != nil {
+		if errors.Is(err, errUnknownCommand) || errors.Is(err, errMissingFileArgument) {
+			fmt.Fprint(os.Stderr, usage)
+		}
+
+		log.Err(err).Error("command failed")
+
+		return 1
+	}
+
+	log.Info("command done")
+
+	return 0
+}
+
+// runFileCommand parses the flags and the file argument of the
+// command, creates the file if create is true or opens it otherwise
+// and calls fn with it and the format given by the --format flag.
+// A created file is removed if fn fails so that it's never
+// mistaken for a complete one.
+func runFileCommand(name string, args []string, create bool, fn func(f *os.File, format string) error) error {
+	flags := flag.NewFlagSet(name, flag.ContinueOnError)
+	flags.SetOutput(io.Discard)
+
+	format := flags.String("format", defaultExportFormat, "the format of the export")
+
+	if err := flags.Parse(args); err != nil {
+		return err
+	}
+
+	path := flags.Arg(0)
+	if path == "" {
+		return errMissingFileArgument
+	}
+
+	openFile := os.Open
+	if create {
+		openFile = createFile
+	}
+
+	f, err := openFile(path)
+	if err != nil {
+		return err
+	}
+
+	if err := fn(f, *format); err != nil {
+		f.Close()
+
+		if create {
+			os.Remove(path)
+		}
+
+		return err
+	}
+
+	return f.Close()
+}
+
+// createFile creates a new file only the owner can read, failing if
+// it already exists so that an older backup is never overwritten.
+func createFile(path string) (*os.File, error) {
+	return os.OpenFile(path, os.O_WRONLY|os.O_CREATE|os.O_EXCL, backupFileMode)
+}
@@ -17,7 +17,8 @@ const (
 )
 
 // main is the entry point of the application. It sets the service name
-// environment variable, creates a channel to receive os signals, creates
+// environment variable and, if a command is given, runs it and exits.
+// Otherwise it creates a channel to receive os signals, creates
 // a context and wait group and starts the app. If the app returns an error
 // or an os signal is received, the context is cancelled and the wait group
 // is waited to finish.
@@ -26,6 +27,10 @@ func main() {
 		panic(err)
 	}
 
+	if len(os.Args) > 1 {
+		os.Exit(runCommand(os.Args[1:]))
+	}
+
 	defer os.Unsetenv(serviceNameEnvVarName) //nolint:errcheck
 
 	log := glogger.New(glogger.Caller{
@@ -0,0 +1,373 @@
+package v1
+
+import (
+	"bufio"
+	"crypto/subtle"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net"
+	"os"
+	"strings"
+
+	"github.com/psyb0t/glogger"
+	"github.com/psyb0t/telegram-logger/internal/pkg/storage"
+	internaltypes "github.com/psyb0t/telegram-logger/internal/pkg/types"
+	"github.com/psyb0t/telegram-logger/pkg/types"
+	"github.com/valyala/fasthttp"
+)
+
+const (
+	headerNameAuthorization = "Authorization"
+	authorizationBearer     = "Bearer "
+
+	adminUserIDUserValue = "id"
+
+	headerNameContentDisposition = "Content-Disposition"
+	contentTypeOctetStream       = "application/octet-stream"
+	backupFileName               = "telegram-logger.bak"
+)
+
+// adminAuthHTTPMiddleware makes sure the wrapped admin HTTP handler is only
+// called for requests carrying the configured admin token as a bearer
+// token. The admin API is disabled if there's no admin token configured.
+func (a *app) adminAuthHTTPMiddleware(next fasthttp.RequestHandler) fasthttp.RequestHandler {
+	return func(ctx *fasthttp.RequestCtx) {
+		if a.config.Admin.Token == "" {
+			a.returnHTTPResponseString(ctx, fasthttp.StatusNotFound,
+				fasthttp.StatusMessage(fasthttp.StatusNotFound))
+
+			return
+		}
+
+		authorization := string(ctx.Request.Header.Peek(headerNameAuthorization))
+		token := strings.TrimPrefix(authorization, authorizationBearer)
+
+		if token == authorization ||
+			subtle.ConstantTimeCompare([]byte(token), []byte(a.config.Admin.Token)) != 1 {
+			a.returnHTTPResponseJSON(ctx, fasthttp.StatusUnauthorized,
+				types.Response{Error: "invalid token", Reason: types.ResponseReasonInvalidToken})
+
+			return
+		}
+
+		next(ctx)
+	}
+}
+
+// adminGetAllUsersHTTPHandler responds with all of the users.
+func (a *app) adminGetAllUsersHTTPHandler(ctx *fasthttp.RequestCtx) {
+	log := glogger.New(glogger.Caller{
+		Service:  os.Getenv(serviceNameEnvVarName),
+		Package:  packageName,
+		Receiver: "app",
+		Function: "adminGetAllUsersHTTPHandler",
+	})
+
+	log.Debug("getting all users")
+	users, err := a.db.GetUserRepositoryReader().GetAll(ctx)
+	if err != nil {
+		log.Err(err).Error("an error occurred when trying to get all users")
+
+		a.returnHTTPResponseJSON(ctx, fasthttp.StatusInternalServerError,
+			types.Response{Error: err.Error()})
+
+		return
+	}
+
+	response := types.AdminUserListResponse{Users: make([]types.AdminUser, 0, len(users))}
+	for _, user := range users {
+		response.Users = append(response.Users, adminUserFromUser(user))
+	}
+
+	a.returnHTTPResponseJSON(ctx, fasthttp.StatusOK, response)
+}
+
+// adminBackupHTTPHandler streams a backup of the storage while the app
+// keeps running. It responds with 501 Not Implemented if the storage
+// doesn't support backups.
+func (a *app) adminBackupHTTPHandler(ctx *fasthttp.RequestCtx) {
+	log := glogger.New(glogger.Caller{
+		Service:  os.Getenv(serviceNameEnvVarName),
+		Package:  packageName,
+		Receiver: "app",
+		Function: "adminBackupHTTPHandler",
+	})
+
+	backuper, ok := a.db.(storage.Backuper)
+	if !ok {
+		a.returnHTTPResponseJSON(ctx, fasthttp.StatusNotImplemented,
+			types.Response{Error: ErrBackupNotSupported.Error()})
+
+		return
+	}
+
+	ctx.SetContentType(contentTypeOctetStream)
+	ctx.Response.Header.Set(headerNameContentDisposition, fmt.Sprintf(`attachment; filename="%s"`, backupFileName))
+
+	// the backup is written after the handler returns so the
+	// status can't change anymore if it fails midway
+	ctx.SetBodyStreamWriter(func(w *bufio.Writer) {
+		log.Info("streaming backup")
+		if err := backuper.Backup(a.ctx, w); err != nil {
+			log.Err(err).Error("an error occurred when streaming the backup")
+
+			return
+		}
+
+		if err := w.Flush(); err != nil {
+			log.Err(err).Error("could not flush the backup")
+		}
+	})
+}
+
+// adminGetUserHTTPHandler responds with the user identified by the id path parameter.
+func (a *app) adminGetUserHTTPHandler(ctx *fasthttp.RequestCtx) {
+	user, ok := a.adminGetRequestedUser(ctx)
+	if !ok {
+		return
+	}
+
+	a.returnHTTPResponseJSON(ctx, fasthttp.StatusOK, adminUserFromUser(user))
+}
+
+// adminCreateUserHTTPHandler creates a user from the JSON request body and
+// responds with the user along with its token and signing secret. Unlike
+// the bot commands, the other users of the same chat are kept.
+func (a *app) adminCreateUserHTTPHandler(ctx *fasthttp.RequestCtx) {
+	log := glogger.New(glogger.Caller{
+		Service:  os.Getenv(serviceNameEnvVarName),
+		Package:  packageName,
+		Receiver: "app",
+		Function: "adminCreateUserHTTPHandler",
+	})
+
+	request := types.AdminUserRequest{}
+	if err := json.Unmarshal(ctx.Request.Body(), &request); err != nil {
+		log.Err(err).Error("could not parse JSON request body")
+
+		a.returnHTTPResponseJSON(ctx, fasthttp.StatusBadRequest,
+			types.Response{Error: err.Error()})
+
+		return
+	}
+
+	user, err := applyAdminUserRequest(internaltypes.User{}, request)
+	if err != nil {
+		log.Err(err).Error("invalid user")
+
+		a.returnHTTPResponseJSON(ctx, fasthttp.StatusBadRequest,
+			types.Response{Error: err.Error()})
+
+		return
+	}
+
+	user, token := a.newUserToken(user)
+
+	log.Data("user", user).Debug("creating user")
+	if err := a.db.GetUserRepositoryWriter().Create(ctx, user); err != nil {
+		log.Err(err).Error("error when creating user")
+
+		a.returnHTTPResponseJSON(ctx, fasthttp.StatusInternalServerError,
+			types.Response{Error: err.Error()})
+
+		return
+	}
+
+	a.returnHTTPResponseJSON(ctx, fasthttp.StatusCreated, types.AdminUserCreateResponse{
+		User:          adminUserFromUser(user),
+		Token:         token,
+		SigningSecret: a.getUserSigningSecret(user),
+	})
+}
+
+// adminUpdateUserHTTPHandler replaces the user identified by the id path
+// parameter with the JSON request body and responds with the updated user.
+func (a *app) adminUpdateUserHTTPHandler(ctx *fasthttp.RequestCtx) {
+	log := glogger.New(glogger.Caller{
+		Service:  os.Getenv(serviceNameEnvVarName),
+		Package:  packageName,
+		Receiver: "app",
+		Function: "adminUpdateUserHTTPHandler",
+	})
+
+	user, ok := a.adminGetRequestedUser(ctx)
+	if !ok {
+		return
+	}
+
+	request := types.AdminUserRequest{}
+	if err := json.Unmarshal(ctx.Request.Body(), &request); err != nil {
+		log.Err(err).Error("could not parse JSON request body")
+
+		a.returnHTTPResponseJSON(ctx, fasthttp.StatusBadRequest,
+			types.Response{Error: err.Error()})
+
+		return
+	}
+
+	user, err := applyAdminUserRequest(user, request)
+	if err != nil {
+		log.Err(err).Error("invalid user")
+
+		a.returnHTTPResponseJSON(ctx, fasthttp.StatusBadRequest,
+			types.Response{Error: err.Error()})
+
+		return
+	}
+
+	log.Data("user", user).Debug("updating user")
+	if err := a.db.GetUserRepositoryWriter().Update(ctx, user); err != nil {
+		log.Err(err).Error("error when updating user")
+
+		a.returnHTTPResponseJSON(ctx, fasthttp.StatusInternalServerError,
+			types.Response{Error: err.Error()})
+
+		return
+	}
+
+	a.returnHTTPResponseJSON(ctx, fasthttp.StatusOK, adminUserFromUser(user))
+}
+
+// adminDeleteUserHTTPHandler deletes the user identified by the id path parameter.
+func (a *app) adminDeleteUserHTTPHandler(ctx *fasthttp.RequestCtx) {
+	log := glogger.New(glogger.Caller{
+		Service:  os.Getenv(serviceNameEnvVarName),
+		Package:  packageName,
+		Receiver: "app",
+		Function: "adminDeleteUserHTTPHandler",
+	})
+
+	user, ok := a.adminGetRequestedUser(ctx)
+	if !ok {
+		return
+	}
+
+	log.Data("id", user.ID).Debug("deleting user")
+	if err := a.db.GetUserRepositoryWriter().Delete(ctx, user.ID); err != nil {
+		log.Err(err).Error("error when deleting user")
+
+		a.returnHTTPResponseJSON(ctx, fasthttp.StatusInternalServerError,
+			types.Response{Error: err.Error()})
+
+		return
+	}
+
+	ctx.SetStatusCode(fasthttp.StatusNoContent)
+}
+
+// adminGetRequestedUser gets the user identified by the id path parameter.
+// If the user can't be retrieved, the error response is set and false
+// is returned.
+func (a *app) adminGetRequestedUser(ctx *fasthttp.RequestCtx) (internaltypes.User, bool) {
+	log := glogger.New(glogger.Caller{
+		Service:  os.Getenv(serviceNameEnvVarName),
+		Package:  packageName,
+		Receiver: "app",
+		Function: "adminGetRequestedUser",
+	})
+
+	id, _ := ctx.UserValue(adminUserIDUserValue).(string)
+
+	log.Data("id", id).Debug("getting user by id")
+	user, err := a.db.GetUserRepositoryReader().Get(ctx, id)
+	if err != nil {
+		log.Err(err).Error("there was an error when getting the user by ID")
+
+		if errors.Is(err, storage.ErrEmptyID) || errors.Is(err, storage.ErrNotFound) {
+			a.returnHTTPResponseJSON(ctx, fasthttp.StatusNotFound,
+				types.Response{Error: storage.ErrNotFound.Error()})
+
+			return user, false
+		}
+
+		a.returnHTTPResponseJSON(ctx, fasthttp.StatusInternalServerError,
+			types.Response{Error: err.Error()})
+
+		return user, false
+	}
+
+	return user, true
+}
+
+// adminUserFromUser converts a user to its admin API representation.
+func adminUserFromUser(user internaltypes.User) types.AdminUser {
+	status := user.Status
+	if status == "" {
+		status = internaltypes.UserStatusActive
+	}
+
+	adminUser := types.AdminUser{
+		ID:             user.ID,
+		TelegramChatID: user.TelegramChatID,
+		ChatTitle:      user.ChatTitle,
+		Status:         string(status),
+		ExpiresAt:      user.ExpiresAt,
+		Scopes: types.AdminUserScopes{
+			Levels:    user.Scopes.Levels,
+			Endpoints: user.Scopes.Endpoints,
+		},
+		AllowedCIDRs: user.AllowedCIDRs,
+	}
+
+	for _, permission := range user.Scopes.Permissions {
+		adminUser.Scopes.Permissions = append(adminUser.Scopes.Permissions, string(permission))
+	}
+
+	return adminUser
+}
+
+// applyAdminUserRequest validates the given admin API request and
+// replaces the given user's fields with the ones in the request.
+//
+//nolint:cyclop
+func applyAdminUserRequest(user internaltypes.User, request types.AdminUserRequest) (internaltypes.User, error) {
+	if request.TelegramChatID == 0 {
+		return user, fmt.Errorf("%w: %s", ErrInvalidUser, storage.ErrEmptyTelegramChatID)
+	}
+
+	if request.ExpiresAt == nil || user.ExpiresAt == nil || !request.ExpiresAt.Equal(*user.ExpiresAt) {
+		user.ExpiryWarningSent = false
+	}
+
+	user.TelegramChatID = request.TelegramChatID
+	user.ExpiresAt = request.ExpiresAt
+	user.Scopes = internaltypes.Scopes{}
+	user.AllowedCIDRs = nil
+
+	for _, p := range request.Scopes.Permissions {
+		permission := internaltypes.Permission(p)
+		if !isKnownPermission(permission) {
+			return user, fmt.Errorf("%w: unknown permission %s", ErrInvalidUser, p)
+		}
+
+		user.Scopes.Permissions = append(user.Scopes.Permissions, permission)
+	}
+
+	for _, l := range request.Scopes.Levels {
+		level := getLogLevelName(l)
+		if level == "" {
+			return user, fmt.Errorf("%w: unknown level %s", ErrInvalidUser, l)
+		}
+
+		user.Scopes.Levels = append(user.Scopes.Levels, level)
+	}
+
+	for _, endpoint := range request.Scopes.Endpoints {
+		if !strings.HasPrefix(endpoint, "/") {
+			return user, fmt.Errorf("%w: invalid endpoint %s", ErrInvalidUser, endpoint)
+		}
+
+		user.Scopes.Endpoints = append(user.Scopes.Endpoints, endpoint)
+	}
+
+	for _, cidr := range request.AllowedCIDRs {
+		if _, _, err := net.ParseCIDR(cidr); err != nil {
+			return user, fmt.Errorf("%w: invalid CIDR %s", ErrInvalidUser, cidr)
+		}
+
+		user.AllowedCIDRs = append(user.AllowedCIDRs, cidr)
+	}
+
+	return user, nil
+}
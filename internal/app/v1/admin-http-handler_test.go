@@ -0,0 +1,208 @@
+package v1
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"testing"
+
+	"github.com/psyb0t/telegram-logger/internal/pkg/storage"
+	"github.com/psyb0t/telegram-logger/internal/pkg/storage/badgerdb"
+	internaltypes "github.com/psyb0t/telegram-logger/internal/pkg/types"
+	"github.com/psyb0t/telegram-logger/pkg/types"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/require"
+	"github.com/valyala/fasthttp"
+)
+
+func newAdminTestApp(adminToken string) (*app, *storage.UserRepositoryReaderMock, *storage.UserRepositoryWriterMock) {
+	db := storage.NewMock()
+	userRepositoryReader, _ := db.GetUserRepositoryReader().(*storage.UserRepositoryReaderMock)
+	userRepositoryWriter, _ := db.GetUserRepositoryWriter().(*storage.UserRepositoryWriterMock)
+
+	a := &app{
+		config: config{
+			Auth:  authConfig{TokenHashKey: "key"},
+			Admin: adminConfig{Token: adminToken},
+		},
+		db: db,
+	}
+
+	return a, userRepositoryReader, userRepositoryWriter
+}
+
+func doAdminTestRequest(a *app, method, uri, adminToken, body string) *fasthttp.RequestCtx {
+	ctx := &fasthttp.RequestCtx{}
+	ctx.Request.Header.SetMethod(method)
+	ctx.Request.SetRequestURI(uri)
+	ctx.Request.SetBodyString(body)
+
+	if adminToken != "" {
+		ctx.Request.Header.Set(headerNameAuthorization, authorizationBearer+adminToken)
+	}
+
+	a.getHTTPRequestHandler()(ctx)
+
+	return ctx
+}
+
+func TestApp_adminAuthHTTPMiddleware(t *testing.T) {
+	t.Run("admin API disabled", func(t *testing.T) {
+		a, _, _ := newAdminTestApp("")
+
+		ctx := doAdminTestRequest(a, fasthttp.MethodGet, "/admin/users", "", "")
+		assert.Equal(t, fasthttp.StatusNotFound, ctx.Response.StatusCode())
+	})
+
+	t.Run("no admin token", func(t *testing.T) {
+		a, _, _ := newAdminTestApp("admin")
+
+		ctx := doAdminTestRequest(a, fasthttp.MethodGet, "/admin/users", "", "")
+		assert.Equal(t, fasthttp.StatusUnauthorized, ctx.Response.StatusCode())
+	})
+
+	t.Run("wrong admin token", func(t *testing.T) {
+		a, _, _ := newAdminTestApp("admin")
+
+		ctx := doAdminTestRequest(a, fasthttp.MethodGet, "/admin/users", "wrong", "")
+		assert.Equal(t, fasthttp.StatusUnauthorized, ctx.Response.StatusCode())
+	})
+}
+
+func TestApp_adminGetAllUsersHTTPHandler(t *testing.T) {
+	a, userRepositoryReader, _ := newAdminTestApp("admin")
+	userRepositoryReader.On("GetAll").Return([]internaltypes.User{
+		{ID: "a", TelegramChatID: 1},
+		{ID: "b", TelegramChatID: 2, Status: internaltypes.UserStatusInactive, Scopes: internaltypes.Scopes{
+			Permissions: []internaltypes.Permission{internaltypes.PermissionIngest},
+		}},
+	}, nil)
+
+	ctx := doAdminTestRequest(a, fasthttp.MethodGet, "/admin/users", "admin", "")
+	assert.Equal(t, fasthttp.StatusOK, ctx.Response.StatusCode())
+
+	response := types.AdminUserListResponse{}
+	assert.NoError(t, json.Unmarshal(ctx.Response.Body(), &response))
+	assert.Equal(t, []types.AdminUser{
+		{ID: "a", TelegramChatID: 1, Status: "active"},
+		{ID: "b", TelegramChatID: 2, Status: "inactive", Scopes: types.AdminUserScopes{Permissions: []string{"ingest"}}},
+	}, response.Users)
+}
+
+func TestApp_adminGetUserHTTPHandler(t *testing.T) {
+	a, userRepositoryReader, _ := newAdminTestApp("admin")
+	userRepositoryReader.On("Get", "a").Return(internaltypes.User{ID: "a", TelegramChatID: 1}, nil)
+	userRepositoryReader.On("Get", "b").Return(internaltypes.User{}, storage.ErrNotFound)
+
+	ctx := doAdminTestRequest(a, fasthttp.MethodGet, "/admin/users/a", "admin", "")
+	assert.Equal(t, fasthttp.StatusOK, ctx.Response.StatusCode())
+	assert.JSONEq(t, `{"id":"a","telegramChatID":1,"status":"active","scopes":{}}`, string(ctx.Response.Body()))
+
+	ctx = doAdminTestRequest(a, fasthttp.MethodGet, "/admin/users/b", "admin", "")
+	assert.Equal(t, fasthttp.StatusNotFound, ctx.Response.StatusCode())
+}
+
+func TestApp_adminCreateUserHTTPHandler(t *testing.T) {
+	a, _, userRepositoryWriter := newAdminTestApp("admin")
+	userRepositoryWriter.On("Create", mock.Anything).Return(nil)
+
+	ctx := doAdminTestRequest(a, fasthttp.MethodPost, "/admin/users", "admin",
+		`{"telegramChatID":1,"scopes":{"levels":["ERR"]},"allowedCIDRs":["10.0.0.0/8"]}`)
+	assert.Equal(t, fasthttp.StatusCreated, ctx.Response.StatusCode())
+
+	response := types.AdminUserCreateResponse{}
+	assert.NoError(t, json.Unmarshal(ctx.Response.Body(), &response))
+	assert.Equal(t, hashToken("key", response.Token), response.User.ID)
+	assert.Equal(t, []string{"error"}, response.User.Scopes.Levels)
+	assert.NotEmpty(t, response.SigningSecret)
+
+	userRepositoryWriter.AssertCalled(t, "Create", internaltypes.User{
+		ID:             response.User.ID,
+		TelegramChatID: 1,
+		Scopes:         internaltypes.Scopes{Levels: []string{"error"}},
+		AllowedCIDRs:   []string{"10.0.0.0/8"},
+	})
+
+	ctx = doAdminTestRequest(a, fasthttp.MethodPost, "/admin/users", "admin", `{"telegramChatID":0}`)
+	assert.Equal(t, fasthttp.StatusBadRequest, ctx.Response.StatusCode())
+
+	ctx = doAdminTestRequest(a, fasthttp.MethodPost, "/admin/users", "admin", `{`)
+	assert.Equal(t, fasthttp.StatusBadRequest, ctx.Response.StatusCode())
+}
+
+func TestApp_adminUpdateUserHTTPHandler(t *testing.T) {
+	a, userRepositoryReader, userRepositoryWriter := newAdminTestApp("admin")
+	userRepositoryReader.On("Get", "a").Return(internaltypes.User{ID: "a", TelegramChatID: 1}, nil)
+	userRepositoryWriter.On("Update", mock.Anything).Return(nil)
+
+	ctx := doAdminTestRequest(a, fasthttp.MethodPut, "/admin/users/a", "admin",
+		`{"telegramChatID":2,"scopes":{"permissions":["ingest"]}}`)
+	assert.Equal(t, fasthttp.StatusOK, ctx.Response.StatusCode())
+
+	userRepositoryWriter.AssertCalled(t, "Update", internaltypes.User{
+		ID:             "a",
+		TelegramChatID: 2,
+		Scopes: internaltypes.Scopes{
+			Permissions: []internaltypes.Permission{internaltypes.PermissionIngest},
+		},
+	})
+
+	ctx = doAdminTestRequest(a, fasthttp.MethodPut, "/admin/users/a", "admin",
+		`{"telegramChatID":2,"scopes":{"permissions":["everything"]}}`)
+	assert.Equal(t, fasthttp.StatusBadRequest, ctx.Response.StatusCode())
+}
+
+func TestApp_adminDeleteUserHTTPHandler(t *testing.T) {
+	a, userRepositoryReader, userRepositoryWriter := newAdminTestApp("admin")
+	userRepositoryReader.On("Get", "a").Return(internaltypes.User{ID: "a", TelegramChatID: 1}, nil)
+	userRepositoryReader.On("Get", "b").Return(internaltypes.User{}, storage.ErrNotFound)
+	userRepositoryWriter.On("Delete", "a").Return(nil)
+
+	ctx := doAdminTestRequest(a, fasthttp.MethodDelete, "/admin/users/a", "admin", "")
+	assert.Equal(t, fasthttp.StatusNoContent, ctx.Response.StatusCode())
+	userRepositoryWriter.AssertCalled(t, "Delete", "a")
+
+	ctx = doAdminTestRequest(a, fasthttp.MethodDelete, "/admin/users/b", "admin", "")
+	assert.Equal(t, fasthttp.StatusNotFound, ctx.Response.StatusCode())
+}
+
+func TestApp_adminBackupHTTPHandler(t *testing.T) {
+	t.Run("storage without backups", func(t *testing.T) {
+		a, _, _ := newAdminTestApp("admin")
+
+		ctx := doAdminTestRequest(a, fasthttp.MethodGet, "/admin/backup", "admin", "")
+		assert.Equal(t, fasthttp.StatusNotImplemented, ctx.Response.StatusCode())
+	})
+
+	t.Run("badgerDB", func(t *testing.T) {
+		bgCtx := context.Background()
+
+		db, err := badgerdb.New(bgCtx, badgerdb.Config{})
+		require.NoError(t, err)
+		require.NoError(t, db.Open(bgCtx, t.TempDir()))
+
+		defer db.Close()
+
+		user := internaltypes.User{ID: "a", TelegramChatID: 1}
+		require.NoError(t, db.GetUserRepositoryWriter().Create(bgCtx, user))
+
+		a := &app{ctx: bgCtx, config: config{Admin: adminConfig{Token: "admin"}}, db: db}
+
+		ctx := doAdminTestRequest(a, fasthttp.MethodGet, "/admin/backup", "admin", "")
+		assert.Equal(t, fasthttp.StatusOK, ctx.Response.StatusCode())
+
+		restored, err := badgerdb.New(bgCtx, badgerdb.Config{})
+		require.NoError(t, err)
+		require.NoError(t, restored.Open(bgCtx, t.TempDir()))
+
+		defer restored.Close()
+
+		backuper, _ := restored.(storage.Backuper)
+		require.NoError(t, backuper.Restore(bgCtx, bytes.NewReader(ctx.Response.Body())))
+
+		users, err := restored.GetUserRepositoryReader().GetAll(bgCtx)
+		require.NoError(t, err)
+		assert.Equal(t, []internaltypes.User{user}, users)
+	})
+}
@@ -10,11 +10,16 @@ import (
 	"github.com/psyb0t/glogger"
 	"github.com/psyb0t/telegram-logger/internal/pkg/storage"
 	"github.com/psyb0t/telegram-logger/internal/pkg/storage/badgerdb"
+	"github.com/psyb0t/telegram-logger/internal/pkg/storage/memory"
+	"github.com/psyb0t/telegram-logger/internal/pkg/storage/postgres"
+	"github.com/psyb0t/telegram-logger/internal/pkg/storage/redisdb"
+	"github.com/psyb0t/telegram-logger/internal/pkg/storage/sqlite"
 	"github.com/valyala/fasthttp"
 )
 
 // app contains the context, cancel function, config, HTTP server,
-// Telegram bot API, and database connection for the app.
+// Telegram bot API, database connection and the caches
+// used by the request handlers for the app.
 type app struct {
 	ctx            context.Context //nolint:containedctx
 	cancelFunc     context.CancelFunc
@@ -22,6 +27,13 @@ type app struct {
 	httpServer     fasthttp.Server
 	telegramBotAPI *tgbotapi.BotAPI
 	db             storage.Storage
+	nonceCache     *seenCache
+	// rejectedIPNotifications rate limits the rejected IP notifications
+	rejectedIPNotifications *seenCache
+	// telegramBotWebhookPath is the path the telegram bot webhook is
+	// served on. It's empty if updates are received by long polling
+	telegramBotWebhookPath    string
+	telegramBotWebhookUpdates chan tgbotapi.Update
 }
 
 // newApp creates a new app struct and initializes the Telegram
@@ -33,12 +45,36 @@ func newApp(parentCtx context.Context, cfg config) (*app, error) {
 		Function: "newApp",
 	})
 
+	if cfg.Auth.TokenHashKey == "" {
+		log.Err(ErrEmptyTokenHashKey).Error("the token hash key must be set")
+
+		return nil, ErrEmptyTokenHashKey
+	}
+
 	ctx, cancelFunc := context.WithCancel(parentCtx)
 
 	a := &app{
 		ctx:        ctx,
 		cancelFunc: cancelFunc,
 		config:     cfg,
+		// a timestamp is valid within auth.signedRequestMaxAge in
+		// both directions so nonces are kept for twice as long
+		nonceCache:              newSeenCache(2 * cfg.Auth.SignedRequestMaxAge),
+		rejectedIPNotifications: newSeenCache(rejectedIPNotificationInterval),
+	}
+
+	if cfg.TelegramBot.Webhook.URL != "" {
+		var err error
+		a.telegramBotWebhookPath, err = getTelegramBotWebhookPath(cfg.TelegramBot.Webhook.URL)
+		if err != nil {
+			log.Err(err).Error("invalid telegram bot webhook URL")
+
+			cancelFunc()
+
+			return nil, err
+		}
+
+		a.telegramBotWebhookUpdates = make(chan tgbotapi.Update, telegramBotWebhookUpdatesBufferSize)
 	}
 
 	log.Info("setting up the telegram bot connection")
@@ -79,19 +115,53 @@ func newApp(parentCtx context.Context, cfg config) (*app, error) {
 // storage type in the config.
 func (a *app) setupDatabase() error {
 	var err error
+	a.db, err = newStorage(a.ctx, a.config.Storage)
 
-	switch a.config.Storage.Type {
+	return err
+}
+
+// newStorage creates a storage of the type set in the given config.
+func newStorage(ctx context.Context, cfg storageConfig) (storage.Storage, error) {
+	switch cfg.Type {
 	case storageTypeBadgerDB:
-		a.db, err = badgerdb.New(a.ctx)
+		badgerDBConfig, err := newBadgerDBConfig(cfg.BadgerDB)
+		if err != nil {
+			return nil, err
+		}
+
+		return badgerdb.New(ctx, badgerDBConfig)
+	case storageTypeSQLite:
+		return sqlite.New(ctx)
+	case storageTypePostgres:
+		return postgres.New(ctx)
+	case storageTypeRedis:
+		return redisdb.New(ctx)
+	case storageTypeMemory:
+		return memory.New(ctx)
 	default:
-		return ErrUnsupportedStorageType
+		return nil, ErrUnsupportedStorageType
 	}
+}
 
-	return err
+// newBadgerDBConfig returns the config of a badgerDB storage,
+// reading the encryption key from its file if one is set.
+func newBadgerDBConfig(cfg storageBadgerDBConfig) (badgerdb.Config, error) {
+	encryptionKey, err := cfg.getEncryptionKey()
+	if err != nil {
+		return badgerdb.Config{}, err
+	}
+
+	return badgerdb.Config{
+		GCInterval:      cfg.GCInterval,
+		FlattenInterval: cfg.FlattenInterval,
+		EncryptionKey:   encryptionKey,
+		IndexCacheSize:  cfg.IndexCacheSize,
+	}, nil
 }
 
 // start starts the app by opening the database connection and starting the
-// HTTP server and Telegram bot message handler in separate goroutines.
+// HTTP server, Telegram bot message handler, token expiry notifier and
+// storage stats logger in separate goroutines.
 // It waits for either the context to be cancelled or for one of the goroutines
 // to return an error. If the context is cancelled, it sets the error to the
 // context's error. If one of the goroutines returns an error, it sets the
@@ -105,12 +175,34 @@ func (a *app) start() error {
 	})
 
 	log.Info("opening the database connection")
-	if err := a.db.Open(a.config.Storage.BadgerDB.DSN); err != nil {
+	if err := a.db.Open(a.ctx, a.config.Storage.getDSN()); err != nil {
 		log.Err(err).Error(ErrUnableToOpenDatabaseConnection.Error())
 
 		return ErrUnableToOpenDatabaseConnection
 	}
 
+	log.Info("migrating user tokens")
+	if err := a.migrateUserTokens(a.ctx); err != nil {
+		log.Err(err).Error("error when migrating user tokens")
+
+		if err := a.db.Close(); err != nil {
+			log.Err(err).Error("error when closing the database connection")
+		}
+
+		return err
+	}
+
+	log.Info("seeding roles")
+	if err := a.seedRoles(a.ctx); err != nil {
+		log.Err(err).Error("error when seeding roles")
+
+		if err := a.db.Close(); err != nil {
+			log.Err(err).Error("error when closing the database connection")
+		}
+
+		return err
+	}
+
 	var wg sync.WaitGroup
 
 	httpServerErrCh := make(chan error, 1)
@@ -121,6 +213,12 @@ func (a *app) start() error {
 	wg.Add(1)
 	go a.startTelegramBotMessageHandler(&wg, telegramBotMessageHandlerErrCh)
 
+	wg.Add(1)
+	go a.startTokenExpiryNotifier(&wg)
+
+	wg.Add(1)
+	go a.startStorageStatsLogger(&wg)
+
 	var err error
 	select {
 	case <-a.ctx.Done():
@@ -1 +1,106 @@
 package v1
+
+import (
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"strconv"
+	"strings"
+	"sync"
+	"testing"
+
+	tgbotapi "github.com/go-telegram-bot-api/telegram-bot-api/v5"
+	"github.com/stretchr/testify/require"
+)
+
+// testTelegramBotMessage is a message sent to the fake Telegram bot API.
+type testTelegramBotMessage struct {
+	ChatID int64
+	Text   string
+}
+
+// testTelegramBotAPI is a fake Telegram bot API which records the sent
+// messages and fails to send them with sendMessageErr if it's set.
+type testTelegramBotAPI struct {
+	mu             sync.Mutex
+	sentMessages   []testTelegramBotMessage
+	sendMessageErr *tgbotapi.Error
+}
+
+// newTestTelegramBotAPI starts a fake Telegram bot API server
+// and returns a bot using it along with the fake.
+func newTestTelegramBotAPI(t *testing.T) (*tgbotapi.BotAPI, *testTelegramBotAPI) {
+	t.Helper()
+
+	fake := &testTelegramBotAPI{}
+
+	server := httptest.NewServer(http.HandlerFunc(fake.serveHTTP))
+	t.Cleanup(server.Close)
+
+	bot, err := tgbotapi.NewBotAPIWithAPIEndpoint("token", server.URL+"/bot%s/%s")
+	require.NoError(t, err)
+
+	return bot, fake
+}
+
+// getSentMessages returns the messages sent so far.
+func (f *testTelegramBotAPI) getSentMessages() []testTelegramBotMessage {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+
+	return append([]testTelegramBotMessage{}, f.sentMessages...)
+}
+
+func (f *testTelegramBotAPI) serveHTTP(w http.ResponseWriter, r *http.Request) {
+	if err := r.ParseForm(); err != nil {
+		http.Error(w, err.Error(), http.StatusBadRequest)
+
+		return
+	}
+
+	response := map[string]interface{}{"ok": true, "result": true}
+
+	switch {
+	case strings.HasSuffix(r.URL.Path, "/getMe"):
+		response["result"] = map[string]interface{}{"id": 1, "is_bot": true, "username": "test_bot"}
+	case strings.HasSuffix(r.URL.Path, "/editMessageText"):
+		chatID, _ := strconv.ParseInt(r.PostForm.Get("chat_id"), 10, 64)
+
+		response["result"] = map[string]interface{}{
+			"message_id": 1,
+			"date":       0,
+			"chat":       map[string]interface{}{"id": chatID},
+		}
+	case strings.HasSuffix(r.URL.Path, "/sendMessage"):
+		chatID, _ := strconv.ParseInt(r.PostForm.Get("chat_id"), 10, 64)
+
+		f.mu.Lock()
+		sendMessageErr := f.sendMessageErr
+		if sendMessageErr == nil {
+			f.sentMessages = append(f.sentMessages, testTelegramBotMessage{
+				ChatID: chatID,
+				Text:   r.PostForm.Get("text"),
+			})
+		}
+		f.mu.Unlock()
+
+		if sendMessageErr != nil {
+			response = map[string]interface{}{
+				"ok":          false,
+				"error_code":  sendMessageErr.Code,
+				"description": sendMessageErr.Message,
+			}
+
+			break
+		}
+
+		response["result"] = map[string]interface{}{
+			"message_id": 1,
+			"date":       0,
+			"chat":       map[string]interface{}{"id": chatID},
+		}
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	_ = json.NewEncoder(w).Encode(response)
+}
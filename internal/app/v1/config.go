@@ -2,6 +2,7 @@ package v1
 
 import (
 	"os"
+	"time"
 
 	"github.com/go-playground/validator/v10"
 	configparser "github.com/psyb0t/go-config-parser"
@@ -12,26 +13,117 @@ const (
 	defaultListenAddress = "0.0.0.0:80"
 	defaultLogLevel      = "debug"
 	defaultLogFormat     = "json"
+
+	defaultTokenExpiryWarning  = 72 * time.Hour
+	defaultSignedRequestMaxAge = 5 * time.Minute
+	defaultMaxUpdateAge        = 10 * time.Minute
+
+	defaultBadgerDBGCInterval      = 10 * time.Minute
+	defaultBadgerDBFlattenInterval = 24 * time.Hour
 )
 
 type storageType string
 
 const (
 	storageTypeBadgerDB storageType = "badgerDB"
+	storageTypeSQLite   storageType = "sqlite"
+	storageTypePostgres storageType = "postgres"
+	storageTypeRedis    storageType = "redis"
+	storageTypeMemory   storageType = "memory"
+)
+
+type registrationMode string
+
+const (
+	registrationModeOpen     registrationMode = "open"
+	registrationModeApproval registrationMode = "approval"
+	registrationModeClosed   registrationMode = "closed"
 )
 
 type storageBadgerDBConfig struct {
+	DSN               string        `yaml:"dsn"`
+	GCInterval        time.Duration `validate:"gte=0" yaml:"gcInterval"`
+	FlattenInterval   time.Duration `validate:"gte=0" yaml:"flattenInterval"`
+	EncryptionKey     string        `validate:"excluded_with=EncryptionKeyFile" yaml:"encryptionKey"`
+	EncryptionKeyFile string        `yaml:"encryptionKeyFile"`
+	IndexCacheSize    int64         `validate:"gte=0" yaml:"indexCacheSize"`
+}
+
+// getEncryptionKey returns the encryption key set in the config or the
+// contents of the key file, as they are. It's empty if neither is set.
+func (c storageBadgerDBConfig) getEncryptionKey() ([]byte, error) {
+	if c.EncryptionKeyFile == "" {
+		return []byte(c.EncryptionKey), nil
+	}
+
+	return os.ReadFile(c.EncryptionKeyFile)
+}
+
+type storageSQLiteConfig struct {
+	DSN string `yaml:"dsn"`
+}
+
+type storagePostgresConfig struct {
+	DSN string `yaml:"dsn"`
+}
+
+type storageRedisConfig struct {
+	DSN string `yaml:"dsn"`
+}
+
+type storageMemoryConfig struct {
 	DSN string `yaml:"dsn"`
 }
 
 type storageConfig struct {
 	Type     storageType           `validate:"required" yaml:"type"`
 	BadgerDB storageBadgerDBConfig `yaml:"badgerDB"`
+	SQLite   storageSQLiteConfig   `yaml:"sqlite"`
+	Postgres storagePostgresConfig `yaml:"postgres"`
+	Redis    storageRedisConfig    `yaml:"redis"`
+	Memory   storageMemoryConfig   `yaml:"memory"`
+}
+
+// getDSN returns the DSN configured for the storage type.
+func (c storageConfig) getDSN() string {
+	switch c.Type {
+	case storageTypeBadgerDB:
+		return c.BadgerDB.DSN
+	case storageTypeSQLite:
+		return c.SQLite.DSN
+	case storageTypePostgres:
+		return c.Postgres.DSN
+	case storageTypeRedis:
+		return c.Redis.DSN
+	case storageTypeMemory:
+		return c.Memory.DSN
+	default:
+		return ""
+	}
+}
+
+type telegramBotWebhookConfig struct {
+	URL         string `validate:"omitempty,url" yaml:"url"`
+	SecretToken string `validate:"required_with=URL,max=256" yaml:"secretToken"`
 }
 
 type telegramBotConfig struct {
-	Token           string `yaml:"token"`
-	SuperuserChatID int64  `yaml:"superuserChatID"`
+	Token            string                   `yaml:"token"`
+	SuperuserChatID  int64                    `yaml:"superuserChatID"`
+	AdminChatIDs     []int64                  `yaml:"adminChatIDs"`
+	RegistrationMode registrationMode         `validate:"oneof=open approval closed" yaml:"registrationMode"`
+	Webhook          telegramBotWebhookConfig `yaml:"webhook"`
+	MaxUpdateAge     time.Duration            `validate:"gte=0" yaml:"maxUpdateAge"`
+}
+
+type authConfig struct {
+	TokenHashKey        string        `yaml:"tokenHashKey"`
+	TokenExpiryWarning  time.Duration `yaml:"tokenExpiryWarning"`
+	SignedRequestMaxAge time.Duration `yaml:"signedRequestMaxAge"`
+}
+
+type adminConfig struct {
+	Token string `yaml:"token"`
 }
 
 type loggerConfig struct {
@@ -40,10 +132,13 @@ type loggerConfig struct {
 }
 
 type config struct {
-	ListenAddress string            `validate:"hostname_port" yaml:"listenAddress"`
-	Logger        loggerConfig      `yaml:"logger"`
-	TelegramBot   telegramBotConfig `yaml:"telegramBot"`
-	Storage       storageConfig     `yaml:"storage"`
+	ListenAddress    string            `validate:"hostname_port" yaml:"listenAddress"`
+	TrustedProxyHops int               `validate:"gte=0" yaml:"trustedProxyHops"`
+	Logger           loggerConfig      `yaml:"logger"`
+	Auth             authConfig        `yaml:"auth"`
+	Admin            adminConfig       `yaml:"admin"`
+	TelegramBot      telegramBotConfig `yaml:"telegramBot"`
+	Storage          storageConfig     `yaml:"storage"`
 }
 
 // newConfig reads and parses the configuration file and returns a config
@@ -57,20 +152,53 @@ func newConfig() (config, error) {
 	configFile := os.Getenv(configFileEnvVarName)
 
 	defaults := map[string]interface{}{
-		"listenAddress": defaultListenAddress,
+		"listenAddress":    defaultListenAddress,
+		"trustedProxyHops": 0,
 		"logger": map[string]interface{}{
 			"level":  defaultLogLevel,
 			"format": defaultLogFormat,
 		},
+		"auth": map[string]interface{}{
+			"tokenHashKey":        "",
+			"tokenExpiryWarning":  defaultTokenExpiryWarning,
+			"signedRequestMaxAge": defaultSignedRequestMaxAge,
+		},
+		"admin": map[string]interface{}{
+			"token": "",
+		},
 		"storage": map[string]interface{}{
 			"type": storageTypeBadgerDB,
 			"badgerDB": map[string]interface{}{
+				"dsn":               "",
+				"gcInterval":        defaultBadgerDBGCInterval,
+				"flattenInterval":   defaultBadgerDBFlattenInterval,
+				"encryptionKey":     "",
+				"encryptionKeyFile": "",
+				"indexCacheSize":    0,
+			},
+			"sqlite": map[string]interface{}{
+				"dsn": "",
+			},
+			"postgres": map[string]interface{}{
+				"dsn": "",
+			},
+			"redis": map[string]interface{}{
+				"dsn": "",
+			},
+			"memory": map[string]interface{}{
 				"dsn": "",
 			},
 		},
 		"telegramBot": map[string]interface{}{
-			"token":           "",
-			"superuserChatID": 0,
+			"token":            "",
+			"superuserChatID":  0,
+			"adminChatIDs":     []int64{},
+			"registrationMode": registrationModeOpen,
+			"maxUpdateAge":     defaultMaxUpdateAge,
+			"webhook": map[string]interface{}{
+				"url":         "",
+				"secretToken": "",
+			},
 		},
 	}
 
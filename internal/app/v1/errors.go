@@ -7,9 +7,52 @@ var (
 	ErrUnableToOpenDatabaseConnection = errors.New("error when opening database connection")
 	// ErrUnsupportedStorageType is returned when an unsupported storage type is used.
 	ErrUnsupportedStorageType = errors.New("unsupported storage type")
-	// ErrUnauthorizedToUseTelegramBotCommand is returned when the trying
-	// to use the telegram bot command is not a superuser.
+	// ErrBackupNotSupported is returned when the configured storage can't be backed up and restored.
+	ErrBackupNotSupported = errors.New("the storage doesn't support backups")
+	// ErrStorageNotEmpty is returned when restoring or importing into a storage that already has data.
+	ErrStorageNotEmpty = errors.New("the storage is not empty")
+	// ErrUnauthorizedToUseTelegramBotCommand is returned when the chat trying
+	// to use the telegram bot command doesn't have the required role.
 	ErrUnauthorizedToUseTelegramBotCommand = errors.New("unauthorized to use command")
+	// ErrEmptyTokenHashKey is returned when no key is configured for hashing user tokens.
+	ErrEmptyTokenHashKey = errors.New("empty token hash key")
+	// ErrInvalidUserOption is returned when a user option can't be parsed.
+	ErrInvalidUserOption = errors.New("invalid user option")
+	// ErrInvalidToken is returned when the request doesn't carry a token.
+	ErrInvalidToken = errors.New("invalid token")
+	// ErrInvalidSignature is returned when a signed request's signature, timestamp or nonce is not valid.
+	ErrInvalidSignature = errors.New("invalid signature")
+	// ErrPermissionNotGranted is returned when the token's scopes don't grant the needed permission.
+	ErrPermissionNotGranted = errors.New("permission not granted")
+	// ErrEndpointNotAllowed is returned when the token's scopes don't allow the endpoint.
+	ErrEndpointNotAllowed = errors.New("endpoint not allowed")
+	// ErrLevelNotAllowed is returned when the token's scopes don't allow the log level.
+	ErrLevelNotAllowed = errors.New("log level not allowed")
+	// ErrInvalidUser is returned when a user sent to the admin API is not valid.
+	ErrInvalidUser = errors.New("invalid user")
+	// ErrUnableToGetTelegramChatRole is returned when the role of a chat can't be retrieved.
+	ErrUnableToGetTelegramChatRole = errors.New("unable to get chat role")
+	// ErrCannotChangeOwnerRole is returned when trying to change the role of an owner.
+	ErrCannotChangeOwnerRole = errors.New("the role of an owner can't be changed")
+	// ErrRegistrationRequestNotFound is returned when a chat has no pending registration request.
+	ErrRegistrationRequestNotFound = errors.New("registration request not found")
+	// ErrUnknownTelegramBotCallbackAction is returned when an inline keyboard button has an unknown action.
+	ErrUnknownTelegramBotCallbackAction = errors.New("unknown action")
+	// ErrTelegramBotCallbackQueryWithoutMessage is returned when the message
+	// of the pressed inline keyboard button is too old to be available.
+	ErrTelegramBotCallbackQueryWithoutMessage = errors.New("the message is no longer available")
+	// ErrInvalidInviteOption is returned when an invite option can't be parsed.
+	ErrInvalidInviteOption = errors.New("invalid invite option")
+	// ErrInviteNotFound is returned when there's no invite with the given code or ID.
+	ErrInviteNotFound = errors.New("invite not found")
+	// ErrInviteNotUsable is returned when an invite is revoked, expired or used up.
+	ErrInviteNotUsable = errors.New("invite is revoked, expired or used up")
+	// ErrInvalidTelegramBotWebhookURL is returned when the telegram bot webhook URL has no path.
+	ErrInvalidTelegramBotWebhookURL = errors.New("the telegram bot webhook URL must have a path")
+	// ErrEncryptionNotSupported is returned when rotating the encryption key of a storage other than badgerDB.
+	ErrEncryptionNotSupported = errors.New("only the badgerDB storage can be encrypted")
+	// ErrStorageStatsNotSupported is returned when the storage doesn't report statistics.
+	ErrStorageStatsNotSupported = errors.New("the storage doesn't report statistics")
 	// ErrInsufficientArguments is returned when there are not enough arguments to use the command.
 	ErrInsufficientArguments = errors.New("insufficient arguments")
 )
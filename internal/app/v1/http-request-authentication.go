@@ -0,0 +1,97 @@
+package v1
+
+import (
+	"crypto/hmac"
+	"os"
+	"strconv"
+	"time"
+
+	"github.com/psyb0t/glogger"
+	internaltypes "github.com/psyb0t/telegram-logger/internal/pkg/types"
+	"github.com/psyb0t/telegram-logger/pkg/types"
+	"github.com/valyala/fasthttp"
+)
+
+const (
+	headerNameXID = "X-ID"
+
+	signingSecretHashPrefix = "signing-secret:"
+)
+
+// authenticateHTTPRequest returns the user who sent the request. Requests
+// carrying a signature are authenticated by verifying it, the rest are
+// authenticated by the token in the X-ID header.
+func (a *app) authenticateHTTPRequest(ctx *fasthttp.RequestCtx, now time.Time) (internaltypes.User, error) {
+	if len(ctx.Request.Header.Peek(types.HeaderNameSignature)) > 0 {
+		return a.authenticateSignedHTTPRequest(ctx, now)
+	}
+
+	token := string(ctx.Request.Header.Peek(headerNameXID))
+	if token == "" {
+		return internaltypes.User{}, ErrInvalidToken
+	}
+
+	return a.db.GetUserRepositoryReader().Get(ctx, hashToken(a.config.Auth.TokenHashKey, token)) //nolint:wrapcheck
+}
+
+// authenticateSignedHTTPRequest verifies that the request was signed less
+// than auth.signedRequestMaxAge ago with the signing secret of the user
+// identified by the key ID header and that its nonce wasn't used before.
+func (a *app) authenticateSignedHTTPRequest(ctx *fasthttp.RequestCtx, now time.Time) (internaltypes.User, error) {
+	log := glogger.New(glogger.Caller{
+		Service:  os.Getenv(serviceNameEnvVarName),
+		Package:  packageName,
+		Receiver: "app",
+		Function: "authenticateSignedHTTPRequest",
+	})
+
+	keyID := string(ctx.Request.Header.Peek(types.HeaderNameKeyID))
+	timestamp := string(ctx.Request.Header.Peek(types.HeaderNameTimestamp))
+	nonce := string(ctx.Request.Header.Peek(types.HeaderNameNonce))
+	signature := string(ctx.Request.Header.Peek(types.HeaderNameSignature))
+
+	if keyID == "" || nonce == "" {
+		return internaltypes.User{}, ErrInvalidSignature
+	}
+
+	unixTimestamp, err := strconv.ParseInt(timestamp, 10, 64)
+	if err != nil {
+		log.Err(err).Debug("could not parse the timestamp")
+
+		return internaltypes.User{}, ErrInvalidSignature
+	}
+
+	age := now.Sub(time.Unix(unixTimestamp, 0))
+	if age > a.config.Auth.SignedRequestMaxAge || age < -a.config.Auth.SignedRequestMaxAge {
+		log.Data("age", age.String()).Debug("the timestamp is outside of the allowed window")
+
+		return internaltypes.User{}, ErrInvalidSignature
+	}
+
+	user, err := a.db.GetUserRepositoryReader().Get(ctx, keyID)
+	if err != nil {
+		return internaltypes.User{}, err //nolint:wrapcheck
+	}
+
+	expectedSignature := types.Signature(a.getUserSigningSecret(user), string(ctx.Method()),
+		string(ctx.Path()), timestamp, nonce, ctx.Request.Body())
+
+	if !hmac.Equal([]byte(signature), []byte(expectedSignature)) {
+		return internaltypes.User{}, ErrInvalidSignature
+	}
+
+	if !a.nonceCache.add(keyID+":"+nonce, now) {
+		log.Data("keyID", keyID).Debug("nonce already used")
+
+		return internaltypes.User{}, ErrInvalidSignature
+	}
+
+	return user, nil
+}
+
+// getUserSigningSecret returns the secret the given user signs requests
+// with. It is derived from the user ID and the token hash key so
+// it never needs to be stored.
+func (a *app) getUserSigningSecret(user internaltypes.User) string {
+	return hashToken(a.config.Auth.TokenHashKey, signingSecretHashPrefix+user.ID)
+}
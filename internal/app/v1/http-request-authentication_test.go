@@ -0,0 +1,118 @@
+package v1
+
+import (
+	"testing"
+	"time"
+
+	"github.com/psyb0t/telegram-logger/internal/pkg/storage"
+	internaltypes "github.com/psyb0t/telegram-logger/internal/pkg/types"
+	"github.com/psyb0t/telegram-logger/pkg/types"
+	"github.com/stretchr/testify/assert"
+	"github.com/valyala/fasthttp"
+)
+
+func newAuthenticationTestApp(user internaltypes.User) *app {
+	db := storage.NewMock()
+	userRepositoryReader, _ := db.GetUserRepositoryReader().(*storage.UserRepositoryReaderMock)
+	userRepositoryReader.On("Get", user.ID).Return(user, nil)
+	userRepositoryReader.On("Get", "unknown").Return(internaltypes.User{}, storage.ErrNotFound)
+
+	return &app{
+		config: config{
+			Auth: authConfig{
+				TokenHashKey:        "key",
+				SignedRequestMaxAge: time.Minute,
+			},
+		},
+		db:         db,
+		nonceCache: newSeenCache(2 * time.Minute),
+	}
+}
+
+func newSignedRequestCtx(t *testing.T, keyID, signingSecret string, signedAt time.Time) *fasthttp.RequestCtx {
+	t.Helper()
+
+	signed, err := types.Request{Message: "hello"}.Sign(keyID, signingSecret, fasthttp.MethodPost, "/", signedAt)
+	assert.NoError(t, err)
+
+	ctx := &fasthttp.RequestCtx{}
+	ctx.Request.Header.SetMethod(fasthttp.MethodPost)
+	ctx.Request.SetRequestURI("/")
+	ctx.Request.SetBody(signed.Body)
+
+	for name, value := range signed.Headers {
+		ctx.Request.Header.Set(name, value)
+	}
+
+	return ctx
+}
+
+func TestApp_authenticateHTTPRequest(t *testing.T) {
+	now := time.Date(2023, 5, 1, 12, 0, 0, 0, time.UTC)
+	user := internaltypes.User{ID: hashToken("key", "token"), TelegramChatID: 1}
+	a := newAuthenticationTestApp(user)
+	signingSecret := a.getUserSigningSecret(user)
+
+	t.Run("token", func(t *testing.T) {
+		ctx := &fasthttp.RequestCtx{}
+		ctx.Request.Header.Set(headerNameXID, "token")
+
+		actual, err := a.authenticateHTTPRequest(ctx, now)
+		assert.NoError(t, err)
+		assert.Equal(t, user, actual)
+	})
+
+	t.Run("no token", func(t *testing.T) {
+		_, err := a.authenticateHTTPRequest(&fasthttp.RequestCtx{}, now)
+		assert.ErrorIs(t, err, ErrInvalidToken)
+	})
+
+	t.Run("signed", func(t *testing.T) {
+		ctx := newSignedRequestCtx(t, user.ID, signingSecret, now)
+
+		actual, err := a.authenticateHTTPRequest(ctx, now)
+		assert.NoError(t, err)
+		assert.Equal(t, user, actual)
+
+		// the same request can't be replayed
+		_, err = a.authenticateHTTPRequest(ctx, now)
+		assert.ErrorIs(t, err, ErrInvalidSignature)
+	})
+
+	t.Run("signed with the wrong secret", func(t *testing.T) {
+		ctx := newSignedRequestCtx(t, user.ID, "wrong", now)
+
+		_, err := a.authenticateHTTPRequest(ctx, now)
+		assert.ErrorIs(t, err, ErrInvalidSignature)
+	})
+
+	t.Run("signed too long ago", func(t *testing.T) {
+		ctx := newSignedRequestCtx(t, user.ID, signingSecret, now.Add(-2*time.Minute))
+
+		_, err := a.authenticateHTTPRequest(ctx, now)
+		assert.ErrorIs(t, err, ErrInvalidSignature)
+	})
+
+	t.Run("tampered body", func(t *testing.T) {
+		ctx := newSignedRequestCtx(t, user.ID, signingSecret, now)
+		ctx.Request.SetBody([]byte(`{"message":"bye"}`))
+
+		_, err := a.authenticateHTTPRequest(ctx, now)
+		assert.ErrorIs(t, err, ErrInvalidSignature)
+	})
+
+	t.Run("bad timestamp", func(t *testing.T) {
+		ctx := newSignedRequestCtx(t, user.ID, signingSecret, now)
+		ctx.Request.Header.Set(types.HeaderNameTimestamp, "yesterday")
+
+		_, err := a.authenticateHTTPRequest(ctx, now)
+		assert.ErrorIs(t, err, ErrInvalidSignature)
+	})
+
+	t.Run("unknown key ID", func(t *testing.T) {
+		ctx := newSignedRequestCtx(t, "unknown", signingSecret, now)
+
+		_, err := a.authenticateHTTPRequest(ctx, now)
+		assert.ErrorIs(t, err, storage.ErrNotFound)
+	})
+}
@@ -21,6 +21,18 @@ func (a *app) getHTTPRequestHandler() fasthttp.RequestHandler {
 	r := router.New()
 	r.POST("/", a.rootHTTPHandler)
 
+	admin := r.Group("/admin")
+	admin.GET("/backup", a.adminAuthHTTPMiddleware(a.adminBackupHTTPHandler))
+	admin.GET("/users", a.adminAuthHTTPMiddleware(a.adminGetAllUsersHTTPHandler))
+	admin.POST("/users", a.adminAuthHTTPMiddleware(a.adminCreateUserHTTPHandler))
+	admin.GET("/users/{id}", a.adminAuthHTTPMiddleware(a.adminGetUserHTTPHandler))
+	admin.PUT("/users/{id}", a.adminAuthHTTPMiddleware(a.adminUpdateUserHTTPHandler))
+	admin.DELETE("/users/{id}", a.adminAuthHTTPMiddleware(a.adminDeleteUserHTTPHandler))
+
+	if a.telegramBotWebhookPath != "" {
+		r.POST(a.telegramBotWebhookPath, a.telegramBotWebhookHTTPHandler)
+	}
+
 	return r.Handler
 }
 
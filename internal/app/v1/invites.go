@@ -0,0 +1,166 @@
+package v1
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/psyb0t/telegram-logger/internal/pkg/storage"
+	"github.com/psyb0t/telegram-logger/internal/pkg/types"
+)
+
+const (
+	inviteIDHashPrefix = "invite:"
+
+	inviteOptionUses    = "uses"
+	inviteOptionExpires = "expires"
+
+	defaultInviteMaxUses = 1
+	defaultInviteExpiry  = 7 * 24 * time.Hour
+)
+
+// getInviteID returns the ID of the invite with the given code. Like
+// tokens, invite codes are only stored as a hash.
+func (a *app) getInviteID(code string) string {
+	return hashToken(a.config.Auth.TokenHashKey, inviteIDHashPrefix+code)
+}
+
+// newInvite creates an invite created by the given chat from the given
+// key=value options and returns it along with its code. The supported
+// options are:
+//
+//	uses=5                        the number of times the invite can be used (default 1)
+//	expires=7d                    the invite expires after the given duration (default 7d)
+//
+// The rest of the options are applied to the users registering with
+// the invite (see applyUserOptions). now is the time the expiry is relative to.
+func (a *app) newInvite(createdBy int64, options []string, now time.Time) (types.Invite, string, error) {
+	expiresAt := now.Add(defaultInviteExpiry)
+	invite := types.Invite{
+		CreatedBy:   createdBy,
+		CreatedAt:   now,
+		ExpiresAt:   &expiresAt,
+		MaxUses:     defaultInviteMaxUses,
+		UserOptions: []string{},
+	}
+
+	for _, option := range options {
+		key, value, _ := strings.Cut(option, "=")
+
+		switch key {
+		case inviteOptionUses:
+			uses, err := strconv.Atoi(value)
+			if err != nil || uses <= 0 {
+				return invite, "", fmt.Errorf("%w: %s", ErrInvalidInviteOption, option)
+			}
+
+			invite.MaxUses = uses
+		case inviteOptionExpires:
+			expiry, err := parseDuration(value)
+			if err != nil || expiry <= 0 {
+				return invite, "", fmt.Errorf("%w: %s", ErrInvalidInviteOption, option)
+			}
+
+			expiresAt := now.Add(expiry)
+			invite.ExpiresAt = &expiresAt
+		default:
+			invite.UserOptions = append(invite.UserOptions, option)
+		}
+	}
+
+	// make sure the user options can be applied when the invite is used
+	if _, err := applyUserOptions(types.User{}, invite.UserOptions, now); err != nil {
+		return invite, "", err
+	}
+
+	code := generateToken()
+	invite.ID = a.getInviteID(code)
+
+	return invite, code, nil
+}
+
+// redeemInvite records the use of the invite with the given code by the
+// given user's chat and creates the user limited by the invite's user
+// options. Both happen in a single transaction so that an invite can't
+// be used more times than allowed. It returns ErrInviteNotFound if
+// there's no such invite and ErrInviteNotUsable if it's revoked,
+// expired or used up.
+func (a *app) redeemInvite(ctx context.Context, user types.User, code string, now time.Time) error {
+	user, token := a.newUserToken(user)
+
+	var limitedUser types.User
+
+	err := a.db.InTx(ctx, func(tx storage.Repositories) error {
+		invite, err := tx.GetInviteRepositoryReader().Get(ctx, a.getInviteID(code))
+		if err != nil {
+			if errors.Is(err, storage.ErrNotFound) {
+				return ErrInviteNotFound
+			}
+
+			return err //nolint:wrapcheck
+		}
+
+		if !invite.IsUsable(now) {
+			return ErrInviteNotUsable
+		}
+
+		limitedUser, err = applyUserOptions(user, invite.UserOptions, now)
+		if err != nil {
+			return err
+		}
+
+		invite.Uses = append(invite.Uses, types.InviteUse{TelegramChatID: user.TelegramChatID, UsedAt: now})
+		if err := tx.GetInviteRepositoryWriter().Update(ctx, invite); err != nil {
+			return err //nolint:wrapcheck
+		}
+
+		return replaceTelegramChatUsers(ctx, tx, limitedUser)
+	})
+	if err != nil {
+		return err //nolint:wrapcheck
+	}
+
+	return a.sendUserToken(ctx, limitedUser, token, limitedUser.TelegramChatID)
+}
+
+// revokeInvite marks the invite identified by the given ID or code as
+// revoked by the given chat. The invite is kept for auditing.
+func (a *app) revokeInvite(ctx context.Context, chatID int64, idOrCode string, now time.Time) (types.Invite, error) {
+	invite, err := a.findInvite(ctx, idOrCode)
+	if err != nil {
+		return invite, err
+	}
+
+	if invite.IsRevoked() {
+		return invite, nil
+	}
+
+	invite.RevokedAt = &now
+	invite.RevokedBy = chatID
+
+	if err := a.db.GetInviteRepositoryWriter().Update(ctx, invite); err != nil {
+		return invite, err //nolint:wrapcheck
+	}
+
+	return invite, nil
+}
+
+// findInvite returns the invite with the given code or, failing
+// that, the invite with the given ID.
+func (a *app) findInvite(ctx context.Context, idOrCode string) (types.Invite, error) {
+	for _, id := range []string{a.getInviteID(idOrCode), idOrCode} {
+		invite, err := a.db.GetInviteRepositoryReader().Get(ctx, id)
+		if err == nil {
+			return invite, nil
+		}
+
+		if !errors.Is(err, storage.ErrNotFound) {
+			return invite, err //nolint:wrapcheck
+		}
+	}
+
+	return types.Invite{}, ErrInviteNotFound
+}
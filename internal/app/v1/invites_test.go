@@ -0,0 +1,135 @@
+package v1
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/psyb0t/telegram-logger/internal/pkg/storage"
+	"github.com/psyb0t/telegram-logger/internal/pkg/types"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+)
+
+func newInvitesTestApp() (*app, *storage.InviteRepositoryReaderMock, *storage.InviteRepositoryWriterMock) {
+	db := storage.NewMock()
+	inviteRepositoryReader, _ := db.GetInviteRepositoryReader().(*storage.InviteRepositoryReaderMock)
+	inviteRepositoryWriter, _ := db.GetInviteRepositoryWriter().(*storage.InviteRepositoryWriterMock)
+
+	a := &app{
+		config: config{
+			Auth: authConfig{TokenHashKey: "key"},
+		},
+		db: db,
+	}
+
+	return a, inviteRepositoryReader, inviteRepositoryWriter
+}
+
+func TestApp_newInvite(t *testing.T) {
+	a, _, _ := newInvitesTestApp()
+	now := time.Now()
+
+	tests := []struct {
+		name              string
+		options           []string
+		expectedMaxUses   int
+		expectedExpiresAt time.Time
+		expectedOptions   []string
+		expectedErr       error
+	}{
+		{
+			name:              "defaults",
+			options:           nil,
+			expectedMaxUses:   1,
+			expectedExpiresAt: now.Add(defaultInviteExpiry),
+			expectedOptions:   []string{},
+		},
+		{
+			name:              "uses, expiry and user options",
+			options:           []string{"uses=5", "expires=1d", "ttl=30d", "permissions=ingest"},
+			expectedMaxUses:   5,
+			expectedExpiresAt: now.Add(24 * time.Hour),
+			expectedOptions:   []string{"ttl=30d", "permissions=ingest"},
+		},
+		{
+			name:        "invalid uses",
+			options:     []string{"uses=0"},
+			expectedErr: ErrInvalidInviteOption,
+		},
+		{
+			name:        "invalid expiry",
+			options:     []string{"expires=soon"},
+			expectedErr: ErrInvalidInviteOption,
+		},
+		{
+			name:        "invalid user option",
+			options:     []string{"permissions=everything"},
+			expectedErr: ErrInvalidUserOption,
+		},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			invite, code, err := a.newInvite(1, test.options, now)
+			assert.ErrorIs(t, err, test.expectedErr)
+
+			if test.expectedErr != nil {
+				return
+			}
+
+			assert.Equal(t, a.getInviteID(code), invite.ID)
+			assert.Equal(t, int64(1), invite.CreatedBy)
+			assert.Equal(t, test.expectedMaxUses, invite.MaxUses)
+			assert.Equal(t, test.expectedExpiresAt, *invite.ExpiresAt)
+			assert.Equal(t, test.expectedOptions, invite.UserOptions)
+		})
+	}
+}
+
+func TestApp_redeemInvite(t *testing.T) {
+	a, inviteRepositoryReader, _ := newInvitesTestApp()
+	now := time.Now()
+
+	inviteRepositoryReader.On("Get", a.getInviteID("unknown")).Return(types.Invite{}, storage.ErrNotFound)
+	inviteRepositoryReader.On("Get", a.getInviteID("usedUp")).Return(types.Invite{
+		MaxUses: 1,
+		Uses:    []types.InviteUse{{TelegramChatID: 2, UsedAt: now}},
+	}, nil)
+
+	user := types.User{TelegramChatID: 1}
+
+	assert.ErrorIs(t, a.redeemInvite(context.Background(), user, "unknown", now), ErrInviteNotFound)
+	assert.ErrorIs(t, a.redeemInvite(context.Background(), user, "usedUp", now), ErrInviteNotUsable)
+}
+
+func TestApp_revokeInvite(t *testing.T) {
+	ctx := context.Background()
+
+	a, inviteRepositoryReader, inviteRepositoryWriter := newInvitesTestApp()
+	now := time.Now()
+
+	invite := types.Invite{ID: a.getInviteID("code"), MaxUses: 1}
+
+	inviteRepositoryReader.On("Get", invite.ID).Return(invite, nil)
+	inviteRepositoryReader.On("Get", mock.Anything).Return(types.Invite{}, storage.ErrNotFound)
+	inviteRepositoryWriter.On("Update", mock.Anything).Return(nil)
+
+	t.Run("by code", func(t *testing.T) {
+		revokedInvite, err := a.revokeInvite(ctx, 1, "code", now)
+		assert.NoError(t, err)
+		assert.True(t, revokedInvite.IsRevoked())
+		assert.Equal(t, int64(1), revokedInvite.RevokedBy)
+	})
+
+	t.Run("by ID", func(t *testing.T) {
+		revokedInvite, err := a.revokeInvite(ctx, 1, invite.ID, now)
+		assert.NoError(t, err)
+		assert.True(t, revokedInvite.IsRevoked())
+	})
+
+	t.Run("unknown", func(t *testing.T) {
+		_, err := a.revokeInvite(ctx, 1, "unknown", now)
+		assert.ErrorIs(t, err, ErrInviteNotFound)
+	})
+}
@@ -0,0 +1,103 @@
+package v1
+
+import (
+	"context"
+	"fmt"
+	"net"
+	"os"
+	"strings"
+	"time"
+
+	"github.com/psyb0t/glogger"
+	"github.com/psyb0t/telegram-logger/internal/pkg/types"
+	"github.com/valyala/fasthttp"
+)
+
+const (
+	headerNameXForwardedFor = "X-Forwarded-For"
+
+	rejectedIPNotificationInterval = 10 * time.Minute
+
+	telegramBotRejectedIPMessageTpl = `⚠️ A request using an ID of this chat was rejected
+because it came from %s which is not in the allowed IP ranges.
+If you don't recognize it, the ID may have leaked.`
+)
+
+// getClientIP returns the IP of the client that sent the request. When
+// there are trusted proxies in front of the app, the client IP is the one
+// the furthest trusted proxy added to the X-Forwarded-For header.
+// It returns nil if the IP can't be parsed or if the header has fewer
+// entries than there are trusted proxies, since the first entry was then
+// set by whoever reached an inner proxy directly and can't be trusted.
+func getClientIP(ctx *fasthttp.RequestCtx, trustedProxyHops int) net.IP {
+	if trustedProxyHops <= 0 {
+		return ctx.RemoteIP()
+	}
+
+	forwardedFor := string(ctx.Request.Header.Peek(headerNameXForwardedFor))
+	if forwardedFor == "" {
+		return ctx.RemoteIP()
+	}
+
+	hops := strings.Split(forwardedFor, ",")
+
+	i := len(hops) - trustedProxyHops
+	if i < 0 {
+		return nil
+	}
+
+	return net.ParseIP(strings.TrimSpace(hops[i]))
+}
+
+// userAllowsIP checks if the given user's token can be used from the
+// given IP. Invalid CIDRs are ignored.
+func userAllowsIP(user types.User, ip net.IP) bool {
+	if len(user.AllowedCIDRs) == 0 {
+		return true
+	}
+
+	if ip == nil {
+		return false
+	}
+
+	for _, cidr := range user.AllowedCIDRs {
+		_, ipNet, err := net.ParseCIDR(cidr)
+		if err != nil {
+			continue
+		}
+
+		if ipNet.Contains(ip) {
+			return true
+		}
+	}
+
+	return false
+}
+
+// notifyRejectedIP tells the given user's chat that a request using its
+// token came from an IP that is not allowed. At most one notification
+// per rejectedIPNotificationInterval is sent for every user.
+func (a *app) notifyRejectedIP(ctx context.Context, user types.User, ip net.IP, now time.Time) {
+	log := glogger.New(glogger.Caller{
+		Service:  os.Getenv(serviceNameEnvVarName),
+		Package:  packageName,
+		Receiver: "app",
+		Function: "notifyRejectedIP",
+	})
+
+	if !a.rejectedIPNotifications.add(user.ID, now) {
+		log.Data("id", user.ID).Debug("rejected IP notification rate limited")
+
+		return
+	}
+
+	source := "an unknown IP"
+	if ip != nil {
+		source = ip.String()
+	}
+
+	msg := fmt.Sprintf(telegramBotRejectedIPMessageTpl, source)
+	if err := a.telegramBotSendMessage(ctx, user, msg); err != nil {
+		log.Err(err).Error("could not send telegram rejected IP message")
+	}
+}
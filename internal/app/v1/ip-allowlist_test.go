@@ -0,0 +1,102 @@
+package v1
+
+import (
+	"net"
+	"testing"
+
+	"github.com/psyb0t/telegram-logger/internal/pkg/types"
+	"github.com/stretchr/testify/assert"
+	"github.com/valyala/fasthttp"
+)
+
+func TestGetClientIP(t *testing.T) {
+	remoteAddr := &net.TCPAddr{IP: net.ParseIP("10.0.0.1"), Port: 1234}
+
+	tests := []struct {
+		name             string
+		forwardedFor     string
+		trustedProxyHops int
+		expected         net.IP
+	}{
+		{
+			name:             "no trusted proxies",
+			forwardedFor:     "1.1.1.1",
+			trustedProxyHops: 0,
+			expected:         net.ParseIP("10.0.0.1"),
+		},
+		{
+			name:             "one trusted proxy",
+			forwardedFor:     "1.1.1.1, 2.2.2.2",
+			trustedProxyHops: 1,
+			expected:         net.ParseIP("2.2.2.2"),
+		},
+		{
+			name:             "two trusted proxies",
+			forwardedFor:     "1.1.1.1, 2.2.2.2",
+			trustedProxyHops: 2,
+			expected:         net.ParseIP("1.1.1.1"),
+		},
+		{
+			name:             "more trusted proxies than hops",
+			forwardedFor:     "2.2.2.2",
+			trustedProxyHops: 2,
+			expected:         nil,
+		},
+		{
+			name:             "short chain with a spoofed client IP",
+			forwardedFor:     "10.1.2.3, 2.2.2.2",
+			trustedProxyHops: 3,
+			expected:         nil,
+		},
+		{
+			name:             "no forwarded for header",
+			forwardedFor:     "",
+			trustedProxyHops: 1,
+			expected:         net.ParseIP("10.0.0.1"),
+		},
+		{
+			name:             "invalid forwarded for header",
+			forwardedFor:     "unknown",
+			trustedProxyHops: 1,
+			expected:         nil,
+		},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			ctx := &fasthttp.RequestCtx{}
+			ctx.Init(&fasthttp.Request{}, remoteAddr, nil)
+
+			if test.forwardedFor != "" {
+				ctx.Request.Header.Set(headerNameXForwardedFor, test.forwardedFor)
+			}
+
+			actual := getClientIP(ctx, test.trustedProxyHops)
+			assert.True(t, test.expected.Equal(actual), "expected %s got %s", test.expected, actual)
+		})
+	}
+}
+
+func TestUserAllowsIP(t *testing.T) {
+	user := types.User{AllowedCIDRs: []string{"10.0.0.0/8", "2001:db8::/32"}}
+
+	tests := []struct {
+		name     string
+		user     types.User
+		ip       net.IP
+		expected bool
+	}{
+		{"no allowed CIDRs", types.User{}, net.ParseIP("1.1.1.1"), true},
+		{"no allowed CIDRs and no IP", types.User{}, nil, true},
+		{"IPv4 in range", user, net.ParseIP("10.1.2.3"), true},
+		{"IPv6 in range", user, net.ParseIP("2001:db8::1"), true},
+		{"out of range", user, net.ParseIP("192.168.1.1"), false},
+		{"no IP", user, nil, false},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			assert.Equal(t, test.expected, userAllowsIP(test.user, test.ip))
+		})
+	}
+}
@@ -0,0 +1,176 @@
+package v1
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"os"
+	"strconv"
+
+	tgbotapi "github.com/go-telegram-bot-api/telegram-bot-api/v5"
+	"github.com/psyb0t/glogger"
+	"github.com/psyb0t/telegram-logger/internal/pkg/storage"
+	"github.com/psyb0t/telegram-logger/internal/pkg/types"
+)
+
+const (
+	pendingUserIDHashPrefix = "pending:"
+
+	telegramBotRegistrationClosedMessageTpl = `Registration is closed.
+To request access, ask an admin to run
+/addUser %d`
+	telegramBotRegistrationRequestedMessage = `Your registration request was sent to the admins.
+You will get your ID once it is approved.`
+	telegramBotRegistrationAlreadyRequestedMessage = `Your registration request is already waiting for the admins.
+You will get your ID once it is approved.`
+	telegramBotRegistrationRejectedMessage    = `Your registration request was rejected.`
+	telegramBotRegistrationApprovalRequestTpl = `Chat %d (%s) wants to register.`
+	telegramBotRegistrationApproveButton      = "✅ Approve"
+	telegramBotRegistrationRejectButton       = "❌ Reject"
+)
+
+// getPendingUserID returns the ID of the pending user of the given chat.
+// It is derived from the chat ID so there's at most one pending user
+// per chat and it can be found without going through all users.
+func (a *app) getPendingUserID(chatID int64) string {
+	return hashToken(a.config.Auth.TokenHashKey, pendingUserIDHashPrefix+strconv.FormatInt(chatID, 10))
+}
+
+// requestRegistrationApproval stores a pending user for the given user's
+// chat and sends every admin a message with buttons for approving or
+// rejecting the registration. The pending user has no token so it
+// can't be used until the registration is approved. If the chat already
+// has a pending user the admins aren't notified again and the chat is
+// told that its request is still waiting.
+func (a *app) requestRegistrationApproval(ctx context.Context, user types.User) error {
+	log := glogger.New(glogger.Caller{
+		Service:  os.Getenv(serviceNameEnvVarName),
+		Package:  packageName,
+		Receiver: "app",
+		Function: "requestRegistrationApproval",
+	})
+
+	user.ID = a.getPendingUserID(user.TelegramChatID)
+	user.Status = types.UserStatusPending
+
+	alreadyRequested := false
+
+	log.Data("user", user).Debug("creating pending user")
+	err := a.db.InTx(ctx, func(tx storage.Repositories) error {
+		_, err := a.getPendingUser(ctx, tx, user.TelegramChatID)
+		alreadyRequested = err == nil
+
+		switch {
+		case alreadyRequested:
+			return nil
+		case !errors.Is(err, ErrRegistrationRequestNotFound):
+			return err
+		}
+
+		return tx.GetUserRepositoryWriter().Create(ctx, user) //nolint:wrapcheck
+	})
+	if err != nil {
+		log.Err(err).Error("error when creating pending user")
+
+		return err //nolint:wrapcheck
+	}
+
+	if alreadyRequested {
+		log.Data("user", user).Debug("the chat already has a pending user")
+
+		return a.telegramBotSendMessage(ctx, user, telegramBotRegistrationAlreadyRequestedMessage)
+	}
+
+	adminChatIDs, err := a.getAdminChatIDs(ctx)
+	if err != nil {
+		log.Err(err).Error("error when getting the admin chat IDs")
+
+		return err
+	}
+
+	chatIDArgument := strconv.FormatInt(user.TelegramChatID, 10)
+	keyboard := tgbotapi.NewInlineKeyboardMarkup(tgbotapi.NewInlineKeyboardRow(
+		tgbotapi.NewInlineKeyboardButtonData(telegramBotRegistrationApproveButton,
+			newTelegramBotCallbackData(telegramBotCallbackActionApproveRegistration, chatIDArgument)),
+		tgbotapi.NewInlineKeyboardButtonData(telegramBotRegistrationRejectButton,
+			newTelegramBotCallbackData(telegramBotCallbackActionRejectRegistration, chatIDArgument)),
+	))
+
+	for _, adminChatID := range adminChatIDs {
+		m := tgbotapi.NewMessage(adminChatID,
+			fmt.Sprintf(telegramBotRegistrationApprovalRequestTpl, user.TelegramChatID, user.ChatTitle))
+		m.ReplyMarkup = keyboard
+
+		if err := a.telegramBotSendMessageConfig(ctx, m); err != nil {
+			log.Data("adminChatID", adminChatID).Err(err).Error("could not send telegram approval request")
+		}
+	}
+
+	return a.telegramBotSendMessage(ctx, user, telegramBotRegistrationRequestedMessage)
+}
+
+// approveRegistration creates a user with a new token for the given chat
+// replacing its pending user and sends it the token. The pending user is
+// checked and replaced in a single transaction so that approving the same
+// registration twice creates only one user and sends only one token.
+func (a *app) approveRegistration(ctx context.Context, chatID int64) error {
+	user, token := a.newUserToken(types.User{TelegramChatID: chatID})
+
+	err := a.db.InTx(ctx, func(tx storage.Repositories) error {
+		pendingUser, err := a.getPendingUser(ctx, tx, chatID)
+		if err != nil {
+			return err
+		}
+
+		user.ChatTitle = pendingUser.ChatTitle
+
+		return replaceTelegramChatUsers(ctx, tx, user)
+	})
+	if err != nil {
+		return err //nolint:wrapcheck
+	}
+
+	return a.sendUserToken(ctx, user, token, user.TelegramChatID)
+}
+
+// rejectRegistration removes the pending user of the given chat
+// and lets the chat know about it. The pending user is checked and
+// removed in a single transaction so the chat is told only once.
+func (a *app) rejectRegistration(ctx context.Context, chatID int64) error {
+	var pendingUser types.User
+
+	err := a.db.InTx(ctx, func(tx storage.Repositories) error {
+		var err error
+
+		pendingUser, err = a.getPendingUser(ctx, tx, chatID)
+		if err != nil {
+			return err
+		}
+
+		return tx.GetUserRepositoryWriter().Delete(ctx, pendingUser.ID) //nolint:wrapcheck
+	})
+	if err != nil {
+		return err //nolint:wrapcheck
+	}
+
+	return a.telegramBotSendMessage(ctx, pendingUser, telegramBotRegistrationRejectedMessage)
+}
+
+// getPendingUser returns the pending user of the given chat from the given
+// repositories or ErrRegistrationRequestNotFound if the chat has no pending user.
+func (a *app) getPendingUser(ctx context.Context, db storage.Repositories, chatID int64) (types.User, error) {
+	user, err := db.GetUserRepositoryReader().Get(ctx, a.getPendingUserID(chatID))
+	if err != nil {
+		if errors.Is(err, storage.ErrNotFound) {
+			return user, ErrRegistrationRequestNotFound
+		}
+
+		return user, err //nolint:wrapcheck
+	}
+
+	if user.Status != types.UserStatusPending {
+		return user, ErrRegistrationRequestNotFound
+	}
+
+	return user, nil
+}
@@ -0,0 +1,82 @@
+package v1
+
+import (
+	"context"
+	"testing"
+
+	"github.com/psyb0t/telegram-logger/internal/pkg/storage/memory"
+	"github.com/psyb0t/telegram-logger/internal/pkg/types"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestApp_approveRegistration(t *testing.T) {
+	ctx := context.Background()
+
+	db, err := memory.New(ctx)
+	require.NoError(t, err)
+
+	telegramBotAPI, fakeTelegramBotAPI := newTestTelegramBotAPI(t)
+
+	a := &app{
+		config:         config{Auth: authConfig{TokenHashKey: "key"}},
+		db:             db,
+		telegramBotAPI: telegramBotAPI,
+	}
+
+	require.NoError(t, db.GetUserRepositoryWriter().Create(ctx, types.User{
+		ID:             a.getPendingUserID(-1),
+		TelegramChatID: -1,
+		ChatTitle:      "group",
+		Status:         types.UserStatusPending,
+	}))
+
+	require.NoError(t, a.approveRegistration(ctx, -1))
+	assert.ErrorIs(t, a.approveRegistration(ctx, -1), ErrRegistrationRequestNotFound)
+
+	_, err = db.GetUserRepositoryReader().Get(ctx, a.getPendingUserID(-1))
+	assert.Error(t, err)
+
+	sentMessages := fakeTelegramBotAPI.getSentMessages()
+	require.Len(t, sentMessages, 1)
+	assert.Equal(t, int64(-1), sentMessages[0].ChatID)
+}
+
+func TestApp_requestRegistrationApproval(t *testing.T) {
+	ctx := context.Background()
+
+	db, err := memory.New(ctx)
+	require.NoError(t, err)
+
+	telegramBotAPI, fakeTelegramBotAPI := newTestTelegramBotAPI(t)
+
+	a := &app{
+		config: config{
+			Auth:        authConfig{TokenHashKey: "key"},
+			TelegramBot: telegramBotConfig{SuperuserChatID: 100},
+		},
+		db:             db,
+		telegramBotAPI: telegramBotAPI,
+	}
+
+	user := types.User{TelegramChatID: 1, ChatTitle: "chat"}
+
+	require.NoError(t, a.requestRegistrationApproval(ctx, user))
+	require.NoError(t, a.requestRegistrationApproval(ctx, user))
+
+	pendingUser, err := a.getPendingUser(ctx, db, 1)
+	require.NoError(t, err)
+	assert.Equal(t, "chat", pendingUser.ChatTitle)
+
+	sentMessages := fakeTelegramBotAPI.getSentMessages()
+	require.Len(t, sentMessages, 3)
+	assert.Equal(t, int64(100), sentMessages[0].ChatID)
+	assert.Equal(t, testTelegramBotMessage{
+		ChatID: 1,
+		Text:   telegramBotRegistrationRequestedMessage,
+	}, sentMessages[1])
+	assert.Equal(t, testTelegramBotMessage{
+		ChatID: 1,
+		Text:   telegramBotRegistrationAlreadyRequestedMessage,
+	}, sentMessages[2])
+}
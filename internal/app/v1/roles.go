@@ -0,0 +1,232 @@
+package v1
+
+import (
+	"context"
+	"errors"
+	"os"
+	"strconv"
+	"strings"
+
+	"github.com/psyb0t/glogger"
+	"github.com/psyb0t/telegram-logger/internal/pkg/storage"
+	"github.com/psyb0t/telegram-logger/internal/pkg/types"
+)
+
+// settingKeySeededAdminChatIDs is the setting holding the comma
+// separated IDs of the chats seedRoles gave the admin role to.
+const settingKeySeededAdminChatIDs = "seededAdminChatIDs"
+
+// getTelegramChatRole returns the role of the given chat. Only the configured
+// superuser is an owner and chats without a stored role are members.
+func (a *app) getTelegramChatRole(ctx context.Context, chatID int64) (types.Role, error) {
+	if chatID == a.config.TelegramBot.SuperuserChatID {
+		return types.RoleOwner, nil
+	}
+
+	chatRole, err := a.db.GetRoleRepositoryReader().Get(ctx, chatID)
+	if err != nil {
+		if errors.Is(err, storage.ErrNotFound) {
+			return types.RoleMember, nil
+		}
+
+		return "", err //nolint:wrapcheck
+	}
+
+	// the owner role stored for a former superuser is ignored
+	// until seedRoles removes it
+	if chatRole.Role == types.RoleOwner {
+		return types.RoleMember, nil
+	}
+
+	return chatRole.Role, nil
+}
+
+// telegramBotRequireRole checks if the given chat's role includes the
+// given role. It returns ErrUnauthorizedToUseTelegramBotCommand if it
+// doesn't and ErrUnableToGetTelegramChatRole if the role can't be retrieved.
+func (a *app) telegramBotRequireRole(ctx context.Context, chatID int64, role types.Role) error {
+	log := glogger.New(glogger.Caller{
+		Service:  os.Getenv(serviceNameEnvVarName),
+		Package:  packageName,
+		Receiver: "app",
+		Function: "telegramBotRequireRole",
+	})
+
+	chatRole, err := a.getTelegramChatRole(ctx, chatID)
+	if err != nil {
+		log.Data("chatID", chatID).Err(err).Error(ErrUnableToGetTelegramChatRole.Error())
+
+		return ErrUnableToGetTelegramChatRole
+	}
+
+	if !chatRole.Includes(role) {
+		return ErrUnauthorizedToUseTelegramBotCommand
+	}
+
+	return nil
+}
+
+// getAdminChatIDs returns the IDs of all of the chats whose role
+// includes the admin role, including the configured superuser.
+func (a *app) getAdminChatIDs(ctx context.Context) ([]int64, error) {
+	chatRoles, err := a.db.GetRoleRepositoryReader().GetAll(ctx)
+	if err != nil {
+		return nil, err //nolint:wrapcheck
+	}
+
+	adminChatIDs := []int64{}
+	if a.config.TelegramBot.SuperuserChatID != 0 {
+		adminChatIDs = append(adminChatIDs, a.config.TelegramBot.SuperuserChatID)
+	}
+
+	for _, chatRole := range chatRoles {
+		if chatRole.TelegramChatID == a.config.TelegramBot.SuperuserChatID ||
+			chatRole.Role != types.RoleAdmin {
+			continue
+		}
+
+		adminChatIDs = append(adminChatIDs, chatRole.TelegramChatID)
+	}
+
+	return adminChatIDs, nil
+}
+
+// changeTelegramChatRole sets the role of the given chat to the given role.
+// Members don't have a stored role so setting the member role removes the
+// stored one. The owner role only comes from the config so the role of an
+// owner can't be changed and no chat can be made an owner.
+func (a *app) changeTelegramChatRole(ctx context.Context, chatID int64, role types.Role) error {
+	currentRole, err := a.getTelegramChatRole(ctx, chatID)
+	if err != nil {
+		return err
+	}
+
+	if currentRole == types.RoleOwner || role == types.RoleOwner {
+		return ErrCannotChangeOwnerRole
+	}
+
+	if role == types.RoleMember {
+		return a.db.GetRoleRepositoryWriter().Delete(ctx, chatID) //nolint:wrapcheck
+	}
+
+	return a.db.GetRoleRepositoryWriter().Set(ctx, types.ChatRole{ //nolint:wrapcheck
+		TelegramChatID: chatID,
+		Role:           role,
+	})
+}
+
+// seedRoles reconciles the stored roles with the config. The configured
+// admins get the admin role and the chats seeded as admins on a previous
+// start which aren't configured anymore lose it. Admins added with the
+// promote command are kept. The owner role is never stored since it
+// only belongs to the configured superuser so any stored one, such
+// as those stored by older versions, is removed.
+func (a *app) seedRoles(ctx context.Context) error {
+	log := glogger.New(glogger.Caller{
+		Service:  os.Getenv(serviceNameEnvVarName),
+		Package:  packageName,
+		Receiver: "app",
+		Function: "seedRoles",
+	})
+
+	adminChatIDs := map[int64]bool{}
+	for _, chatID := range a.config.TelegramBot.AdminChatIDs {
+		adminChatIDs[chatID] = true
+	}
+
+	err := a.db.InTx(ctx, func(tx storage.Repositories) error {
+		seededAdminChatIDs, err := getSeededAdminChatIDs(ctx, tx)
+		if err != nil {
+			return err
+		}
+
+		chatRoles, err := tx.GetRoleRepositoryReader().GetAll(ctx)
+		if err != nil {
+			return err //nolint:wrapcheck
+		}
+
+		storedRoles := map[int64]types.Role{}
+
+		for _, chatRole := range chatRoles {
+			chatID := chatRole.TelegramChatID
+
+			if chatRole.Role == types.RoleOwner ||
+				(chatRole.Role == types.RoleAdmin && seededAdminChatIDs[chatID] && !adminChatIDs[chatID]) {
+				log.Data("chatID", chatID).Data("role", chatRole.Role).Debug("revoking role")
+				if err := tx.GetRoleRepositoryWriter().Delete(ctx, chatID); err != nil {
+					return err //nolint:wrapcheck
+				}
+
+				continue
+			}
+
+			storedRoles[chatID] = chatRole.Role
+		}
+
+		for _, chatID := range a.config.TelegramBot.AdminChatIDs {
+			if chatID == a.config.TelegramBot.SuperuserChatID || storedRoles[chatID].Includes(types.RoleAdmin) {
+				continue
+			}
+
+			log.Data("chatID", chatID).Debug("seeding admin")
+			if err := tx.GetRoleRepositoryWriter().Set(ctx, types.ChatRole{
+				TelegramChatID: chatID,
+				Role:           types.RoleAdmin,
+			}); err != nil {
+				return err //nolint:wrapcheck
+			}
+		}
+
+		return setSeededAdminChatIDs(ctx, tx, a.config.TelegramBot.AdminChatIDs)
+	})
+	if err != nil {
+		log.Err(err).Error("error when seeding roles")
+
+		return err //nolint:wrapcheck
+	}
+
+	return nil
+}
+
+// getSeededAdminChatIDs returns the IDs of the chats seedRoles
+// gave the admin role to the last time it ran.
+func getSeededAdminChatIDs(ctx context.Context, db storage.Repositories) (map[int64]bool, error) {
+	value, err := db.GetSettingRepositoryReader().Get(ctx, settingKeySeededAdminChatIDs)
+	if err != nil {
+		if errors.Is(err, storage.ErrNotFound) {
+			return map[int64]bool{}, nil
+		}
+
+		return nil, err //nolint:wrapcheck
+	}
+
+	chatIDs := map[int64]bool{}
+
+	for _, field := range strings.Split(value, ",") {
+		if field == "" {
+			continue
+		}
+
+		chatID, err := strconv.ParseInt(field, 10, 64)
+		if err != nil {
+			return nil, err //nolint:wrapcheck
+		}
+
+		chatIDs[chatID] = true
+	}
+
+	return chatIDs, nil
+}
+
+// setSeededAdminChatIDs stores the IDs of the chats seedRoles gave
+// the admin role to so that it can be revoked once they're
+// removed from the config.
+func setSeededAdminChatIDs(ctx context.Context, db storage.Repositories, chatIDs []int64) error {
+	fields := make([]string, 0, len(chatIDs))
+	for _, chatID := range chatIDs {
+		fields = append(fields, strconv.FormatInt(chatID, 10))
+	}
+
+	return db.GetSettingRepositoryWriter().Set(ctx, //nolint:wrapcheck
+		settingKeySeededAdminChatIDs, strings.Join(fields, ","))
+}
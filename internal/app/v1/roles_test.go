@@ -0,0 +1,171 @@
+package v1
+
+import (
+	"context"
+	"errors"
+	"testing"
+
+	"github.com/psyb0t/telegram-logger/internal/pkg/storage"
+	"github.com/psyb0t/telegram-logger/internal/pkg/storage/memory"
+	"github.com/psyb0t/telegram-logger/internal/pkg/types"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+var errTestStorage = errors.New("storage error")
+
+func newRolesTestApp() (*app, *storage.RoleRepositoryReaderMock, *storage.RoleRepositoryWriterMock) {
+	db := storage.NewMock()
+	roleRepositoryReader, _ := db.GetRoleRepositoryReader().(*storage.RoleRepositoryReaderMock)
+	roleRepositoryWriter, _ := db.GetRoleRepositoryWriter().(*storage.RoleRepositoryWriterMock)
+
+	roleRepositoryReader.On("Get", int64(2)).Return(types.ChatRole{TelegramChatID: 2, Role: types.RoleAdmin}, nil)
+	roleRepositoryReader.On("Get", int64(3)).Return(types.ChatRole{}, storage.ErrNotFound)
+	roleRepositoryReader.On("Get", int64(4)).Return(types.ChatRole{}, errTestStorage)
+	roleRepositoryReader.On("Get", int64(5)).Return(types.ChatRole{TelegramChatID: 5, Role: types.RoleOwner}, nil)
+
+	a := &app{
+		config: config{
+			TelegramBot: telegramBotConfig{SuperuserChatID: 1},
+		},
+		db: db,
+	}
+
+	return a, roleRepositoryReader, roleRepositoryWriter
+}
+
+func TestApp_getTelegramChatRole(t *testing.T) {
+	a, _, _ := newRolesTestApp()
+
+	tests := []struct {
+		name         string
+		chatID       int64
+		expectedRole types.Role
+		expectedErr  error
+	}{
+		{"superuser", 1, types.RoleOwner, nil},
+		{"stored role", 2, types.RoleAdmin, nil},
+		{"no stored role", 3, types.RoleMember, nil},
+		{"storage error", 4, "", errTestStorage},
+		{"stored owner role of a former superuser", 5, types.RoleMember, nil},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			role, err := a.getTelegramChatRole(context.Background(), test.chatID)
+			assert.ErrorIs(t, err, test.expectedErr)
+			assert.Equal(t, test.expectedRole, role)
+		})
+	}
+}
+
+func TestApp_telegramBotRequireRole(t *testing.T) {
+	a, _, _ := newRolesTestApp()
+
+	tests := []struct {
+		name        string
+		chatID      int64
+		role        types.Role
+		expectedErr error
+	}{
+		{"owner requiring owner", 1, types.RoleOwner, nil},
+		{"admin requiring admin", 2, types.RoleAdmin, nil},
+		{"admin requiring owner", 2, types.RoleOwner, ErrUnauthorizedToUseTelegramBotCommand},
+		{"member requiring member", 3, types.RoleMember, nil},
+		{"member requiring admin", 3, types.RoleAdmin, ErrUnauthorizedToUseTelegramBotCommand},
+		{"storage error", 4, types.RoleMember, ErrUnableToGetTelegramChatRole},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			assert.ErrorIs(t, a.telegramBotRequireRole(context.Background(), test.chatID, test.role), test.expectedErr)
+		})
+	}
+}
+
+func TestApp_changeTelegramChatRole(t *testing.T) {
+	ctx := context.Background()
+
+	a, _, roleRepositoryWriter := newRolesTestApp()
+	roleRepositoryWriter.On("Set", types.ChatRole{TelegramChatID: 3, Role: types.RoleAdmin}).Return(nil)
+	roleRepositoryWriter.On("Delete", int64(2)).Return(nil)
+
+	assert.NoError(t, a.changeTelegramChatRole(ctx, 3, types.RoleAdmin))
+	assert.NoError(t, a.changeTelegramChatRole(ctx, 2, types.RoleMember))
+	assert.ErrorIs(t, a.changeTelegramChatRole(ctx, 1, types.RoleMember), ErrCannotChangeOwnerRole)
+	assert.ErrorIs(t, a.changeTelegramChatRole(ctx, 3, types.RoleOwner), ErrCannotChangeOwnerRole)
+
+	roleRepositoryWriter.AssertExpectations(t)
+}
+
+func TestApp_seedRoles(t *testing.T) {
+	ctx := context.Background()
+
+	db, err := memory.New(ctx)
+	require.NoError(t, err)
+
+	// 1 is the superuser and 7 was the superuser before with owner roles
+	// stored by an older version, 2 was promoted, 5 was seeded on the
+	// previous start and is no longer configured
+	for _, chatRole := range []types.ChatRole{
+		{TelegramChatID: 1, Role: types.RoleOwner},
+		{TelegramChatID: 7, Role: types.RoleOwner},
+		{TelegramChatID: 2, Role: types.RoleAdmin},
+		{TelegramChatID: 5, Role: types.RoleAdmin},
+		{TelegramChatID: 3, Role: types.RoleAdmin},
+	} {
+		require.NoError(t, db.GetRoleRepositoryWriter().Set(ctx, chatRole))
+	}
+
+	require.NoError(t, db.GetSettingRepositoryWriter().Set(ctx, settingKeySeededAdminChatIDs, "3,5"))
+
+	a := &app{
+		config: config{
+			TelegramBot: telegramBotConfig{SuperuserChatID: 1, AdminChatIDs: []int64{1, 3, 6}},
+		},
+		db: db,
+	}
+
+	require.NoError(t, a.seedRoles(ctx))
+
+	chatRoles, err := db.GetRoleRepositoryReader().GetAll(ctx)
+	require.NoError(t, err)
+	assert.ElementsMatch(t, []types.ChatRole{
+		{TelegramChatID: 2, Role: types.RoleAdmin},
+		{TelegramChatID: 3, Role: types.RoleAdmin},
+		{TelegramChatID: 6, Role: types.RoleAdmin},
+	}, chatRoles)
+
+	seededAdminChatIDs, err := db.GetSettingRepositoryReader().Get(ctx, settingKeySeededAdminChatIDs)
+	require.NoError(t, err)
+	assert.Equal(t, "1,3,6", seededAdminChatIDs)
+
+	role, err := a.getTelegramChatRole(ctx, 7)
+	require.NoError(t, err)
+	assert.Equal(t, types.RoleMember, role)
+
+	// removing a chat from the config revokes the role it was seeded with
+	a.config.TelegramBot.AdminChatIDs = []int64{3}
+	require.NoError(t, a.seedRoles(ctx))
+
+	chatRoles, err = db.GetRoleRepositoryReader().GetAll(ctx)
+	require.NoError(t, err)
+	assert.ElementsMatch(t, []types.ChatRole{
+		{TelegramChatID: 2, Role: types.RoleAdmin},
+		{TelegramChatID: 3, Role: types.RoleAdmin},
+	}, chatRoles)
+}
+
+func TestApp_getAdminChatIDs(t *testing.T) {
+	a, roleRepositoryReader, _ := newRolesTestApp()
+
+	roleRepositoryReader.On("GetAll").Return([]types.ChatRole{
+		{TelegramChatID: 1, Role: types.RoleOwner},
+		{TelegramChatID: 2, Role: types.RoleAdmin},
+		{TelegramChatID: 5, Role: types.RoleMember},
+	}, nil)
+
+	adminChatIDs, err := a.getAdminChatIDs(context.Background())
+	assert.NoError(t, err)
+	assert.Equal(t, []int64{1, 2}, adminChatIDs)
+}
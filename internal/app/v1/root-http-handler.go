@@ -6,22 +6,28 @@ import (
 	"fmt"
 	"os"
 	"strings"
+	"time"
 
 	"github.com/psyb0t/glogger"
 	"github.com/psyb0t/telegram-logger/internal/pkg/storage"
+	internaltypes "github.com/psyb0t/telegram-logger/internal/pkg/types"
 	"github.com/psyb0t/telegram-logger/pkg/types"
 	"github.com/valyala/fasthttp"
 )
 
-const (
-	headerNameXID = "X-ID"
-)
+const errChatUnreachableMessage = "the bot was blocked or removed from the chat"
 
 // rootHTTPHandler handles HTTP requests to the root path. It gets the user
-// associated with the request based on the value of the X-ID header, parses
-// the JSON request body, builds a Telegram message string from the request,
-// and sends the message to the user via the Telegram bot. It returns an HTTP
-// response with the status code, header, and body serialized as JSON.
+// associated with the request based on the hash of the X-ID header value or
+// on the request signature and rejects tokens of chats the bot was removed
+// from with 410, requests coming from IPs that are not allowed with 403 and
+// expired tokens with 401 and the token_expired reason. It then parses the
+// JSON request body, rejects requests the token's scopes don't allow with
+// 403, builds a Telegram message string from the request and sends the
+// message to the user via the Telegram bot. If sending fails because the
+// bot was removed from the chat, the chat's tokens are deactivated and 410
+// is returned. It returns an HTTP response with the status code, header,
+// and body serialized as JSON.
 func (a *app) rootHTTPHandler(ctx *fasthttp.RequestCtx) {
 	log := glogger.New(glogger.Caller{
 		Service:  os.Getenv(serviceNameEnvVarName),
@@ -30,17 +36,65 @@ func (a *app) rootHTTPHandler(ctx *fasthttp.RequestCtx) {
 		Function: "rootHTTPHandler",
 	})
 
-	id := string(ctx.Request.Header.Peek(headerNameXID))
-	log.Data("id", id).Debug("getting user by id")
-	user, err := a.db.GetUserRepositoryReader().Get(id)
+	now := time.Now()
+
+	log.Debug("authenticating request")
+	user, err := a.authenticateHTTPRequest(ctx, now)
 	if err != nil {
-		log.Err(err).Error("there was an error when getting the user by ID")
+		log.Err(err).Error("there was an error when authenticating the request")
+
+		switch {
+		case errors.Is(err, ErrInvalidToken), errors.Is(err, storage.ErrEmptyID), errors.Is(err, storage.ErrNotFound):
+			a.returnHTTPResponseJSON(ctx, fasthttp.StatusUnauthorized,
+				types.Response{Error: "invalid token", Reason: types.ResponseReasonInvalidToken})
+		case errors.Is(err, ErrInvalidSignature):
+			a.returnHTTPResponseJSON(ctx, fasthttp.StatusUnauthorized,
+				types.Response{Error: err.Error(), Reason: types.ResponseReasonInvalidSignature})
+		default:
+			a.returnHTTPResponseJSON(ctx, fasthttp.StatusInternalServerError,
+				types.Response{Error: err.Error()})
+		}
 
-		if errors.Is(err, storage.ErrEmptyID) || errors.Is(err, storage.ErrNotFound) {
-			a.returnHTTPResponseString(ctx, fasthttp.StatusUnauthorized, "")
+		return
+	}
 
-			return
-		}
+	if user.Status == internaltypes.UserStatusInactive {
+		log.Data("id", user.ID).Debug("chat unreachable")
+
+		a.returnHTTPResponseJSON(ctx, fasthttp.StatusGone,
+			types.Response{Error: errChatUnreachableMessage, Reason: types.ResponseReasonChatUnreachable})
+
+		return
+	}
+
+	if !user.IsActive() {
+		log.Data("id", user.ID).Debug("user is not active")
+
+		a.returnHTTPResponseJSON(ctx, fasthttp.StatusUnauthorized,
+			types.Response{Error: "invalid token", Reason: types.ResponseReasonInvalidToken})
+
+		return
+	}
+
+	clientIP := getClientIP(ctx, a.config.TrustedProxyHops)
+	if !userAllowsIP(user, clientIP) {
+		log.Data("id", user.ID).Data("ip", clientIP.String()).Debug("IP not allowed")
+
+		a.notifyRejectedIP(ctx, user, clientIP, now)
+
+		a.returnHTTPResponseJSON(ctx, fasthttp.StatusForbidden,
+			types.Response{Error: "IP not allowed", Reason: types.ResponseReasonIPNotAllowed})
+
+		return
+	}
+
+	if user.IsExpired(now) {
+		log.Data("id", user.ID).Debug("token expired")
+
+		a.returnHTTPResponseJSON(ctx, fasthttp.StatusUnauthorized,
+			types.Response{Error: "token expired", Reason: types.ResponseReasonTokenExpired})
+
+		return
 	}
 
 	log.Debug("parsing JSON request body")
@@ -54,6 +108,15 @@ func (a *app) rootHTTPHandler(ctx *fasthttp.RequestCtx) {
 		return
 	}
 
+	if err := checkUserScopes(user, string(ctx.Path()), request); err != nil {
+		log.Data("id", user.ID).Err(err).Debug("request not allowed by the token scopes")
+
+		a.returnHTTPResponseJSON(ctx, fasthttp.StatusForbidden,
+			types.Response{Error: err.Error(), Reason: types.ResponseReasonInsufficientScope})
+
+		return
+	}
+
 	log.Data("request", request).Debug("building telegram message string from request")
 	telegramMessage, err := requestToTelegramMessageString(request)
 	if err != nil {
@@ -69,9 +132,20 @@ func (a *app) rootHTTPHandler(ctx *fasthttp.RequestCtx) {
 		Data("user", user).
 		Debug("sending message to the user")
 
-	if err := a.telegramBotSendMessage(user, telegramMessage); err != nil {
+	if err := a.telegramBotSendMessage(ctx, user, telegramMessage); err != nil {
 		log.Err(err).Error("there was an error when sending the message to the user")
 
+		if isTelegramBotRemovedError(err) {
+			if err := a.deactivateTelegramChat(ctx, user.TelegramChatID, ""); err != nil {
+				log.Err(err).Error("error when deactivating the chat")
+			}
+
+			a.returnHTTPResponseJSON(ctx, fasthttp.StatusGone,
+				types.Response{Error: errChatUnreachableMessage, Reason: types.ResponseReasonChatUnreachable})
+
+			return
+		}
+
 		a.returnHTTPResponseJSON(ctx, fasthttp.StatusInternalServerError,
 			types.Response{Error: err.Error()})
 
@@ -82,6 +156,24 @@ func (a *app) rootHTTPHandler(ctx *fasthttp.RequestCtx) {
 	a.returnHTTPResponseJSON(ctx, fasthttp.StatusOK, response)
 }
 
+// checkUserScopes checks if the given user's scopes allow ingesting
+// the given request on the given endpoint path.
+func checkUserScopes(user internaltypes.User, path string, request types.Request) error {
+	if !user.Scopes.HasPermission(internaltypes.PermissionIngest) {
+		return ErrPermissionNotGranted
+	}
+
+	if !user.Scopes.AllowsEndpoint(path) {
+		return ErrEndpointNotAllowed
+	}
+
+	if !user.Scopes.AllowsLevel(getLogLevelName(request.Level)) {
+		return ErrLevelNotAllowed
+	}
+
+	return nil
+}
+
 // requestToTelegramMessageString builds a Telegram message string from a
 // types.Request struct. It returns the message string and an error if
 // there was an issue building the string.
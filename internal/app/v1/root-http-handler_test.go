@@ -1 +1,141 @@
 package v1
+
+import (
+	"context"
+	"testing"
+
+	tgbotapi "github.com/go-telegram-bot-api/telegram-bot-api/v5"
+	"github.com/psyb0t/telegram-logger/internal/pkg/storage/memory"
+	internaltypes "github.com/psyb0t/telegram-logger/internal/pkg/types"
+	"github.com/psyb0t/telegram-logger/pkg/types"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"github.com/valyala/fasthttp"
+)
+
+func TestCheckUserScopes(t *testing.T) {
+	tests := []struct {
+		name        string
+		scopes      internaltypes.Scopes
+		path        string
+		level       string
+		expectedErr error
+	}{
+		{
+			name:  "no scopes",
+			path:  "/",
+			level: "info",
+		},
+		{
+			name: "all allowed",
+			scopes: internaltypes.Scopes{
+				Permissions: []internaltypes.Permission{internaltypes.PermissionIngest},
+				Levels:      []string{"error", "fatal"},
+				Endpoints:   []string{"/"},
+			},
+			path:  "/",
+			level: "ERR",
+		},
+		{
+			name: "ingest not granted",
+			scopes: internaltypes.Scopes{
+				Permissions: []internaltypes.Permission{internaltypes.PermissionHistoryRead},
+			},
+			path:        "/",
+			level:       "info",
+			expectedErr: ErrPermissionNotGranted,
+		},
+		{
+			name: "endpoint not allowed",
+			scopes: internaltypes.Scopes{
+				Endpoints: []string{"/other"},
+			},
+			path:        "/",
+			level:       "info",
+			expectedErr: ErrEndpointNotAllowed,
+		},
+		{
+			name: "level not allowed",
+			scopes: internaltypes.Scopes{
+				Levels: []string{"error"},
+			},
+			path:        "/",
+			level:       "warning",
+			expectedErr: ErrLevelNotAllowed,
+		},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			user := internaltypes.User{Scopes: test.scopes}
+			err := checkUserScopes(user, test.path, types.Request{Level: test.level})
+			assert.ErrorIs(t, err, test.expectedErr)
+		})
+	}
+}
+
+func TestApp_rootHTTPHandler_telegramBotSendMessageForbidden(t *testing.T) {
+	tests := []struct {
+		name           string
+		message        string
+		expectedCode   int
+		expectedActive bool
+	}{
+		{
+			name:           "bot blocked",
+			message:        "Forbidden: bot was blocked by the user",
+			expectedCode:   fasthttp.StatusGone,
+			expectedActive: false,
+		},
+		{
+			name:           "not enough rights",
+			message:        "Forbidden: not enough rights to send text messages to the chat",
+			expectedCode:   fasthttp.StatusInternalServerError,
+			expectedActive: true,
+		},
+		{
+			name:           "can't initiate conversation",
+			message:        "Forbidden: bot can't initiate conversation with a user",
+			expectedCode:   fasthttp.StatusInternalServerError,
+			expectedActive: true,
+		},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			ctx := context.Background()
+
+			db, err := memory.New(ctx)
+			require.NoError(t, err)
+
+			telegramBotAPI, fakeTelegramBotAPI := newTestTelegramBotAPI(t)
+			fakeTelegramBotAPI.sendMessageErr = &tgbotapi.Error{Code: fasthttp.StatusForbidden, Message: test.message}
+
+			a := &app{
+				config:         config{Auth: authConfig{TokenHashKey: "key"}},
+				db:             db,
+				telegramBotAPI: telegramBotAPI,
+			}
+
+			user := internaltypes.User{ID: hashToken("key", "token"), TelegramChatID: 1}
+			require.NoError(t, db.GetUserRepositoryWriter().Create(ctx, user))
+
+			request := &fasthttp.Request{}
+			request.Header.SetMethod(fasthttp.MethodPost)
+			request.SetRequestURI("/")
+			request.Header.Set(headerNameXID, "token")
+			request.SetBodyString(`{"message":"hello"}`)
+
+			// the storage uses the request as its context which needs Init
+			requestCtx := &fasthttp.RequestCtx{}
+			requestCtx.Init(request, nil, nil)
+
+			a.rootHTTPHandler(requestCtx)
+			assert.Equal(t, test.expectedCode, requestCtx.Response.StatusCode())
+
+			actual, err := db.GetUserRepositoryReader().Get(ctx, user.ID)
+			require.NoError(t, err)
+			assert.Equal(t, test.expectedActive, actual.IsActive())
+		})
+	}
+}
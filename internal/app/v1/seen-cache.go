@@ -0,0 +1,55 @@
+package v1
+
+import (
+	"sync"
+	"time"
+)
+
+// seenCache remembers keys for a limited time. It is used for making
+// sure that the nonce of a signed request is not reused and for
+// rate limiting notifications.
+type seenCache struct {
+	mu        sync.Mutex
+	ttl       time.Duration
+	keys      map[string]time.Time
+	lastPurge time.Time
+}
+
+// newSeenCache creates a new seenCache which remembers
+// every key for the given duration.
+func newSeenCache(ttl time.Duration) *seenCache {
+	return &seenCache{
+		ttl:  ttl,
+		keys: map[string]time.Time{},
+	}
+}
+
+// add stores the given key as seen at the given time. It returns false
+// if the key was already seen within the cache's ttl.
+func (c *seenCache) add(key string, now time.Time) bool {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	if now.Sub(c.lastPurge) > c.ttl {
+		c.purge(now)
+	}
+
+	if seenAt, ok := c.keys[key]; ok && now.Sub(seenAt) <= c.ttl {
+		return false
+	}
+
+	c.keys[key] = now
+
+	return true
+}
+
+// purge removes the expired keys. The caller must hold the lock.
+func (c *seenCache) purge(now time.Time) {
+	for key, seenAt := range c.keys {
+		if now.Sub(seenAt) > c.ttl {
+			delete(c.keys, key)
+		}
+	}
+
+	c.lastPurge = now
+}
@@ -0,0 +1,22 @@
+package v1
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestSeenCache_add(t *testing.T) {
+	now := time.Date(2023, 5, 1, 12, 0, 0, 0, time.UTC)
+	c := newSeenCache(time.Minute)
+
+	assert.True(t, c.add("a", now))
+	assert.True(t, c.add("b", now))
+	assert.False(t, c.add("a", now.Add(30*time.Second)))
+	assert.True(t, c.add("a", now.Add(2*time.Minute)))
+
+	// the first add after the ttl passed purges the expired keys
+	c.add("c", now.Add(4*time.Minute))
+	assert.Len(t, c.keys, 1)
+}
@@ -0,0 +1,188 @@
+package v1
+
+import (
+	"context"
+	"io"
+	"os"
+
+	"github.com/psyb0t/glogger"
+	"github.com/psyb0t/telegram-logger/internal/pkg/storage"
+	"github.com/psyb0t/telegram-logger/internal/pkg/storage/badgerdb"
+)
+
+// Backup writes a backup of the configured storage to w. Only the
+// storages implementing storage.Backuper, such as badgerDB, support it.
+// The storage is opened by Backup so it can't be in use by the app if
+// the storage only allows a single process, like badgerDB does. The
+// admin API serves backups of the running app instead.
+func Backup(ctx context.Context, w io.Writer) error {
+	return withStorage(ctx, func(db storage.Storage) error {
+		backuper, ok := db.(storage.Backuper)
+		if !ok {
+			return ErrBackupNotSupported
+		}
+
+		return backuper.Backup(ctx, w) //nolint:wrapcheck
+	})
+}
+
+// Restore loads a backup written by Backup from r into the
+// configured storage which must be empty.
+func Restore(ctx context.Context, r io.Reader) error {
+	return withStorage(ctx, func(db storage.Storage) error {
+		backuper, ok := db.(storage.Backuper)
+		if !ok {
+			return ErrBackupNotSupported
+		}
+
+		if err := requireEmptyStorage(ctx, db); err != nil {
+			return err
+		}
+
+		return backuper.Restore(ctx, r) //nolint:wrapcheck
+	})
+}
+
+// Export writes all of the data of the configured storage to w in the
+// given format. Unlike a backup, an export can be imported into any
+// kind of storage.
+func Export(ctx context.Context, w io.Writer, format string) error {
+	return withStorage(ctx, func(db storage.Storage) error {
+		return storage.Export(ctx, db, w, storage.ExportFormat(format)) //nolint:wrapcheck
+	})
+}
+
+// Import writes the data exported by Export in the given format from r
+// to the configured storage which must be empty.
+func Import(ctx context.Context, r io.Reader, format string) error {
+	return withStorage(ctx, func(db storage.Storage) error {
+		if err := requireEmptyStorage(ctx, db); err != nil {
+			return err
+		}
+
+		return storage.Import(ctx, db, r, storage.ExportFormat(format)) //nolint:wrapcheck
+	})
+}
+
+// RotateEncryptionKey encrypts the configured badgerDB storage with the
+// key read from newKey instead of the configured one, which the config
+// has to be changed to afterwards. The storage must already be encrypted
+// and it can't be in use by the app while the key is rotated.
+func RotateEncryptionKey(newKey io.Reader) error {
+	log := glogger.New(glogger.Caller{
+		Service:  os.Getenv(serviceNameEnvVarName),
+		Package:  packageName,
+		Function: "RotateEncryptionKey",
+	})
+
+	cfg, err := newCommandConfig()
+	if err != nil {
+		return err
+	}
+
+	if cfg.Storage.Type != storageTypeBadgerDB {
+		return ErrEncryptionNotSupported
+	}
+
+	key, err := io.ReadAll(newKey)
+	if err != nil {
+		log.Err(err).Error("error when reading the new encryption key")
+
+		return err
+	}
+
+	badgerDBConfig, err := newBadgerDBConfig(cfg.Storage.BadgerDB)
+	if err != nil {
+		log.Err(err).Error("error when reading the current encryption key")
+
+		return err
+	}
+
+	log.Info("rotating the encryption key")
+
+	return badgerdb.RotateEncryptionKey(cfg.Storage.BadgerDB.DSN, badgerDBConfig, key) //nolint:wrapcheck
+}
+
+// withStorage reads the config, opens the configured storage,
+// calls fn with it and closes it.
+func withStorage(ctx context.Context, fn func(db storage.Storage) error) error {
+	log := glogger.New(glogger.Caller{
+		Service:  os.Getenv(serviceNameEnvVarName),
+		Package:  packageName,
+		Function: "withStorage",
+	})
+
+	cfg, err := newCommandConfig()
+	if err != nil {
+		return err
+	}
+
+	db, err := newStorage(ctx, cfg.Storage)
+	if err != nil {
+		log.Err(err).Error("an error occurred when setting up the database")
+
+		return err
+	}
+
+	log.Data("type", cfg.Storage.Type).Info("opening the database connection")
+	if err := db.Open(ctx, cfg.Storage.getDSN()); err != nil {
+		log.Err(err).Error(ErrUnableToOpenDatabaseConnection.Error())
+
+		return ErrUnableToOpenDatabaseConnection
+	}
+
+	defer func() {
+		if err := db.Close(); err != nil {
+			log.Err(err).Error("error when closing the database connection")
+		}
+	}()
+
+	return fn(db)
+}
+
+// newCommandConfig reads the config and sets up the logger with it.
+func newCommandConfig() (config, error) {
+	log := glogger.New(glogger.Caller{
+		Service:  os.Getenv(serviceNameEnvVarName),
+		Package:  packageName,
+		Function: "newCommandConfig",
+	})
+
+	cfg, err := newConfig()
+	if err != nil {
+		log.Err(err).Error("error when initializing config")
+
+		return config{}, err
+	}
+
+	glogger.SetLogLevel(glogger.StrToLogLevel(cfg.Logger.Level))
+	glogger.SetLogFormat(glogger.StrToLogFormat(cfg.Logger.Format))
+
+	return cfg, nil
+}
+
+// requireEmptyStorage returns ErrStorageNotEmpty if the given storage
+// has any users, chat roles or invites. The settings don't count since
+// they're written by the app on its own.
+func requireEmptyStorage(ctx context.Context, db storage.Repositories) error {
+	users, err := db.GetUserRepositoryReader().GetPage(ctx, storage.Cursor{Limit: 1})
+	if err != nil {
+		return err //nolint:wrapcheck
+	}
+
+	roles, err := db.GetRoleRepositoryReader().GetAll(ctx)
+	if err != nil {
+		return err //nolint:wrapcheck
+	}
+
+	invites, err := db.GetInviteRepositoryReader().GetAll(ctx)
+	if err != nil {
+		return err //nolint:wrapcheck
+	}
+
+	if len(users) > 0 || len(roles) > 0 || len(invites) > 0 {
+		return ErrStorageNotEmpty
+	}
+
+	return nil
+}
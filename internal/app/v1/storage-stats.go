@@ -0,0 +1,99 @@
+package v1
+
+import (
+	"context"
+	"os"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/psyb0t/glogger"
+	"github.com/psyb0t/telegram-logger/internal/pkg/storage"
+)
+
+const storageStatsLogInterval = time.Hour
+
+// startStorageStatsLogger periodically logs the statistics of the storage
+// until the app context is done. It returns right away if the
+// storage doesn't report statistics.
+func (a *app) startStorageStatsLogger(wg *sync.WaitGroup) {
+	defer wg.Done()
+
+	log := glogger.New(glogger.Caller{
+		Service:  os.Getenv(serviceNameEnvVarName),
+		Package:  packageName,
+		Receiver: "app",
+		Function: "startStorageStatsLogger",
+	})
+
+	if _, ok := a.db.(storage.StatsReporter); !ok {
+		log.Debug("the storage doesn't report statistics")
+
+		return
+	}
+
+	log.Info("starting the storage stats logger")
+	defer log.Info("storage stats logger stopped")
+
+	ticker := time.NewTicker(storageStatsLogInterval)
+	defer ticker.Stop()
+
+	for {
+		if err := a.logStorageStats(a.ctx); err != nil {
+			log.Err(err).Error("an error occurred when logging the storage stats")
+		}
+
+		select {
+		case <-a.ctx.Done():
+			return
+		case <-ticker.C:
+		}
+	}
+}
+
+// logStorageStats logs the statistics of the storage, each one as a field.
+func (a *app) logStorageStats(ctx context.Context) error {
+	log := glogger.New(glogger.Caller{
+		Service:  os.Getenv(serviceNameEnvVarName),
+		Package:  packageName,
+		Receiver: "app",
+		Function: "logStorageStats",
+	})
+
+	stats, err := a.getStorageStats(ctx)
+	if err != nil {
+		return err
+	}
+
+	for _, stat := range stats {
+		log = log.Data(stat.Name, stat.Value)
+	}
+
+	log.Info("storage stats")
+
+	return nil
+}
+
+// getStorageStats returns the statistics of the storage or
+// ErrStorageStatsNotSupported if it doesn't report any.
+func (a *app) getStorageStats(ctx context.Context) ([]storage.Stat, error) {
+	reporter, ok := a.db.(storage.StatsReporter)
+	if !ok {
+		return nil, ErrStorageStatsNotSupported
+	}
+
+	return reporter.Stats(ctx) //nolint:wrapcheck
+}
+
+// formatStorageStats formats the given statistics one per line.
+func formatStorageStats(stats []storage.Stat) string {
+	var b strings.Builder
+
+	b.WriteString("Database stats\n")
+
+	for _, stat := range stats {
+		b.WriteString("\n" + stat.Name + ": " + stat.Value)
+	}
+
+	return b.String()
+}
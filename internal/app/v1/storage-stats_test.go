@@ -0,0 +1,32 @@
+package v1
+
+import (
+	"context"
+	"testing"
+
+	"github.com/psyb0t/telegram-logger/internal/pkg/storage"
+	"github.com/psyb0t/telegram-logger/internal/pkg/storage/memory"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestApp_getStorageStats_notSupported(t *testing.T) {
+	ctx := context.Background()
+
+	db, err := memory.New(ctx)
+	require.NoError(t, err)
+
+	a := &app{db: db}
+
+	_, err = a.getStorageStats(ctx)
+	assert.ErrorIs(t, err, ErrStorageStatsNotSupported)
+}
+
+func TestFormatStorageStats(t *testing.T) {
+	stats := []storage.Stat{
+		{Name: "lsmSize", Value: "1024"},
+		{Name: "lastGC", Value: "never"},
+	}
+
+	assert.Equal(t, "Database stats\n\nlsmSize: 1024\nlastGC: never", formatStorageStats(stats))
+}
@@ -1,15 +1,22 @@
 package v1
 
 import (
+	"context"
 	"os"
 	"strconv"
+	"time"
 
 	"github.com/psyb0t/glogger"
 	"github.com/psyb0t/telegram-logger/internal/pkg/types" //nolint:depguard
 )
 
+// telegramBotAddUserCommandHandler handles the telegramBotAddUser command
+// of the telegram bot. It creates a user for the chat ID given as the first
+// argument, limited by the key=value options given as the rest of the
+// arguments (see applyUserOptions), and sends the token to that chat.
+//
 //nolint:funlen
-func (a *app) telegramBotAddUserCommandHandler(chatID int64, arguments []string) error {
+func (a *app) telegramBotAddUserCommandHandler(ctx context.Context, chatID int64, arguments []string) error {
 	log := glogger.New(glogger.Caller{
 		Service:  os.Getenv(serviceNameEnvVarName),
 		Package:  packageName,
@@ -24,15 +31,14 @@ func (a *app) telegramBotAddUserCommandHandler(chatID int64, arguments []string)
 	errMsg := ""
 	defer func() {
 		if errMsg != "" {
-			if err := a.telegramBotSendMessage(requestUser, errMsg); err != nil {
+			if err := a.telegramBotSendMessage(ctx, requestUser, errMsg); err != nil {
 				log.Err(err).Error("error when sending telegram error message")
 			}
 		}
 	}()
 
-	log.Data("chatID", chatID).Debug("checking if the user is superadmin")
-	if !a.telegramBotUserIsSuperUser(chatID) {
-		err := ErrUnauthorizedToUseTelegramBotCommand
+	log.Data("chatID", chatID).Debug("checking if the chat is an admin")
+	if err := a.telegramBotRequireRole(ctx, chatID, types.RoleAdmin); err != nil {
 		errMsg = err.Error()
 		log.Data("chatID", chatID).Err(err).Error(errMsg)
 
@@ -63,12 +69,19 @@ func (a *app) telegramBotAddUserCommandHandler(chatID int64, arguments []string)
 		return err //nolint:wrapcheck
 	}
 
-	newUser := types.User{
-		ID:             generateUserID(),
-		TelegramChatID: otherChatID,
+	newUser, err := applyUserOptions(types.User{TelegramChatID: otherChatID}, arguments[1:], time.Now())
+	if err != nil {
+		errMsg = err.Error()
+
+		log.Data("data", map[string]interface{}{
+			"chatID":    chatID,
+			"arguments": arguments,
+		}).Err(err).Error("could not parse user options")
+
+		return err
 	}
 
-	if err := a.createUser(newUser); err != nil {
+	if err := a.createUser(ctx, newUser); err != nil {
 		errMsg = "error when creating user" //nolint:goconst
 		log.Err(err).Error(errMsg)
 
@@ -0,0 +1,203 @@
+package v1
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"os"
+	"strconv"
+	"strings"
+
+	tgbotapi "github.com/go-telegram-bot-api/telegram-bot-api/v5"
+	"github.com/psyb0t/glogger"
+	"github.com/psyb0t/telegram-logger/internal/pkg/storage"
+	"github.com/psyb0t/telegram-logger/internal/pkg/types"
+)
+
+type telegramBotCallbackAction string
+
+const (
+	telegramBotCallbackActionApproveRegistration telegramBotCallbackAction = "approveRegistration"
+	telegramBotCallbackActionRejectRegistration  telegramBotCallbackAction = "rejectRegistration"
+	telegramBotCallbackActionUsersPrevPage       telegramBotCallbackAction = "usersPrev"
+	telegramBotCallbackActionUsersNextPage       telegramBotCallbackAction = "usersNext"
+
+	telegramBotCallbackDataSeparator = ":"
+
+	telegramBotRegistrationApprovedByTpl = "\n\nApproved by %s"
+	telegramBotRegistrationRejectedByTpl = "\n\nRejected by %s"
+)
+
+// newTelegramBotCallbackData builds the data of an inline keyboard button
+// from the given action and argument. Telegram limits it to 64 bytes.
+func newTelegramBotCallbackData(action telegramBotCallbackAction, argument string) string {
+	return string(action) + telegramBotCallbackDataSeparator + argument
+}
+
+// parseTelegramBotCallbackData splits inline keyboard button
+// data built by newTelegramBotCallbackData.
+func parseTelegramBotCallbackData(data string) (telegramBotCallbackAction, string) {
+	action, argument, _ := strings.Cut(data, telegramBotCallbackDataSeparator)
+
+	return telegramBotCallbackAction(action), argument
+}
+
+// telegramBotCallbackQueryHandler handles the callback queries sent when
+// inline keyboard buttons are pressed. It invokes the function of the
+// button's action, answers the callback query with the outcome and, on
+// success, replaces the message of the button with an edited version
+// that has no buttons unless the action edits the message itself.
+//
+//nolint:funlen
+func (a *app) telegramBotCallbackQueryHandler(ctx context.Context, query *tgbotapi.CallbackQuery) error {
+	log := glogger.New(glogger.Caller{
+		Service:  os.Getenv(serviceNameEnvVarName),
+		Package:  packageName,
+		Receiver: "app",
+		Function: "telegramBotCallbackQueryHandler",
+	})
+
+	action, argument := parseTelegramBotCallbackData(query.Data)
+	fromChatID := getTelegramBotCallbackQueryChatID(query)
+
+	log.Data("data", map[string]interface{}{
+		"fromChatID": fromChatID,
+		"action":     action,
+		"argument":   argument,
+	}).Debug("handling callback query")
+
+	answer := ""
+	editedTextSuffixTpl := ""
+
+	var err error
+	switch action {
+	case telegramBotCallbackActionApproveRegistration, telegramBotCallbackActionRejectRegistration:
+		answer, editedTextSuffixTpl, err = a.telegramBotRegistrationCallbackQueryHandler(ctx, fromChatID, action, argument)
+	case telegramBotCallbackActionUsersPrevPage, telegramBotCallbackActionUsersNextPage:
+		answer, err = a.telegramBotUsersPageCallbackQueryHandler(ctx, fromChatID, query.Message, action, argument)
+	default:
+		err = ErrUnknownTelegramBotCallbackAction
+		answer = err.Error()
+	}
+
+	if err != nil {
+		log.Err(err).Error("an error occurred when handling the callback query")
+	}
+
+	if _, err := a.telegramBotAPI.Request(tgbotapi.NewCallback(query.ID, answer)); err != nil {
+		log.Err(err).Error("could not answer the callback query")
+	}
+
+	if editedTextSuffixTpl != "" && query.Message != nil {
+		edit := tgbotapi.NewEditMessageText(query.Message.Chat.ID, query.Message.MessageID,
+			query.Message.Text+fmt.Sprintf(editedTextSuffixTpl, getTelegramUserName(query.From)))
+
+		if _, err := a.telegramBotAPI.Send(edit); err != nil {
+			log.Err(err).Error("could not edit the callback query message")
+		}
+	}
+
+	return err
+}
+
+// getTelegramBotCallbackQueryChatID returns the ID of the chat whose role
+// is checked when handling the given callback query. Like for commands,
+// it's the chat the message with the buttons is in so that the buttons
+// work in admin groups. If Telegram didn't send the message along, the
+// private chat of the user who pressed the button is used instead.
+func getTelegramBotCallbackQueryChatID(query *tgbotapi.CallbackQuery) int64 {
+	if query.Message != nil && query.Message.Chat != nil {
+		return query.Message.Chat.ID
+	}
+
+	return query.From.ID
+}
+
+// telegramBotRegistrationCallbackQueryHandler approves or rejects the
+// registration of the chat ID given as the argument. It returns the
+// callback query answer and the template (formatted with the name of the
+// admin) of the text to append to the message of the pressed button.
+// Only admins can use it.
+func (a *app) telegramBotRegistrationCallbackQueryHandler(ctx context.Context, fromChatID int64,
+	action telegramBotCallbackAction, argument string,
+) (string, string, error) {
+	if err := a.telegramBotRequireRole(ctx, fromChatID, types.RoleAdmin); err != nil {
+		return err.Error(), "", err
+	}
+
+	chatID, err := strconv.ParseInt(argument, 10, 64)
+	if err != nil {
+		return "could not parse chat ID", "", err //nolint:wrapcheck
+	}
+
+	if action == telegramBotCallbackActionRejectRegistration {
+		if err := a.rejectRegistration(ctx, chatID); err != nil {
+			return registrationCallbackErrorAnswer(err), "", err
+		}
+
+		return "registration rejected", telegramBotRegistrationRejectedByTpl, nil
+	}
+
+	if err := a.approveRegistration(ctx, chatID); err != nil {
+		return registrationCallbackErrorAnswer(err), "", err
+	}
+
+	return "registration approved", telegramBotRegistrationApprovedByTpl, nil
+}
+
+// telegramBotUsersPageCallbackQueryHandler replaces the given message
+// with the page of users before or after the key given as the argument,
+// along with its buttons. It returns the callback query answer.
+// Only admins can use it.
+func (a *app) telegramBotUsersPageCallbackQueryHandler(ctx context.Context, fromChatID int64,
+	message *tgbotapi.Message, action telegramBotCallbackAction, argument string,
+) (string, error) {
+	if err := a.telegramBotRequireRole(ctx, fromChatID, types.RoleAdmin); err != nil {
+		return err.Error(), err
+	}
+
+	if message == nil {
+		return ErrTelegramBotCallbackQueryWithoutMessage.Error(), ErrTelegramBotCallbackQueryWithoutMessage
+	}
+
+	cursor := storage.Cursor{
+		StartKey: argument,
+		Limit:    telegramBotUsersPageSize,
+		Backward: action == telegramBotCallbackActionUsersPrevPage,
+	}
+
+	text, keyboard, err := a.getTelegramBotUsersPage(ctx, cursor)
+	if err != nil {
+		return "an error occurred when trying to get the users", err
+	}
+
+	edit := tgbotapi.NewEditMessageText(message.Chat.ID, message.MessageID, text)
+	edit.ReplyMarkup = keyboard
+
+	if _, err := a.telegramBotAPI.Send(edit); err != nil {
+		return "could not show the page", err //nolint:wrapcheck
+	}
+
+	return "", nil
+}
+
+func registrationCallbackErrorAnswer(err error) string {
+	if errors.Is(err, ErrRegistrationRequestNotFound) {
+		return err.Error()
+	}
+
+	return "error when handling the registration request"
+}
+
+// getTelegramUserName returns a human readable name of the given user.
+func getTelegramUserName(user *tgbotapi.User) string {
+	if user == nil {
+		return ""
+	}
+
+	if user.UserName != "" {
+		return "@" + user.UserName
+	}
+
+	return strings.TrimSpace(user.FirstName + " " + user.LastName)
+}
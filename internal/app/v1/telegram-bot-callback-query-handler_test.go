@@ -0,0 +1,148 @@
+package v1
+
+import (
+	"context"
+	"testing"
+
+	tgbotapi "github.com/go-telegram-bot-api/telegram-bot-api/v5"
+	"github.com/psyb0t/telegram-logger/internal/pkg/storage/memory"
+	"github.com/psyb0t/telegram-logger/internal/pkg/types"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestParseTelegramBotCallbackData(t *testing.T) {
+	tests := []struct {
+		name             string
+		data             string
+		expectedAction   telegramBotCallbackAction
+		expectedArgument string
+	}{
+		{
+			name:             "built data",
+			data:             newTelegramBotCallbackData(telegramBotCallbackActionApproveRegistration, "-100123"),
+			expectedAction:   telegramBotCallbackActionApproveRegistration,
+			expectedArgument: "-100123",
+		},
+		{
+			name:             "no argument",
+			data:             "rejectRegistration",
+			expectedAction:   telegramBotCallbackActionRejectRegistration,
+			expectedArgument: "",
+		},
+		{
+			name:             "empty",
+			data:             "",
+			expectedAction:   "",
+			expectedArgument: "",
+		},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			action, argument := parseTelegramBotCallbackData(test.data)
+			assert.Equal(t, test.expectedAction, action)
+			assert.Equal(t, test.expectedArgument, argument)
+		})
+	}
+}
+
+func TestGetTelegramBotCallbackQueryChatID(t *testing.T) {
+	from := &tgbotapi.User{ID: 5}
+
+	assert.Equal(t, int64(-100), getTelegramBotCallbackQueryChatID(&tgbotapi.CallbackQuery{
+		From:    from,
+		Message: &tgbotapi.Message{Chat: &tgbotapi.Chat{ID: -100}},
+	}))
+	assert.Equal(t, int64(5), getTelegramBotCallbackQueryChatID(&tgbotapi.CallbackQuery{From: from}))
+}
+
+func TestApp_telegramBotCallbackQueryHandler_adminGroup(t *testing.T) {
+	tests := []struct {
+		name        string
+		adminChatID int64
+		expectedErr error
+	}{
+		{
+			name:        "pressed in an admin group",
+			adminChatID: -100,
+		},
+		{
+			name:        "pressed in a member group by an admin",
+			adminChatID: 5,
+			expectedErr: ErrUnauthorizedToUseTelegramBotCommand,
+		},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			ctx := context.Background()
+
+			db, err := memory.New(ctx)
+			require.NoError(t, err)
+
+			telegramBotAPI, _ := newTestTelegramBotAPI(t)
+
+			a := &app{
+				config:         config{Auth: authConfig{TokenHashKey: "key"}},
+				db:             db,
+				telegramBotAPI: telegramBotAPI,
+			}
+
+			require.NoError(t, db.GetRoleRepositoryWriter().Set(ctx, types.ChatRole{
+				TelegramChatID: test.adminChatID,
+				Role:           types.RoleAdmin,
+			}))
+			require.NoError(t, db.GetUserRepositoryWriter().Create(ctx, types.User{
+				ID:             a.getPendingUserID(1),
+				TelegramChatID: 1,
+				Status:         types.UserStatusPending,
+			}))
+
+			// the role of the group the button is pressed in
+			// is checked, not the role of the user pressing it
+			err = a.telegramBotCallbackQueryHandler(ctx, &tgbotapi.CallbackQuery{
+				ID:      "query",
+				From:    &tgbotapi.User{ID: 5},
+				Message: &tgbotapi.Message{MessageID: 1, Chat: &tgbotapi.Chat{ID: -100}},
+				Data:    newTelegramBotCallbackData(telegramBotCallbackActionApproveRegistration, "1"),
+			})
+			assert.ErrorIs(t, err, test.expectedErr)
+
+			_, err = a.getPendingUser(ctx, db, 1)
+			if test.expectedErr == nil {
+				assert.ErrorIs(t, err, ErrRegistrationRequestNotFound)
+
+				return
+			}
+
+			assert.NoError(t, err)
+		})
+	}
+}
+
+func TestApp_telegramBotCallbackQueryHandler_usersPageInAdminGroup(t *testing.T) {
+	ctx := context.Background()
+
+	db, err := memory.New(ctx)
+	require.NoError(t, err)
+
+	telegramBotAPI, _ := newTestTelegramBotAPI(t)
+
+	a := &app{
+		config:         config{Auth: authConfig{TokenHashKey: "key"}},
+		db:             db,
+		telegramBotAPI: telegramBotAPI,
+	}
+
+	require.NoError(t, db.GetRoleRepositoryWriter().Set(ctx, types.ChatRole{TelegramChatID: -100, Role: types.RoleAdmin}))
+
+	// the user pressing the button isn't an admin, the group is
+	err = a.telegramBotCallbackQueryHandler(ctx, &tgbotapi.CallbackQuery{
+		ID:      "query",
+		From:    &tgbotapi.User{ID: 5},
+		Message: &tgbotapi.Message{MessageID: 1, Chat: &tgbotapi.Chat{ID: -100}},
+		Data:    newTelegramBotCallbackData(telegramBotCallbackActionUsersNextPage, ""),
+	})
+	assert.NoError(t, err)
+}
@@ -0,0 +1,238 @@
+package v1
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"os"
+	"strconv"
+	"strings"
+
+	tgbotapi "github.com/go-telegram-bot-api/telegram-bot-api/v5"
+	"github.com/psyb0t/glogger"
+	"github.com/psyb0t/telegram-logger/internal/pkg/storage"
+	"github.com/psyb0t/telegram-logger/internal/pkg/types"
+	"github.com/valyala/fasthttp"
+)
+
+const (
+	telegramBotChatMemberStatusMember        = "member"
+	telegramBotChatMemberStatusAdministrator = "administrator"
+	telegramBotChatMemberStatusCreator       = "creator"
+
+	telegramBotChatDeactivatedMessageTpl = `The bot was blocked or removed from chat %s.
+%d ID(s) used by it are deactivated until the bot is added back.`
+	telegramBotChatReactivatedMessageTpl = `The bot was added back to chat %s.
+%d ID(s) used by it are active again.`
+)
+
+// telegramBotRemovedErrorMessages are parts of the messages of the 403 errors
+// Telegram returns when the bot can't reach the chat anymore. Other 403s,
+// such as missing the rights to send messages, leave the bot in the chat.
+var telegramBotRemovedErrorMessages = []string{
+	"bot was blocked by the user",
+	"bot was kicked",
+	"user is deactivated",
+	"bot is not a member",
+}
+
+// isTelegramBotRemovedError checks if Telegram returned the given error
+// because the bot was blocked by the user or removed from the chat.
+func isTelegramBotRemovedError(err error) bool {
+	var telegramBotAPIErr *tgbotapi.Error
+	if !errors.As(err, &telegramBotAPIErr) || telegramBotAPIErr.Code != fasthttp.StatusForbidden {
+		return false
+	}
+
+	message := strings.ToLower(telegramBotAPIErr.Message)
+	for _, removedMessage := range telegramBotRemovedErrorMessages {
+		if strings.Contains(message, removedMessage) {
+			return true
+		}
+	}
+
+	return false
+}
+
+// telegramBotMyChatMemberHandler handles the updates Telegram sends when
+// the bot's status in a chat changes. The users of the chat are deactivated
+// when the bot is blocked or removed from the chat and reactivated
+// when it's unblocked or added back. When an admin adds the bot to a group
+// or channel that has no users yet, a user is created for it and its token
+// is sent privately to the admin.
+func (a *app) telegramBotMyChatMemberHandler(ctx context.Context, chatMemberUpdated *tgbotapi.ChatMemberUpdated) error {
+	chatID := chatMemberUpdated.Chat.ID
+	chatTitle := getTelegramChatTitle(&chatMemberUpdated.Chat)
+
+	switch chatMemberUpdated.NewChatMember.Status {
+	case telegramBotChatMemberStatusMember,
+		telegramBotChatMemberStatusAdministrator,
+		telegramBotChatMemberStatusCreator:
+		if err := a.reactivateTelegramChat(ctx, chatID, chatTitle); err != nil {
+			return err
+		}
+
+		if chatMemberUpdated.Chat.IsPrivate() || chatMemberUpdated.From.ID == 0 {
+			return nil
+		}
+
+		return a.autoRegisterTelegramChat(ctx, chatID, chatTitle, chatMemberUpdated.From.ID)
+	default:
+		if chatMemberUpdated.NewChatMember.HasLeft() || chatMemberUpdated.NewChatMember.WasKicked() {
+			return a.deactivateTelegramChat(ctx, chatID, chatTitle)
+		}
+	}
+
+	return nil
+}
+
+// autoRegisterTelegramChat creates a user for the given chat after the bot
+// was added to it by the given Telegram user and sends the token to the
+// user's private chat so that it's not posted in the chat itself. Nothing
+// happens if the user isn't an admin or the chat already has a user.
+func (a *app) autoRegisterTelegramChat(ctx context.Context, chatID int64, chatTitle string, addedBy int64) error {
+	log := glogger.New(glogger.Caller{
+		Service:  os.Getenv(serviceNameEnvVarName),
+		Package:  packageName,
+		Receiver: "app",
+		Function: "autoRegisterTelegramChat",
+	}).Data("chatID", chatID).Data("addedBy", addedBy)
+
+	// the private chat of a Telegram user has the same ID as the user
+	if err := a.telegramBotRequireRole(ctx, addedBy, types.RoleAdmin); err != nil {
+		log.Err(err).Info("not registering the chat since the bot wasn't added by an admin")
+
+		return nil
+	}
+
+	user, token := a.newUserToken(types.User{TelegramChatID: chatID, ChatTitle: chatTitle})
+
+	// the check and the creation happen in a single transaction so
+	// that a chat registered in the meantime isn't registered again
+	registered := false
+
+	err := a.db.InTx(ctx, func(tx storage.Repositories) error {
+		registered = false
+
+		users, err := tx.GetUserRepositoryReader().GetAllByTelegramChatID(ctx, chatID)
+		if err != nil {
+			return err //nolint:wrapcheck
+		}
+
+		for _, chatUser := range users {
+			if chatUser.Status != types.UserStatusPending {
+				return nil
+			}
+		}
+
+		registered = true
+
+		return replaceTelegramChatUsers(ctx, tx, user)
+	})
+	if err != nil {
+		log.Err(err).Error("error when registering the chat")
+
+		return err //nolint:wrapcheck
+	}
+
+	if !registered {
+		log.Debug("not registering the chat since it already has a user")
+
+		return nil
+	}
+
+	log.Info("registered the chat the bot was added to")
+
+	return a.sendUserToken(ctx, user, token, addedBy)
+}
+
+// deactivateTelegramChat marks the active users of the given chat as
+// inactive and lets the superuser know about it.
+func (a *app) deactivateTelegramChat(ctx context.Context, chatID int64, chatTitle string) error {
+	return a.changeTelegramChatUsersStatus(ctx, chatID, chatTitle,
+		types.User.IsActive, types.UserStatusInactive, telegramBotChatDeactivatedMessageTpl)
+}
+
+// reactivateTelegramChat marks the inactive users of the given chat as
+// active again and lets the superuser know about it.
+func (a *app) reactivateTelegramChat(ctx context.Context, chatID int64, chatTitle string) error {
+	isInactive := func(user types.User) bool {
+		return user.Status == types.UserStatusInactive
+	}
+
+	return a.changeTelegramChatUsersStatus(ctx, chatID, chatTitle,
+		isInactive, types.UserStatusActive, telegramBotChatReactivatedMessageTpl)
+}
+
+// changeTelegramChatUsersStatus sets the status of the users of the given
+// chat matching the given filter to the given status. If any user was
+// changed, the superuser is notified with the given message template.
+func (a *app) changeTelegramChatUsersStatus(ctx context.Context, chatID int64, chatTitle string,
+	filterFn func(types.User) bool, status types.UserStatus, notificationTpl string,
+) error {
+	log := glogger.New(glogger.Caller{
+		Service:  os.Getenv(serviceNameEnvVarName),
+		Package:  packageName,
+		Receiver: "app",
+		Function: "changeTelegramChatUsersStatus",
+	})
+
+	changed := 0
+
+	err := a.db.InTx(ctx, func(tx storage.Repositories) error {
+		changed = 0
+
+		users, err := tx.GetUserRepositoryReader().GetAllByTelegramChatID(ctx, chatID)
+		if err != nil {
+			log.Err(err).Error("error when getting the users of the chat")
+
+			return err //nolint:wrapcheck
+		}
+
+		for _, user := range users {
+			if !filterFn(user) {
+				continue
+			}
+
+			user.Status = status
+
+			log.Data("id", user.ID).Data("status", status).Info("changing user status")
+			if err := tx.GetUserRepositoryWriter().Update(ctx, user); err != nil {
+				log.Err(err).Error("error when changing the user status")
+
+				return err //nolint:wrapcheck
+			}
+
+			changed++
+		}
+
+		return nil
+	})
+	if err != nil {
+		return err //nolint:wrapcheck
+	}
+
+	if changed == 0 || a.config.TelegramBot.SuperuserChatID == 0 ||
+		a.config.TelegramBot.SuperuserChatID == chatID {
+		return nil
+	}
+
+	superuser := types.User{TelegramChatID: a.config.TelegramBot.SuperuserChatID}
+	msg := fmt.Sprintf(notificationTpl, formatTelegramChat(chatID, chatTitle), changed)
+	if err := a.telegramBotSendMessage(ctx, superuser, msg); err != nil {
+		log.Err(err).Error("could not notify the superuser")
+	}
+
+	return nil
+}
+
+// formatTelegramChat returns the given chat ID followed by
+// the given chat title, if any, for use in messages.
+func formatTelegramChat(chatID int64, chatTitle string) string {
+	chat := strconv.FormatInt(chatID, 10)
+	if chatTitle != "" {
+		chat += " (" + chatTitle + ")"
+	}
+
+	return chat
+}
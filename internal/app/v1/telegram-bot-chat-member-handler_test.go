@@ -0,0 +1,174 @@
+package v1
+
+import (
+	"context"
+	"testing"
+
+	tgbotapi "github.com/go-telegram-bot-api/telegram-bot-api/v5"
+	"github.com/psyb0t/telegram-logger/internal/pkg/storage"
+	"github.com/psyb0t/telegram-logger/internal/pkg/storage/memory"
+	"github.com/psyb0t/telegram-logger/internal/pkg/types"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/require"
+)
+
+func TestIsTelegramBotRemovedError(t *testing.T) {
+	assert.False(t, isTelegramBotRemovedError(nil))
+	assert.False(t, isTelegramBotRemovedError(errTestStorage))
+	assert.False(t, isTelegramBotRemovedError(&tgbotapi.Error{Code: 400, Message: "Bad Request: chat not found"}))
+	assert.True(t, isTelegramBotRemovedError(&tgbotapi.Error{Code: 403, Message: "Forbidden: bot was blocked by the user"}))
+	assert.True(t, isTelegramBotRemovedError(&tgbotapi.Error{
+		Code: 403, Message: "Forbidden: bot was kicked from the supergroup chat",
+	}))
+	assert.True(t, isTelegramBotRemovedError(&tgbotapi.Error{Code: 403, Message: "Forbidden: user is deactivated"}))
+	assert.True(t, isTelegramBotRemovedError(&tgbotapi.Error{
+		Code: 403, Message: "Forbidden: bot is not a member of the channel chat",
+	}))
+
+	// the bot is still in the chat
+	assert.False(t, isTelegramBotRemovedError(&tgbotapi.Error{
+		Code: 403, Message: "Forbidden: not enough rights to send text messages to the chat",
+	}))
+	assert.False(t, isTelegramBotRemovedError(&tgbotapi.Error{
+		Code: 403, Message: "Forbidden: bot can't initiate conversation with a user",
+	}))
+}
+
+func TestApp_telegramBotMyChatMemberHandler(t *testing.T) {
+	users := []types.User{
+		{ID: "active", TelegramChatID: 1},
+		{ID: "pending", TelegramChatID: 1, Status: types.UserStatusPending},
+		{ID: "inactive", TelegramChatID: 1, Status: types.UserStatusInactive},
+	}
+
+	tests := []struct {
+		name            string
+		status          string
+		expectedUpdates []types.User
+	}{
+		{
+			name:   "kicked",
+			status: "kicked",
+			expectedUpdates: []types.User{
+				{ID: "active", TelegramChatID: 1, Status: types.UserStatusInactive},
+			},
+		},
+		{
+			name:   "left",
+			status: "left",
+			expectedUpdates: []types.User{
+				{ID: "active", TelegramChatID: 1, Status: types.UserStatusInactive},
+			},
+		},
+		{
+			name:   "added back",
+			status: "member",
+			expectedUpdates: []types.User{
+				{ID: "inactive", TelegramChatID: 1, Status: types.UserStatusActive},
+			},
+		},
+		{
+			name:            "restricted",
+			status:          "restricted",
+			expectedUpdates: []types.User{},
+		},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			db := storage.NewMock()
+			userRepositoryReader, _ := db.GetUserRepositoryReader().(*storage.UserRepositoryReaderMock)
+			userRepositoryWriter, _ := db.GetUserRepositoryWriter().(*storage.UserRepositoryWriterMock)
+
+			userRepositoryReader.On("GetAllByTelegramChatID", int64(1)).Return(users, nil)
+			userRepositoryWriter.On("Update", mock.Anything).Return(nil)
+
+			a := &app{db: db}
+
+			err := a.telegramBotMyChatMemberHandler(context.Background(), &tgbotapi.ChatMemberUpdated{
+				Chat:          tgbotapi.Chat{ID: 1},
+				NewChatMember: tgbotapi.ChatMember{Status: test.status},
+			})
+			assert.NoError(t, err)
+
+			userRepositoryWriter.AssertNumberOfCalls(t, "Update", len(test.expectedUpdates))
+			for _, user := range test.expectedUpdates {
+				userRepositoryWriter.AssertCalled(t, "Update", user)
+			}
+		})
+	}
+}
+
+func TestApp_autoRegisterTelegramChat(t *testing.T) {
+	tests := []struct {
+		name    string
+		addedBy int64
+		users   []types.User
+	}{
+		{
+			name:    "not added by an admin",
+			addedBy: 3,
+		},
+		{
+			name:    "chat already has a user",
+			addedBy: 2,
+			users:   []types.User{{ID: "existing", TelegramChatID: 1}},
+		},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			db := storage.NewMock()
+			roleRepositoryReader, _ := db.GetRoleRepositoryReader().(*storage.RoleRepositoryReaderMock)
+			userRepositoryReader, _ := db.GetUserRepositoryReader().(*storage.UserRepositoryReaderMock)
+			userRepositoryWriter, _ := db.GetUserRepositoryWriter().(*storage.UserRepositoryWriterMock)
+
+			roleRepositoryReader.On("Get", int64(2)).Return(types.ChatRole{TelegramChatID: 2, Role: types.RoleAdmin}, nil)
+			roleRepositoryReader.On("Get", int64(3)).Return(types.ChatRole{}, storage.ErrNotFound)
+			userRepositoryReader.On("GetAllByTelegramChatID", int64(1)).Return(test.users, nil)
+
+			a := &app{db: db}
+
+			assert.NoError(t, a.autoRegisterTelegramChat(context.Background(), 1, "logs", test.addedBy))
+
+			userRepositoryWriter.AssertNotCalled(t, "Create", mock.Anything)
+		})
+	}
+}
+
+func TestApp_autoRegisterTelegramChat_registered(t *testing.T) {
+	ctx := context.Background()
+
+	db, err := memory.New(ctx)
+	require.NoError(t, err)
+
+	telegramBotAPI, fakeTelegramBotAPI := newTestTelegramBotAPI(t)
+
+	a := &app{
+		config:         config{Auth: authConfig{TokenHashKey: "key"}},
+		db:             db,
+		telegramBotAPI: telegramBotAPI,
+	}
+
+	require.NoError(t, db.GetRoleRepositoryWriter().Set(ctx, types.ChatRole{TelegramChatID: 2, Role: types.RoleAdmin}))
+
+	require.NoError(t, a.autoRegisterTelegramChat(ctx, -1001, "logs", 2))
+
+	users, err := db.GetUserRepositoryReader().GetAllByTelegramChatID(ctx, -1001)
+	require.NoError(t, err)
+	require.Len(t, users, 1)
+	assert.Equal(t, "logs", users[0].ChatTitle)
+	assert.True(t, users[0].IsActive())
+
+	// the token is only sent to the private chat of the admin who added the bot
+	sentMessages := fakeTelegramBotAPI.getSentMessages()
+	require.Len(t, sentMessages, 1)
+	assert.Equal(t, int64(2), sentMessages[0].ChatID)
+	assert.Contains(t, sentMessages[0].Text, formatTelegramChat(-1001, "logs"))
+}
+
+func TestFormatTelegramChat(t *testing.T) {
+	assert.Equal(t, "-100123", formatTelegramChat(-100123, ""))
+	assert.Equal(t, "-100123 (logs)", formatTelegramChat(-100123, "logs"))
+}
@@ -0,0 +1,70 @@
+package v1
+
+import (
+	"context"
+	"errors"
+	"os"
+
+	"github.com/psyb0t/glogger"
+	"github.com/psyb0t/telegram-logger/internal/pkg/types"
+)
+
+// telegramBotDBStatsCommandHandler handles the telegramBotDBStats command
+// of the telegram bot. Only admins can use it. It sends the statistics
+// of the storage, such as its size on disk and the maintenance it ran,
+// to the sender of the command.
+func (a *app) telegramBotDBStatsCommandHandler(ctx context.Context, chatID int64) error {
+	log := glogger.New(glogger.Caller{
+		Service:  os.Getenv(serviceNameEnvVarName),
+		Package:  packageName,
+		Receiver: "app",
+		Function: "telegramBotDBStatsCommandHandler",
+	})
+
+	log.Debug("handling command")
+
+	requestUser := types.User{TelegramChatID: chatID}
+
+	// define errMsg which is used to send a generic message to
+	// the sender of the command via telegram when an error occurs
+	// in the deferred function
+	errMsg := ""
+	defer func() {
+		if errMsg != "" {
+			if err := a.telegramBotSendMessage(ctx, requestUser, errMsg); err != nil {
+				log.Err(err).Error("error when sending telegram error message")
+			}
+		}
+	}()
+
+	log.Data("chatID", chatID).Debug("checking if the chat is an admin")
+	if err := a.telegramBotRequireRole(ctx, chatID, types.RoleAdmin); err != nil {
+		errMsg = err.Error()
+		log.Data("chatID", chatID).Err(err).Error(errMsg)
+
+		return err
+	}
+
+	log.Debug("getting the storage stats")
+	stats, err := a.getStorageStats(ctx)
+	if err != nil {
+		errMsg = "an error occurred when trying to get the database stats"
+		if errors.Is(err, ErrStorageStatsNotSupported) {
+			errMsg = err.Error()
+		}
+
+		log.Err(err).Error(errMsg)
+
+		return err
+	}
+
+	log.Debug("sending the response to the user")
+	if err := a.telegramBotSendMessage(ctx, requestUser, formatStorageStats(stats)); err != nil {
+		errMsg = "could not send telegram message"
+		log.Err(err).Error(errMsg)
+
+		return err
+	}
+
+	return nil
+}
@@ -0,0 +1,100 @@
+package v1
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"os"
+	"strconv"
+
+	"github.com/psyb0t/glogger"
+	"github.com/psyb0t/telegram-logger/internal/pkg/types"
+)
+
+const (
+	telegramBotDemoteMessageTpl = `Chat %d is now a member.`
+)
+
+// telegramBotDemoteCommandHandler handles the telegramBotDemote command of
+// the telegram bot. Only owners can use it. It makes the admin chat given
+// as the first argument a member again and lets the sender know about it.
+//
+//nolint:dupl
+func (a *app) telegramBotDemoteCommandHandler(ctx context.Context, chatID int64, arguments []string) error {
+	log := glogger.New(glogger.Caller{
+		Service:  os.Getenv(serviceNameEnvVarName),
+		Package:  packageName,
+		Receiver: "app",
+		Function: "telegramBotDemoteCommandHandler",
+	})
+
+	log.Debug("handling command")
+
+	requestUser := types.User{TelegramChatID: chatID}
+
+	// define errMsg which is used to send a generic message to
+	// the sender of the command via telegram when an error occurs
+	// in the deferred function
+	errMsg := ""
+	defer func() {
+		if errMsg != "" {
+			if err := a.telegramBotSendMessage(ctx, requestUser, errMsg); err != nil {
+				log.Err(err).Error("error when sending telegram error message")
+			}
+		}
+	}()
+
+	log.Data("chatID", chatID).Debug("checking if the chat is an owner")
+	if err := a.telegramBotRequireRole(ctx, chatID, types.RoleOwner); err != nil {
+		errMsg = err.Error()
+		log.Data("chatID", chatID).Err(err).Error(errMsg)
+
+		return err
+	}
+
+	if len(arguments) < 1 {
+		err := ErrInsufficientArguments
+		errMsg = err.Error()
+
+		log.Data("data", map[string]interface{}{
+			"chatID":    chatID,
+			"arguments": arguments,
+		}).Err(err).Error(errMsg)
+
+		return err
+	}
+
+	otherChatID, err := strconv.ParseInt(arguments[0], 10, 64)
+	if err != nil {
+		errMsg = "could not parse chat ID"
+
+		log.Data("data", map[string]interface{}{
+			"chatID":    chatID,
+			"arguments": arguments,
+		}).Err(err).Error(errMsg)
+
+		return err //nolint:wrapcheck
+	}
+
+	log.Data("otherChatID", otherChatID).Debug("changing the chat role")
+	if err := a.changeTelegramChatRole(ctx, otherChatID, types.RoleMember); err != nil {
+		errMsg = "error when changing the chat role"
+		if errors.Is(err, ErrCannotChangeOwnerRole) {
+			errMsg = err.Error()
+		}
+
+		log.Err(err).Error(errMsg)
+
+		return err
+	}
+
+	msg := fmt.Sprintf(telegramBotDemoteMessageTpl, otherChatID)
+	if err := a.telegramBotSendMessage(ctx, requestUser, msg); err != nil {
+		errMsg = "could not send telegram message"
+		log.Err(err).Error(errMsg)
+
+		return err
+	}
+
+	return nil
+}
@@ -0,0 +1,75 @@
+package v1
+
+import (
+	"context"
+	"encoding/json"
+	"os"
+
+	"github.com/psyb0t/glogger"
+	"github.com/psyb0t/telegram-logger/internal/pkg/types"
+)
+
+// telegramBotGetAllInvitesCommandHandler handles the telegramBotGetAllInvites
+// command of the telegram bot. Only admins can use it. It sends all of the
+// invites, including the revoked and used up ones along with who used
+// them, to the sender of the command.
+func (a *app) telegramBotGetAllInvitesCommandHandler(ctx context.Context, chatID int64) error {
+	log := glogger.New(glogger.Caller{
+		Service:  os.Getenv(serviceNameEnvVarName),
+		Package:  packageName,
+		Receiver: "app",
+		Function: "telegramBotGetAllInvitesCommandHandler",
+	})
+
+	log.Debug("handling command")
+
+	requestUser := types.User{TelegramChatID: chatID}
+
+	// define errMsg which is used to send a generic message to
+	// the sender of the command via telegram when an error occurs
+	// in the deferred function
+	errMsg := ""
+	defer func() {
+		if errMsg != "" {
+			if err := a.telegramBotSendMessage(ctx, requestUser, errMsg); err != nil {
+				log.Err(err).Error("error when sending telegram error message")
+			}
+		}
+	}()
+
+	log.Data("chatID", chatID).Debug("checking if the chat is an admin")
+	if err := a.telegramBotRequireRole(ctx, chatID, types.RoleAdmin); err != nil {
+		errMsg = err.Error()
+		log.Data("chatID", chatID).Err(err).Error(errMsg)
+
+		return err
+	}
+
+	log.Debug("getting all invites")
+	allInvites, err := a.db.GetInviteRepositoryReader().GetAll(ctx)
+	if err != nil {
+		errMsg = "an error occurred when trying to get all invites"
+		log.Err(err).Error(errMsg)
+
+		return err //nolint:wrapcheck
+	}
+
+	log.Data("allInvites", allInvites).Debug("serializing the resulting invites")
+	allInvitesJSON, err := json.MarshalIndent(allInvites, "", " ")
+	if err != nil {
+		errMsg = "error when serializing the resulting invites"
+		log.Err(err).Error(errMsg)
+
+		return err //nolint:wrapcheck
+	}
+
+	log.Debug("sending the response to the user")
+	if err := a.telegramBotSendMessage(ctx, requestUser, string(allInvitesJSON)); err != nil {
+		errMsg = "could not send telegram message"
+		log.Err(err).Error(errMsg)
+
+		return err
+	}
+
+	return nil
+}
@@ -1,22 +1,38 @@
 package v1
 
 import (
-	"encoding/json"
+	"context"
+	"fmt"
 	"os"
+	"strings"
+	"time"
 
+	tgbotapi "github.com/go-telegram-bot-api/telegram-bot-api/v5"
 	"github.com/psyb0t/glogger"
+	"github.com/psyb0t/telegram-logger/internal/pkg/storage"
 	"github.com/psyb0t/telegram-logger/internal/pkg/types"
 )
 
+const (
+	// telegramBotUsersPageSize is the number of users per page. It keeps
+	// the pages well under the 4096 characters Telegram allows per message.
+	telegramBotUsersPageSize = 10
+
+	telegramBotUsersPrevButton = "« Prev"
+	telegramBotUsersNextButton = "Next »"
+	telegramBotNoUsersMessage  = "There are no users"
+	telegramBotUserNeverExpire = "never"
+)
+
 // telegramBotGetAllUsersCommandHandler handles the telegramBotGetAllUsers
 // command of the telegram bot.
 //
-// It checks if the user who sent the command is a superadmin and if they are,
-// it retrieves all the users from the database, serializes them and sends the
-// result to the sender of the command. If the user is not a superadmin or
-// there's an error when getting the users from the database or serializing
-// them, it sends an error message to the sender.
-func (a *app) telegramBotGetAllUsersCommandHandler(chatID int64) error {
+// It checks if the chat that sent the command is an admin and if it is,
+// it sends the sender the first page of users with buttons for browsing
+// the other pages. If the chat is not an admin or there's an error when
+// getting the users from the database, it sends an error message to
+// the sender.
+func (a *app) telegramBotGetAllUsersCommandHandler(ctx context.Context, chatID int64) error {
 	log := glogger.New(glogger.Caller{
 		Service:  os.Getenv(serviceNameEnvVarName),
 		Package:  packageName,
@@ -34,41 +50,36 @@ func (a *app) telegramBotGetAllUsersCommandHandler(chatID int64) error {
 	errMsg := ""
 	defer func() {
 		if errMsg != "" {
-			if err := a.telegramBotSendMessage(requestUser, errMsg); err != nil {
+			if err := a.telegramBotSendMessage(ctx, requestUser, errMsg); err != nil {
 				log.Err(err).Error("error when sending telegram error message")
 			}
 		}
 	}()
 
-	log.Data("chatID", chatID).Debug("checking if the user is superadmin")
-	if !a.telegramBotUserIsSuperUser(chatID) {
-		err := ErrUnauthorizedToUseTelegramBotCommand
+	log.Data("chatID", chatID).Debug("checking if the chat is an admin")
+	if err := a.telegramBotRequireRole(ctx, chatID, types.RoleAdmin); err != nil {
 		errMsg = err.Error()
 		log.Data("chatID", chatID).Err(err).Error(errMsg)
 
 		return err
 	}
 
-	log.Debug("getting all users")
-	allUsers, err := a.db.GetUserRepositoryReader().GetAll()
+	log.Debug("getting the first page of users")
+	text, keyboard, err := a.getTelegramBotUsersPage(ctx, storage.Cursor{Limit: telegramBotUsersPageSize})
 	if err != nil {
-		errMsg = "an error occurred when trying to get all users"
+		errMsg = "an error occurred when trying to get the users"
 		log.Err(err).Error(errMsg)
 
 		return err
 	}
 
-	log.Data("allUsers", allUsers).Debug("serializing the resulting users")
-	allUsersJSON, err := json.MarshalIndent(allUsers, "", " ")
-	if err != nil {
-		errMsg = "error when serializing the resulting users"
-		log.Err(err).Error(errMsg)
-
-		return err
+	log.Debug("sending the response to the user")
+	m := tgbotapi.NewMessage(chatID, text)
+	if keyboard != nil {
+		m.ReplyMarkup = *keyboard
 	}
 
-	log.Debug("sending the response to the user")
-	if err := a.telegramBotSendMessage(requestUser, string(allUsersJSON)); err != nil {
+	if err := a.telegramBotSendMessageConfig(ctx, m); err != nil {
 		errMsg = "could not send telegram message"
 		log.Err(err).Error(errMsg)
 
@@ -77,3 +88,91 @@ func (a *app) telegramBotGetAllUsersCommandHandler(chatID int64) error {
 
 	return nil
 }
+
+// getTelegramBotUsersPage returns the text of the page of users selected
+// by the given cursor and the keyboard with the buttons for the previous
+// and the next pages, if there are any. The keyboard is nil if there are
+// no other pages. If the page is empty because the users it started with
+// were deleted, the first page is returned instead.
+func (a *app) getTelegramBotUsersPage(ctx context.Context,
+	cursor storage.Cursor,
+) (string, *tgbotapi.InlineKeyboardMarkup, error) {
+	reader := a.db.GetUserRepositoryReader()
+
+	users, err := reader.GetPage(ctx, cursor)
+	if err != nil {
+		return "", nil, err //nolint:wrapcheck
+	}
+
+	if len(users) == 0 && cursor.StartKey != "" {
+		return a.getTelegramBotUsersPage(ctx, storage.Cursor{Limit: cursor.Limit})
+	}
+
+	if len(users) == 0 {
+		return telegramBotNoUsersMessage, nil, nil
+	}
+
+	first, last := users[0], users[len(users)-1]
+
+	// the page before ends with the user right before the first one
+	before, err := reader.GetPage(ctx, storage.Cursor{StartKey: first.ID, Limit: 1, Backward: true})
+	if err != nil {
+		return "", nil, err //nolint:wrapcheck
+	}
+
+	// the page after starts with the user right after the last one
+	// which is the second one of this page unless it was deleted
+	after, err := reader.GetPage(ctx, storage.Cursor{StartKey: last.ID, Limit: 2})
+	if err != nil {
+		return "", nil, err //nolint:wrapcheck
+	}
+
+	if len(after) > 0 && after[0].ID == last.ID {
+		after = after[1:]
+	}
+
+	buttons := []tgbotapi.InlineKeyboardButton{}
+
+	if len(before) > 0 {
+		buttons = append(buttons, tgbotapi.NewInlineKeyboardButtonData(telegramBotUsersPrevButton,
+			newTelegramBotCallbackData(telegramBotCallbackActionUsersPrevPage,
+				getShortestKeyBetween(before[0].ID, first.ID))))
+	}
+
+	if len(after) > 0 {
+		buttons = append(buttons, tgbotapi.NewInlineKeyboardButtonData(telegramBotUsersNextButton,
+			newTelegramBotCallbackData(telegramBotCallbackActionUsersNextPage,
+				getShortestKeyBetween(last.ID, after[0].ID))))
+	}
+
+	text := formatTelegramBotUsersPage(users)
+	if len(buttons) == 0 {
+		return text, nil, nil
+	}
+
+	keyboard := tgbotapi.NewInlineKeyboardMarkup(buttons)
+
+	return text, &keyboard, nil
+}
+
+// formatTelegramBotUsersPage returns the text of a page listing the given users.
+func formatTelegramBotUsersPage(users []types.User) string {
+	entries := make([]string, 0, len(users))
+
+	for _, user := range users {
+		status := user.Status
+		if status == "" {
+			status = types.UserStatusActive
+		}
+
+		expiresAt := telegramBotUserNeverExpire
+		if user.ExpiresAt != nil {
+			expiresAt = user.ExpiresAt.UTC().Format(time.RFC3339)
+		}
+
+		entries = append(entries, fmt.Sprintf("%s (%d)\nID: %s\nStatus: %s\nExpires: %s",
+			user.ChatTitle, user.TelegramChatID, user.ID, status, expiresAt))
+	}
+
+	return strings.Join(entries, "\n\n")
+}
@@ -1 +1,124 @@
 package v1
+
+import (
+	"context"
+	"sort"
+	"strconv"
+	"strings"
+	"testing"
+
+	tgbotapi "github.com/go-telegram-bot-api/telegram-bot-api/v5"
+	"github.com/psyb0t/telegram-logger/internal/pkg/storage"
+	"github.com/psyb0t/telegram-logger/internal/pkg/storage/memory"
+	"github.com/psyb0t/telegram-logger/internal/pkg/types"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestApp_getTelegramBotUsersPage(t *testing.T) {
+	ctx := context.Background()
+
+	db, err := memory.New(ctx)
+	require.NoError(t, err)
+	require.NoError(t, db.Open(ctx, ""))
+
+	defer db.Close()
+
+	a := &app{db: db}
+
+	text, keyboard, err := a.getTelegramBotUsersPage(ctx, storage.Cursor{Limit: 2})
+	require.NoError(t, err)
+	assert.Equal(t, telegramBotNoUsersMessage, text)
+	assert.Nil(t, keyboard)
+
+	ids := []string{}
+
+	for i := 0; i < 5; i++ {
+		id := hashToken("key", strconv.Itoa(i))
+		ids = append(ids, id)
+
+		require.NoError(t, db.GetUserRepositoryWriter().Create(ctx, types.User{ID: id, TelegramChatID: int64(i + 1)}))
+	}
+
+	sort.Strings(ids)
+
+	// getPageIDs returns the IDs of the users listed in the given page
+	getPageIDs := func(text string) []string {
+		pageIDs := []string{}
+
+		for _, id := range ids {
+			if strings.Contains(text, id) {
+				pageIDs = append(pageIDs, id)
+			}
+		}
+
+		return pageIDs
+	}
+
+	// getButtonCursor returns the cursor of the button with the given text
+	getButtonCursor := func(keyboard *tgbotapi.InlineKeyboardMarkup, buttonText string) (storage.Cursor, bool) {
+		for _, button := range keyboard.InlineKeyboard[0] {
+			if button.Text != buttonText {
+				continue
+			}
+
+			// Telegram limits the data to 64 bytes
+			assert.LessOrEqual(t, len(*button.CallbackData), 64)
+
+			action, argument := parseTelegramBotCallbackData(*button.CallbackData)
+
+			return storage.Cursor{
+				StartKey: argument,
+				Limit:    2,
+				Backward: action == telegramBotCallbackActionUsersPrevPage,
+			}, true
+		}
+
+		return storage.Cursor{}, false
+	}
+
+	// go through the pages forward
+	text, keyboard, err = a.getTelegramBotUsersPage(ctx, storage.Cursor{Limit: 2})
+	require.NoError(t, err)
+	assert.Equal(t, ids[:2], getPageIDs(text))
+
+	_, ok := getButtonCursor(keyboard, telegramBotUsersPrevButton)
+	assert.False(t, ok)
+
+	cursor, ok := getButtonCursor(keyboard, telegramBotUsersNextButton)
+	require.True(t, ok)
+
+	text, keyboard, err = a.getTelegramBotUsersPage(ctx, cursor)
+	require.NoError(t, err)
+	assert.Equal(t, ids[2:4], getPageIDs(text))
+
+	cursor, ok = getButtonCursor(keyboard, telegramBotUsersNextButton)
+	require.True(t, ok)
+
+	text, keyboard, err = a.getTelegramBotUsersPage(ctx, cursor)
+	require.NoError(t, err)
+	assert.Equal(t, ids[4:], getPageIDs(text))
+
+	_, ok = getButtonCursor(keyboard, telegramBotUsersNextButton)
+	assert.False(t, ok)
+
+	// and back
+	cursor, ok = getButtonCursor(keyboard, telegramBotUsersPrevButton)
+	require.True(t, ok)
+
+	text, keyboard, err = a.getTelegramBotUsersPage(ctx, cursor)
+	require.NoError(t, err)
+	assert.Equal(t, ids[2:4], getPageIDs(text))
+
+	cursor, ok = getButtonCursor(keyboard, telegramBotUsersPrevButton)
+	require.True(t, ok)
+
+	text, _, err = a.getTelegramBotUsersPage(ctx, cursor)
+	require.NoError(t, err)
+	assert.Equal(t, ids[:2], getPageIDs(text))
+
+	// a page past the deleted users falls back to the first one
+	text, _, err = a.getTelegramBotUsersPage(ctx, storage.Cursor{StartKey: "g", Limit: 2})
+	require.NoError(t, err)
+	assert.Equal(t, ids[:2], getPageIDs(text))
+}
@@ -0,0 +1,101 @@
+package v1
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"os"
+	"time"
+
+	"github.com/psyb0t/glogger"
+	"github.com/psyb0t/telegram-logger/internal/pkg/types"
+)
+
+const (
+	telegramBotInviteMessageTpl = `Invite created!
+Code: %s
+Uses: %d
+Expires at: %s
+
+Send it to the chat you want to invite. It registers by sending
+/start %s`
+	telegramBotInviteLinkMessageTpl = `
+or by opening https://t.me/%s?start=%s`
+)
+
+// telegramBotInviteCommandHandler handles the telegramBotInvite command of
+// the telegram bot. Only admins can use it. It creates an invite from the
+// key=value options given as the arguments (see newInvite) and sends
+// its code to the sender. The code is only shown this once.
+//
+//nolint:funlen
+func (a *app) telegramBotInviteCommandHandler(ctx context.Context, chatID int64, arguments []string) error {
+	log := glogger.New(glogger.Caller{
+		Service:  os.Getenv(serviceNameEnvVarName),
+		Package:  packageName,
+		Receiver: "app",
+		Function: "telegramBotInviteCommandHandler",
+	})
+
+	log.Debug("handling command")
+
+	requestUser := types.User{TelegramChatID: chatID}
+
+	// define errMsg which is used to send a generic message to
+	// the sender of the command via telegram when an error occurs
+	// in the deferred function
+	errMsg := ""
+	defer func() {
+		if errMsg != "" {
+			if err := a.telegramBotSendMessage(ctx, requestUser, errMsg); err != nil {
+				log.Err(err).Error("error when sending telegram error message")
+			}
+		}
+	}()
+
+	log.Data("chatID", chatID).Debug("checking if the chat is an admin")
+	if err := a.telegramBotRequireRole(ctx, chatID, types.RoleAdmin); err != nil {
+		errMsg = err.Error()
+		log.Data("chatID", chatID).Err(err).Error(errMsg)
+
+		return err
+	}
+
+	invite, code, err := a.newInvite(chatID, arguments, time.Now())
+	if err != nil {
+		errMsg = "error when creating invite"
+		if errors.Is(err, ErrInvalidInviteOption) || errors.Is(err, ErrInvalidUserOption) {
+			errMsg = err.Error()
+		}
+
+		log.Data("data", map[string]interface{}{
+			"chatID":    chatID,
+			"arguments": arguments,
+		}).Err(err).Error("could not parse invite options")
+
+		return err
+	}
+
+	log.Data("invite", invite).Debug("creating invite")
+	if err := a.db.GetInviteRepositoryWriter().Create(ctx, invite); err != nil {
+		errMsg = "error when creating invite"
+		log.Err(err).Error(errMsg)
+
+		return err //nolint:wrapcheck
+	}
+
+	msg := fmt.Sprintf(telegramBotInviteMessageTpl, code, invite.MaxUses,
+		invite.ExpiresAt.Format(time.RFC1123), code)
+	if a.telegramBotAPI != nil && a.telegramBotAPI.Self.UserName != "" {
+		msg += fmt.Sprintf(telegramBotInviteLinkMessageTpl, a.telegramBotAPI.Self.UserName, code)
+	}
+
+	if err := a.telegramBotSendMessage(ctx, requestUser, msg); err != nil {
+		errMsg = "could not send telegram message"
+		log.Err(err).Error(errMsg)
+
+		return err
+	}
+
+	return nil
+}
@@ -1,9 +1,11 @@
 package v1
 
 import (
+	"context"
 	"fmt"
 	"os"
 	"strings"
+	"time"
 
 	tgbotapi "github.com/go-telegram-bot-api/telegram-bot-api/v5" //nolint:depguard
 	"github.com/psyb0t/glogger"
@@ -13,20 +15,31 @@ import (
 type telegramBotCommand string
 
 const (
-	telegramBotStartCommand telegramBotCommand = "/start"
-	telegramBotStopCommand  telegramBotCommand = "/stop"
-	telegramBotGetAllUsers  telegramBotCommand = "/getAllUsers"
-	telegramBotAddUser      telegramBotCommand = "/addUser"
+	telegramBotStartCommand  telegramBotCommand = "/start"
+	telegramBotStopCommand   telegramBotCommand = "/stop"
+	telegramBotGetAllUsers   telegramBotCommand = "/getAllUsers"
+	telegramBotAddUser       telegramBotCommand = "/addUser"
+	telegramBotPromote       telegramBotCommand = "/promote"
+	telegramBotDemote        telegramBotCommand = "/demote"
+	telegramBotInvite        telegramBotCommand = "/invite"
+	telegramBotGetAllInvites telegramBotCommand = "/getAllInvites"
+	telegramBotRevokeInvite  telegramBotCommand = "/revokeInvite"
+	telegramBotDBStats       telegramBotCommand = "/dbstats"
 )
 
-// telegramBotMessageHandler is responsible for handling incoming messages
-// from Telegram. It listens to a channel of updates and processes each
-// message as they come in. If the message is a command (e.g. "/start" or "/stop"),
-// it invokes the corresponding command handler function. If the message is
-// not a command, it does nothing.
+// telegramBotMessageHandler is responsible for handling incoming updates
+// from Telegram. It listens to a channel of updates, fed either by long
+// polling or by the webhook HTTP handler when a webhook is configured,
+// and passes each update on to telegramBotUpdateHandler one at a time.
+// The webhook is registered on start and deleted when the handler stops.
+//
+// The ID of the last processed update is stored so that long polling
+// resumes from it after a restart and updates delivered twice are
+// ignored. Updates older than telegramBot.maxUpdateAge are skipped
+// so that stale commands aren't replayed.
 // This function should be run in a separate goroutine.
 //
-//nolint:funlen,gocognit,cyclop
+//nolint:funlen,cyclop
 func (a *app) telegramBotMessageHandler() error {
 	log := glogger.New(glogger.Caller{
 		Service:  os.Getenv(serviceNameEnvVarName),
@@ -35,72 +48,221 @@ func (a *app) telegramBotMessageHandler() error {
 		Function: "telegramBotMessageHandler",
 	})
 
-	u := tgbotapi.NewUpdate(0)
-	u.Timeout = 60
+	offset, err := a.getTelegramBotUpdateOffset(a.ctx)
+	if err != nil {
+		log.Err(err).Error("error when getting the telegram bot update offset")
+
+		return err
+	}
+
+	var updates tgbotapi.UpdatesChannel
+
+	if a.config.TelegramBot.Webhook.URL != "" {
+		log.Info("registering the telegram bot webhook")
+		if err := a.setTelegramBotWebhook(); err != nil {
+			log.Err(err).Error("error when registering the telegram bot webhook")
+
+			return err
+		}
+
+		defer a.deleteTelegramBotWebhook()
 
-	updates := a.telegramBotAPI.GetUpdatesChan(u)
+		updates = a.telegramBotWebhookUpdates
+	} else {
+		log.Data("offset", offset).Info("resuming telegram bot updates")
+		u := tgbotapi.NewUpdate(offset)
+		u.Timeout = 60
+
+		updates = a.telegramBotAPI.GetUpdatesChan(u)
+	}
 
 	for {
 		select {
 		case <-a.ctx.Done():
 			return a.ctx.Err() //nolint:wrapcheck
 		case update := <-updates:
-			if update.Message == nil {
+			if update.UpdateID < offset {
+				log.Data("updateID", update.UpdateID).Debug("skipping already processed update")
+
 				continue
 			}
-			chatID := update.Message.Chat.ID
-			data := update.Message.Text
 
-			parts := strings.Fields(data)
-
-			var command string
-			var arguments []string //nolint:wsl
+			// the offset is stored before handling the update so that
+			// an update that breaks the app isn't processed again
+			offset = update.UpdateID + 1
+			if err := a.setTelegramBotUpdateOffset(a.ctx, offset); err != nil {
+				log.Err(err).Error("error when storing the telegram bot update offset")
+			}
 
-			if len(parts) > 0 {
-				command = parts[0]
+			if telegramBotUpdateIsStale(update, time.Now(), a.config.TelegramBot.MaxUpdateAge) {
+				log.Data("updateID", update.UpdateID).Info("skipping stale update")
 
-				if len(parts) > 1 {
-					arguments = parts[1:]
-				}
+				continue
 			}
 
-			log.Debug(fmt.Sprintf("telegram message received: chat id: %d - username: %s - message: %s",
-				chatID, update.Message.From.UserName, update.Message.Text))
-
-			switch telegramBotCommand(command) {
-			case telegramBotStartCommand:
-				err := a.telegramBotStartCommandHandler(chatID)
-				if err != nil {
-					log.Err(err).Error("an error occurred when handling start the command")
-				}
-			case telegramBotStopCommand:
-				err := a.telegramBotStopCommandHandler(chatID)
-				if err != nil {
-					log.Err(err).Error("an error occurred when handling the stop command")
-				}
-			case telegramBotGetAllUsers:
-				err := a.telegramBotGetAllUsersCommandHandler(chatID)
-				if err != nil {
-					log.Err(err).Error("an error occurred when handling the get all users command")
-				}
-			case telegramBotAddUser:
-				err := a.telegramBotAddUserCommandHandler(chatID, arguments)
-				if err != nil {
-					log.Err(err).Error("an error occurred when handling the add user command")
-				}
-			default:
-			}
+			a.telegramBotUpdateHandler(a.ctx, update)
 		}
 	}
 }
 
-func (a *app) telegramBotSendMessage(user types.User, msg string) error {
-	m := tgbotapi.NewMessage(user.TelegramChatID, msg)
-	_, err := a.telegramBotAPI.Send(m)
+// telegramBotUpdateHandler processes an update received from Telegram.
+// If the update is a message with a command (e.g. "/start" or "/stop"),
+// it invokes the corresponding command handler function. If the message is
+// not a command, it does nothing. Callback queries (inline keyboard button
+// presses) are passed on to the callback query handler.
+//
+//nolint:funlen,gocognit,cyclop
+func (a *app) telegramBotUpdateHandler(ctx context.Context, update tgbotapi.Update) {
+	log := glogger.New(glogger.Caller{
+		Service:  os.Getenv(serviceNameEnvVarName),
+		Package:  packageName,
+		Receiver: "app",
+		Function: "telegramBotUpdateHandler",
+	})
 
-	return err //nolint:wrapcheck
+	if update.CallbackQuery != nil {
+		if err := a.telegramBotCallbackQueryHandler(ctx, update.CallbackQuery); err != nil {
+			log.Err(err).Error("an error occurred when handling the callback query")
+		}
+
+		return
+	}
+
+	if update.MyChatMember != nil {
+		if err := a.telegramBotMyChatMemberHandler(ctx, update.MyChatMember); err != nil {
+			log.Err(err).Error("an error occurred when handling the chat member update")
+		}
+
+		return
+	}
+
+	if update.Message == nil {
+		return
+	}
+
+	// the old group sends the ID of the supergroup it was upgraded to
+	// and the supergroup sends the ID of the group it was upgraded from
+	if update.Message.MigrateToChatID != 0 || update.Message.MigrateFromChatID != 0 {
+		oldChatID, newChatID := update.Message.Chat.ID, update.Message.MigrateToChatID
+		if update.Message.MigrateFromChatID != 0 {
+			oldChatID, newChatID = update.Message.MigrateFromChatID, update.Message.Chat.ID
+		}
+
+		if err := a.migrateTelegramChat(ctx, oldChatID, newChatID); err != nil {
+			log.Err(err).Error("an error occurred when migrating the telegram chat")
+		}
+
+		return
+	}
+	chatID := update.Message.Chat.ID
+	data := update.Message.Text
+
+	parts := strings.Fields(data)
+
+	var command string
+	var arguments []string //nolint:wsl
+
+	if len(parts) > 0 {
+		command = parts[0]
+
+		if len(parts) > 1 {
+			arguments = parts[1:]
+		}
+	}
+
+	log.Debug(fmt.Sprintf("telegram message received: chat id: %d - username: %s - message: %s",
+		chatID, update.Message.From.UserName, update.Message.Text))
+
+	switch telegramBotCommand(command) {
+	case telegramBotStartCommand:
+		err := a.telegramBotStartCommandHandler(ctx, chatID, getTelegramChatTitle(update.Message.Chat), arguments)
+		if err != nil {
+			log.Err(err).Error("an error occurred when handling start the command")
+		}
+	case telegramBotStopCommand:
+		err := a.telegramBotStopCommandHandler(ctx, chatID)
+		if err != nil {
+			log.Err(err).Error("an error occurred when handling the stop command")
+		}
+	case telegramBotGetAllUsers:
+		err := a.telegramBotGetAllUsersCommandHandler(ctx, chatID)
+		if err != nil {
+			log.Err(err).Error("an error occurred when handling the get all users command")
+		}
+	case telegramBotAddUser:
+		err := a.telegramBotAddUserCommandHandler(ctx, chatID, arguments)
+		if err != nil {
+			log.Err(err).Error("an error occurred when handling the add user command")
+		}
+	case telegramBotPromote:
+		err := a.telegramBotPromoteCommandHandler(ctx, chatID, arguments)
+		if err != nil {
+			log.Err(err).Error("an error occurred when handling the promote command")
+		}
+	case telegramBotDemote:
+		err := a.telegramBotDemoteCommandHandler(ctx, chatID, arguments)
+		if err != nil {
+			log.Err(err).Error("an error occurred when handling the demote command")
+		}
+	case telegramBotInvite:
+		err := a.telegramBotInviteCommandHandler(ctx, chatID, arguments)
+		if err != nil {
+			log.Err(err).Error("an error occurred when handling the invite command")
+		}
+	case telegramBotGetAllInvites:
+		err := a.telegramBotGetAllInvitesCommandHandler(ctx, chatID)
+		if err != nil {
+			log.Err(err).Error("an error occurred when handling the get all invites command")
+		}
+	case telegramBotRevokeInvite:
+		err := a.telegramBotRevokeInviteCommandHandler(ctx, chatID, arguments)
+		if err != nil {
+			log.Err(err).Error("an error occurred when handling the revoke invite command")
+		}
+	case telegramBotDBStats:
+		err := a.telegramBotDBStatsCommandHandler(ctx, chatID)
+		if err != nil {
+			log.Err(err).Error("an error occurred when handling the db stats command")
+		}
+	default:
+	}
+}
+
+// getTelegramChatTitle returns a human readable name of the given chat.
+func getTelegramChatTitle(chat *tgbotapi.Chat) string {
+	switch {
+	case chat == nil:
+		return ""
+	case chat.Title != "":
+		return chat.Title
+	case chat.UserName != "":
+		return "@" + chat.UserName
+	default:
+		return strings.TrimSpace(chat.FirstName + " " + chat.LastName)
+	}
+}
+
+func (a *app) telegramBotSendMessage(ctx context.Context, user types.User, msg string) error {
+	return a.telegramBotSendMessageConfig(ctx, tgbotapi.NewMessage(user.TelegramChatID, msg))
 }
 
-func (a *app) telegramBotUserIsSuperUser(chatID int64) bool {
-	return chatID == a.config.TelegramBot.SuperuserChatID
+// telegramBotSendMessageConfig sends the given message. If the chat is a
+// group that was upgraded to a supergroup, the chat is migrated to
+// the supergroup's chat ID and the message is sent there instead.
+func (a *app) telegramBotSendMessageConfig(ctx context.Context, m tgbotapi.MessageConfig) error {
+	_, err := a.telegramBotAPI.Send(m)
+
+	newChatID := getTelegramBotMigrateToChatID(err)
+	if newChatID == 0 {
+		return err //nolint:wrapcheck
+	}
+
+	if err := a.migrateTelegramChat(ctx, m.ChatID, newChatID); err != nil {
+		return err
+	}
+
+	m.ChatID = newChatID
+	_, err = a.telegramBotAPI.Send(m)
+
+	return err //nolint:wrapcheck
 }
@@ -0,0 +1,88 @@
+package v1
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"os"
+	"time"
+
+	"github.com/psyb0t/glogger"
+	"github.com/psyb0t/telegram-logger/internal/pkg/types"
+)
+
+const (
+	telegramBotRevokeInviteMessageTpl = `Invite %s is revoked.`
+)
+
+// telegramBotRevokeInviteCommandHandler handles the telegramBotRevokeInvite
+// command of the telegram bot. Only admins can use it. It revokes the
+// invite whose code or ID is given as the first argument so that it
+// can't be used anymore.
+func (a *app) telegramBotRevokeInviteCommandHandler(ctx context.Context, chatID int64, arguments []string) error {
+	log := glogger.New(glogger.Caller{
+		Service:  os.Getenv(serviceNameEnvVarName),
+		Package:  packageName,
+		Receiver: "app",
+		Function: "telegramBotRevokeInviteCommandHandler",
+	})
+
+	log.Debug("handling command")
+
+	requestUser := types.User{TelegramChatID: chatID}
+
+	// define errMsg which is used to send a generic message to
+	// the sender of the command via telegram when an error occurs
+	// in the deferred function
+	errMsg := ""
+	defer func() {
+		if errMsg != "" {
+			if err := a.telegramBotSendMessage(ctx, requestUser, errMsg); err != nil {
+				log.Err(err).Error("error when sending telegram error message")
+			}
+		}
+	}()
+
+	log.Data("chatID", chatID).Debug("checking if the chat is an admin")
+	if err := a.telegramBotRequireRole(ctx, chatID, types.RoleAdmin); err != nil {
+		errMsg = err.Error()
+		log.Data("chatID", chatID).Err(err).Error(errMsg)
+
+		return err
+	}
+
+	if len(arguments) < 1 {
+		err := ErrInsufficientArguments
+		errMsg = err.Error()
+
+		log.Data("data", map[string]interface{}{
+			"chatID":    chatID,
+			"arguments": arguments,
+		}).Err(err).Error(errMsg)
+
+		return err
+	}
+
+	log.Data("chatID", chatID).Debug("revoking invite")
+	invite, err := a.revokeInvite(ctx, chatID, arguments[0], time.Now())
+	if err != nil {
+		errMsg = "error when revoking invite"
+		if errors.Is(err, ErrInviteNotFound) {
+			errMsg = err.Error()
+		}
+
+		log.Err(err).Error(errMsg)
+
+		return err
+	}
+
+	msg := fmt.Sprintf(telegramBotRevokeInviteMessageTpl, invite.ID)
+	if err := a.telegramBotSendMessage(ctx, requestUser, msg); err != nil {
+		errMsg = "could not send telegram message"
+		log.Err(err).Error(errMsg)
+
+		return err
+	}
+
+	return nil
+}
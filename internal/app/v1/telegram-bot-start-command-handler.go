@@ -1,23 +1,48 @@
 package v1
 
 import (
+	"context"
+	"errors"
 	"fmt"
 	"os"
+	"time"
 
 	"github.com/psyb0t/glogger"
+	"github.com/psyb0t/telegram-logger/internal/pkg/storage"
 	"github.com/psyb0t/telegram-logger/internal/pkg/types"
 )
 
 const (
 	telegramBotWelcomeMessageTpl = `Welcome!
-Your ID is %s`
+Your ID is %s
+
+To sign requests instead of sending the ID use
+key ID: %s
+signing secret: %s
+
+Keep them safe, they will not be shown again.`
+	telegramBotWelcomeMessageExpiryTpl = `
+It expires at %s.`
+	telegramBotWelcomeMessageRecipientTpl = `Chat %s was registered.
+
+`
 )
 
 // telegramBotStartCommandHandler handles the telegramBotStartCommand command
-// received from a user via a telegram bot. It generates a unique ID for the
-// user and stores it in the database and it sends a welcome message to the
-// user, containing the unique ID.
-func (a *app) telegramBotStartCommandHandler(chatID int64) error {
+// received from a user via a telegram bot. It generates a unique token for the
+// user, stores its hash in the database and it sends a welcome message to the
+// user, containing the token.
+//
+// If an invite code is given as the first argument, the user is created
+// with the invite's options regardless of the registration mode. Otherwise,
+// unless the chat is an admin, the registration mode decides what happens:
+// in approval mode the admins are asked to approve the registration first
+// and in closed mode the user is told how to request access instead.
+//
+//nolint:funlen,cyclop
+func (a *app) telegramBotStartCommandHandler(ctx context.Context, chatID int64,
+	chatTitle string, arguments []string,
+) error {
 	log := glogger.New(glogger.Caller{
 		Service:  os.Getenv(serviceNameEnvVarName),
 		Package:  packageName,
@@ -27,10 +52,7 @@ func (a *app) telegramBotStartCommandHandler(chatID int64) error {
 
 	log.Debug("handling command")
 
-	user := types.User{
-		ID:             generateUserID(),
-		TelegramChatID: chatID,
-	}
+	user := types.User{TelegramChatID: chatID, ChatTitle: chatTitle}
 
 	// define errMsg which is used to send a generic message to
 	// the sender of the command via telegram when an error occurs
@@ -38,13 +60,65 @@ func (a *app) telegramBotStartCommandHandler(chatID int64) error {
 	errMsg := ""
 	defer func() {
 		if errMsg != "" {
-			if err := a.telegramBotSendMessage(user, errMsg); err != nil {
+			if err := a.telegramBotSendMessage(ctx, user, errMsg); err != nil {
 				log.Err(err).Error("error when sending telegram error message")
 			}
 		}
 	}()
 
-	if err := a.createUser(user); err != nil {
+	log.Data("chatID", chatID).Debug("getting the chat role")
+	role, err := a.getTelegramChatRole(ctx, chatID)
+	if err != nil {
+		errMsg = ErrUnableToGetTelegramChatRole.Error()
+		log.Data("chatID", chatID).Err(err).Error(errMsg)
+
+		return err
+	}
+
+	if len(arguments) > 0 {
+		log.Data("chatID", chatID).Debug("redeeming invite")
+		if err := a.redeemInvite(ctx, user, arguments[0], time.Now()); err != nil {
+			errMsg = "error when redeeming invite"
+			if errors.Is(err, ErrInviteNotFound) || errors.Is(err, ErrInviteNotUsable) {
+				errMsg = err.Error()
+			}
+
+			log.Err(err).Error(errMsg)
+
+			return err
+		}
+
+		return nil
+	}
+
+	if !role.Includes(types.RoleAdmin) {
+		switch a.config.TelegramBot.RegistrationMode {
+		case registrationModeClosed:
+			log.Data("chatID", chatID).Debug("registration is closed")
+			msg := fmt.Sprintf(telegramBotRegistrationClosedMessageTpl, chatID)
+			if err := a.telegramBotSendMessage(ctx, user, msg); err != nil {
+				errMsg = "could not send telegram message"
+				log.Err(err).Error(errMsg)
+
+				return err
+			}
+
+			return nil
+		case registrationModeApproval:
+			log.Data("chatID", chatID).Debug("requesting registration approval")
+			if err := a.requestRegistrationApproval(ctx, user); err != nil {
+				errMsg = "error when requesting registration approval"
+				log.Err(err).Error(errMsg)
+
+				return err
+			}
+
+			return nil
+		case registrationModeOpen:
+		}
+	}
+
+	if err := a.createUser(ctx, user); err != nil {
 		errMsg = "error when creating user"
 		log.Err(err).Error(errMsg)
 
@@ -54,38 +128,86 @@ func (a *app) telegramBotStartCommandHandler(chatID int64) error {
 	return nil
 }
 
-func (a *app) createUser(user types.User) error {
+// newUserToken generates a new token for the given user and returns the
+// user with its ID set to the token's hash along with the token.
+func (a *app) newUserToken(user types.User) (types.User, string) {
+	token := generateToken()
+	user.ID = hashToken(a.config.Auth.TokenHashKey, token)
+
+	return user, token
+}
+
+// createUser generates a new token for the given user, replaces all of the
+// existing users matching the user's Telegram chat ID with it and sends
+// the token to the user. The token is only stored as a hash so this
+// is the only time it is shown.
+func (a *app) createUser(ctx context.Context, user types.User) error {
+	return a.createUserAndSendToken(ctx, user, user.TelegramChatID)
+}
+
+// createUserAndSendToken works like createUser but sends the token to the
+// given recipient chat instead. If the recipient isn't the user's chat, the
+// message says which chat the token is for.
+func (a *app) createUserAndSendToken(ctx context.Context, user types.User, recipientChatID int64) error {
 	log := glogger.New(glogger.Caller{
 		Service:  os.Getenv(serviceNameEnvVarName),
 		Package:  packageName,
 		Receiver: "app",
-		Function: "createUser",
+		Function: "createUserAndSendToken",
 	})
 
-	var errMsg string
+	user, token := a.newUserToken(user)
 
-	log.Data("chatID", user.TelegramChatID).Debug("deleting all users matching the telegram chat id")
-	err := a.db.GetUserRepositoryWriter().DeleteAllByTelegramChatID(user.TelegramChatID)
+	log.Data("user", user).Debug("replacing the users of the telegram chat")
+	err := a.db.InTx(ctx, func(tx storage.Repositories) error {
+		return replaceTelegramChatUsers(ctx, tx, user)
+	})
 	if err != nil {
-		errMsg = "error when cleaning up the database by telegram chat ID"
-		log.Err(err).Error(errMsg)
+		log.Err(err).Error("error when creating user")
 
-		return err
+		return err //nolint:wrapcheck
 	}
 
-	log.Data("user", user).Debug("creating user")
-	if err := a.db.GetUserRepositoryWriter().Create(user); err != nil {
-		errMsg = "error when creating user"
-		log.Err(err).Error(errMsg)
+	return a.sendUserToken(ctx, user, token, recipientChatID)
+}
 
-		return err
+// replaceTelegramChatUsers deletes all of the users of the given user's
+// Telegram chat and creates the user in the given transaction so that
+// the chat is never left without a user or with more than one.
+func replaceTelegramChatUsers(ctx context.Context, tx storage.Repositories, user types.User) error {
+	if err := tx.GetUserRepositoryWriter().DeleteAllByTelegramChatID(ctx, user.TelegramChatID); err != nil {
+		return err //nolint:wrapcheck
 	}
 
-	log.Data("user", user).Debug("sending welcome message to user")
-	msg := fmt.Sprintf(telegramBotWelcomeMessageTpl, user.ID)
-	if err := a.telegramBotSendMessage(user, msg); err != nil {
-		errMsg = "could not send telegram welcome message"
-		log.Err(err).Error(errMsg)
+	return tx.GetUserRepositoryWriter().Create(ctx, user) //nolint:wrapcheck
+}
+
+// sendUserToken sends the welcome message with the given token of the
+// given user to the given recipient chat. If the recipient isn't the
+// user's chat, the message says which chat the token is for.
+func (a *app) sendUserToken(ctx context.Context, user types.User, token string, recipientChatID int64) error {
+	log := glogger.New(glogger.Caller{
+		Service:  os.Getenv(serviceNameEnvVarName),
+		Package:  packageName,
+		Receiver: "app",
+		Function: "sendUserToken",
+	})
+
+	log.Data("user", user).Data("recipientChatID", recipientChatID).Debug("sending welcome message")
+	msg := fmt.Sprintf(telegramBotWelcomeMessageTpl, token, user.ID, a.getUserSigningSecret(user))
+	if user.ExpiresAt != nil {
+		msg += fmt.Sprintf(telegramBotWelcomeMessageExpiryTpl, user.ExpiresAt.Format(time.RFC1123))
+	}
+
+	recipient := user
+	if recipientChatID != user.TelegramChatID {
+		recipient = types.User{TelegramChatID: recipientChatID}
+		msg = fmt.Sprintf(telegramBotWelcomeMessageRecipientTpl,
+			formatTelegramChat(user.TelegramChatID, user.ChatTitle)) + msg
+	}
+
+	if err := a.telegramBotSendMessage(ctx, recipient, msg); err != nil {
+		log.Err(err).Error("could not send telegram welcome message")
 
 		return err
 	}
@@ -1 +1,37 @@
 package v1
+
+import (
+	"context"
+	"testing"
+
+	"github.com/psyb0t/telegram-logger/internal/pkg/storage"
+	"github.com/psyb0t/telegram-logger/internal/pkg/storage/memory"
+	"github.com/psyb0t/telegram-logger/internal/pkg/types"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestReplaceTelegramChatUsers(t *testing.T) {
+	ctx := context.Background()
+
+	db, err := memory.New(ctx)
+	require.NoError(t, err)
+	require.NoError(t, db.Open(ctx, ""))
+
+	defer db.Close()
+
+	require.NoError(t, db.GetUserRepositoryWriter().Create(ctx, types.User{ID: "a", TelegramChatID: 1}))
+	require.NoError(t, db.GetUserRepositoryWriter().Create(ctx, types.User{ID: "b", TelegramChatID: 1}))
+	require.NoError(t, db.GetUserRepositoryWriter().Create(ctx, types.User{ID: "c", TelegramChatID: 2}))
+
+	require.NoError(t, db.InTx(ctx, func(tx storage.Repositories) error {
+		return replaceTelegramChatUsers(ctx, tx, types.User{ID: "d", TelegramChatID: 1})
+	}))
+
+	users, err := db.GetUserRepositoryReader().GetAll(ctx)
+	require.NoError(t, err)
+	assert.Equal(t, []types.User{
+		{ID: "c", TelegramChatID: 2},
+		{ID: "d", TelegramChatID: 1},
+	}, users)
+}
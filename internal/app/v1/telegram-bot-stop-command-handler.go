@@ -1,6 +1,7 @@
 package v1
 
 import (
+	"context"
 	"fmt"
 	"os"
 
@@ -17,7 +18,7 @@ Chat ID %d has been removed from the system.`
 // of the Telegram bot. When this function is called, it removes all users
 // with the provided chat ID from the system and sends notification to the user
 // via Telegram.
-func (a *app) telegramBotStopCommandHandler(chatID int64) error {
+func (a *app) telegramBotStopCommandHandler(ctx context.Context, chatID int64) error {
 	log := glogger.New(glogger.Caller{
 		Service:  os.Getenv(serviceNameEnvVarName),
 		Package:  packageName,
@@ -35,14 +36,22 @@ func (a *app) telegramBotStopCommandHandler(chatID int64) error {
 	errMsg := ""
 	defer func() {
 		if errMsg != "" {
-			if err := a.telegramBotSendMessage(user, errMsg); err != nil {
+			if err := a.telegramBotSendMessage(ctx, user, errMsg); err != nil {
 				log.Err(err).Error("error when sending telegram error message")
 			}
 		}
 	}()
 
+	log.Data("chatID", chatID).Debug("checking if the chat is a member")
+	if err := a.telegramBotRequireRole(ctx, chatID, types.RoleMember); err != nil {
+		errMsg = err.Error()
+		log.Data("chatID", chatID).Err(err).Error(errMsg)
+
+		return err
+	}
+
 	log.Data("chatID", chatID).Debug("deleting all users by Telegram chat ID")
-	err := a.db.GetUserRepositoryWriter().DeleteAllByTelegramChatID(chatID)
+	err := a.db.GetUserRepositoryWriter().DeleteAllByTelegramChatID(ctx, chatID)
 	if err != nil {
 		errMsg = "an error occurred when trying to delete users"
 		log.Err(err).Error(errMsg)
@@ -51,7 +60,7 @@ func (a *app) telegramBotStopCommandHandler(chatID int64) error {
 	}
 
 	msg := fmt.Sprintf(telegramBotByeMessageTpl, chatID)
-	if err := a.telegramBotSendMessage(user, msg); err != nil {
+	if err := a.telegramBotSendMessage(ctx, user, msg); err != nil {
 		errMsg = "could not send telegram bye message"
 		log.Err(err).Error(errMsg)
 
@@ -0,0 +1,82 @@
+package v1
+
+import (
+	"context"
+	"errors"
+	"strconv"
+	"time"
+
+	tgbotapi "github.com/go-telegram-bot-api/telegram-bot-api/v5"
+	"github.com/psyb0t/telegram-logger/internal/pkg/storage"
+)
+
+const (
+	settingKeyTelegramBotUpdateOffset = "telegramBotUpdateOffset"
+)
+
+// getTelegramBotUpdateOffset returns the ID of the first update that's not
+// processed yet. It's 0 if no update was ever processed.
+func (a *app) getTelegramBotUpdateOffset(ctx context.Context) (int, error) {
+	value, err := a.db.GetSettingRepositoryReader().Get(ctx, settingKeyTelegramBotUpdateOffset)
+	if err != nil {
+		if errors.Is(err, storage.ErrNotFound) {
+			return 0, nil
+		}
+
+		return 0, err //nolint:wrapcheck
+	}
+
+	return strconv.Atoi(value) //nolint:wrapcheck
+}
+
+// setTelegramBotUpdateOffset stores the ID of the first update
+// that's not processed yet so it can be resumed from after a restart.
+func (a *app) setTelegramBotUpdateOffset(ctx context.Context, offset int) error {
+	return a.db.GetSettingRepositoryWriter().Set(ctx, //nolint:wrapcheck
+		settingKeyTelegramBotUpdateOffset, strconv.Itoa(offset))
+}
+
+// getTelegramBotUpdateTime returns the time the given update was sent at
+// or the zero time if the update doesn't carry one (eg. callback queries).
+func getTelegramBotUpdateTime(update tgbotapi.Update) time.Time {
+	var date int
+
+	switch {
+	case update.Message != nil:
+		date = update.Message.Date
+	case update.EditedMessage != nil:
+		date = update.EditedMessage.Date
+	case update.ChannelPost != nil:
+		date = update.ChannelPost.Date
+	case update.EditedChannelPost != nil:
+		date = update.EditedChannelPost.Date
+	case update.MyChatMember != nil:
+		date = update.MyChatMember.Date
+	case update.ChatMember != nil:
+		date = update.ChatMember.Date
+	}
+
+	if date == 0 {
+		return time.Time{}
+	}
+
+	return time.Unix(int64(date), 0)
+}
+
+// telegramBotUpdateIsStale checks if the given update was sent more than
+// maxAge before now. Updates without a time are never stale and
+// no update is stale if maxAge is 0. Changes of the bot's status in a
+// chat are never stale either since they are not commands and missing
+// them would leave the chat's users in the wrong state.
+func telegramBotUpdateIsStale(update tgbotapi.Update, now time.Time, maxAge time.Duration) bool {
+	if maxAge == 0 || update.MyChatMember != nil {
+		return false
+	}
+
+	updateTime := getTelegramBotUpdateTime(update)
+	if updateTime.IsZero() {
+		return false
+	}
+
+	return now.Sub(updateTime) > maxAge
+}
@@ -0,0 +1,110 @@
+package v1
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	tgbotapi "github.com/go-telegram-bot-api/telegram-bot-api/v5"
+	"github.com/psyb0t/telegram-logger/internal/pkg/storage"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestApp_getTelegramBotUpdateOffset(t *testing.T) {
+	tests := []struct {
+		name           string
+		value          string
+		err            error
+		expectedOffset int
+		expectedErr    error
+	}{
+		{
+			name:           "stored offset",
+			value:          "12345",
+			expectedOffset: 12345,
+		},
+		{
+			name:           "no stored offset",
+			err:            storage.ErrNotFound,
+			expectedOffset: 0,
+		},
+		{
+			name:        "storage error",
+			err:         errTestStorage,
+			expectedErr: errTestStorage,
+		},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			db := storage.NewMock()
+			settingRepositoryReader, _ := db.GetSettingRepositoryReader().(*storage.SettingRepositoryReaderMock)
+			settingRepositoryReader.On("Get", settingKeyTelegramBotUpdateOffset).Return(test.value, test.err)
+
+			a := &app{db: db}
+
+			offset, err := a.getTelegramBotUpdateOffset(context.Background())
+			assert.ErrorIs(t, err, test.expectedErr)
+			assert.Equal(t, test.expectedOffset, offset)
+		})
+	}
+}
+
+func TestTelegramBotUpdateIsStale(t *testing.T) {
+	now := time.Now()
+	messageAt := func(t time.Time) *tgbotapi.Message {
+		return &tgbotapi.Message{Date: int(t.Unix())}
+	}
+
+	tests := []struct {
+		name     string
+		update   tgbotapi.Update
+		maxAge   time.Duration
+		expected bool
+	}{
+		{
+			name:     "recent message",
+			update:   tgbotapi.Update{Message: messageAt(now.Add(-time.Minute))},
+			maxAge:   10 * time.Minute,
+			expected: false,
+		},
+		{
+			name:     "old message",
+			update:   tgbotapi.Update{Message: messageAt(now.Add(-time.Hour))},
+			maxAge:   10 * time.Minute,
+			expected: true,
+		},
+		{
+			name:     "old channel post",
+			update:   tgbotapi.Update{ChannelPost: messageAt(now.Add(-time.Hour))},
+			maxAge:   10 * time.Minute,
+			expected: true,
+		},
+		{
+			name:     "old message without max age",
+			update:   tgbotapi.Update{Message: messageAt(now.Add(-time.Hour))},
+			maxAge:   0,
+			expected: false,
+		},
+		{
+			name: "old bot status change",
+			update: tgbotapi.Update{MyChatMember: &tgbotapi.ChatMemberUpdated{
+				Date: int(now.Add(-time.Hour).Unix()),
+			}},
+			maxAge:   10 * time.Minute,
+			expected: false,
+		},
+		{
+			name:     "callback query",
+			update:   tgbotapi.Update{CallbackQuery: &tgbotapi.CallbackQuery{}},
+			maxAge:   10 * time.Minute,
+			expected: false,
+		},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			assert.Equal(t, test.expected, telegramBotUpdateIsStale(test.update, now, test.maxAge))
+		})
+	}
+}
@@ -0,0 +1,104 @@
+package v1
+
+import (
+	"crypto/subtle"
+	"encoding/json"
+	"net/url"
+	"os"
+
+	tgbotapi "github.com/go-telegram-bot-api/telegram-bot-api/v5"
+	"github.com/psyb0t/glogger"
+	"github.com/valyala/fasthttp"
+)
+
+const (
+	headerNameTelegramBotAPISecretToken = "X-Telegram-Bot-Api-Secret-Token" //nolint:gosec
+
+	telegramBotWebhookUpdatesBufferSize = 100
+)
+
+// getTelegramBotWebhookPath returns the path of the given webhook URL which
+// is the path the webhook HTTP handler is served on. The root path
+// is already taken by the log ingestion handler.
+func getTelegramBotWebhookPath(webhookURL string) (string, error) {
+	u, err := url.Parse(webhookURL)
+	if err != nil {
+		return "", err //nolint:wrapcheck
+	}
+
+	if u.Path == "" || u.Path == "/" {
+		return "", ErrInvalidTelegramBotWebhookURL
+	}
+
+	return u.Path, nil
+}
+
+// setTelegramBotWebhook registers the configured webhook URL with Telegram
+// along with the secret token Telegram sends in every webhook request.
+func (a *app) setTelegramBotWebhook() error {
+	params := tgbotapi.Params{}
+	params.AddNonEmpty("url", a.config.TelegramBot.Webhook.URL)
+	params.AddNonEmpty("secret_token", a.config.TelegramBot.Webhook.SecretToken)
+
+	_, err := a.telegramBotAPI.MakeRequest("setWebhook", params)
+
+	return err //nolint:wrapcheck
+}
+
+// deleteTelegramBotWebhook removes the webhook from Telegram so that
+// it stops sending updates to an instance that's shutting down.
+func (a *app) deleteTelegramBotWebhook() {
+	log := glogger.New(glogger.Caller{
+		Service:  os.Getenv(serviceNameEnvVarName),
+		Package:  packageName,
+		Receiver: "app",
+		Function: "deleteTelegramBotWebhook",
+	})
+
+	log.Info("deleting the telegram bot webhook")
+	if _, err := a.telegramBotAPI.Request(tgbotapi.DeleteWebhookConfig{}); err != nil {
+		log.Err(err).Error("error when deleting the telegram bot webhook")
+	}
+}
+
+// telegramBotWebhookHTTPHandler receives the updates Telegram sends to the
+// webhook and passes them on to the telegram bot message handler. Requests
+// without the configured secret token are rejected.
+func (a *app) telegramBotWebhookHTTPHandler(ctx *fasthttp.RequestCtx) {
+	log := glogger.New(glogger.Caller{
+		Service:  os.Getenv(serviceNameEnvVarName),
+		Package:  packageName,
+		Receiver: "app",
+		Function: "telegramBotWebhookHTTPHandler",
+	})
+
+	secretToken := ctx.Request.Header.Peek(headerNameTelegramBotAPISecretToken)
+	if subtle.ConstantTimeCompare(secretToken, []byte(a.config.TelegramBot.Webhook.SecretToken)) != 1 {
+		log.Debug("invalid telegram bot webhook secret token")
+
+		a.returnHTTPResponseString(ctx, fasthttp.StatusUnauthorized,
+			fasthttp.StatusMessage(fasthttp.StatusUnauthorized))
+
+		return
+	}
+
+	update := tgbotapi.Update{}
+	if err := json.Unmarshal(ctx.Request.Body(), &update); err != nil {
+		log.Err(err).Error("could not parse the telegram bot webhook update")
+
+		a.returnHTTPResponseString(ctx, fasthttp.StatusBadRequest,
+			fasthttp.StatusMessage(fasthttp.StatusBadRequest))
+
+		return
+	}
+
+	select {
+	case <-a.ctx.Done():
+		// let Telegram retry the update later
+		a.returnHTTPResponseString(ctx, fasthttp.StatusServiceUnavailable,
+			fasthttp.StatusMessage(fasthttp.StatusServiceUnavailable))
+	case a.telegramBotWebhookUpdates <- update:
+		a.returnHTTPResponseString(ctx, fasthttp.StatusOK,
+			fasthttp.StatusMessage(fasthttp.StatusOK))
+	}
+}
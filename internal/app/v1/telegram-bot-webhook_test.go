@@ -0,0 +1,124 @@
+package v1
+
+import (
+	"context"
+	"testing"
+
+	tgbotapi "github.com/go-telegram-bot-api/telegram-bot-api/v5"
+	"github.com/stretchr/testify/assert"
+	"github.com/valyala/fasthttp"
+)
+
+func TestGetTelegramBotWebhookPath(t *testing.T) {
+	tests := []struct {
+		name         string
+		webhookURL   string
+		expectedPath string
+		expectedErr  error
+	}{
+		{
+			name:         "with path",
+			webhookURL:   "https://example.com/telegram/webhook",
+			expectedPath: "/telegram/webhook",
+		},
+		{
+			name:        "without path",
+			webhookURL:  "https://example.com",
+			expectedErr: ErrInvalidTelegramBotWebhookURL,
+		},
+		{
+			name:        "root path",
+			webhookURL:  "https://example.com/",
+			expectedErr: ErrInvalidTelegramBotWebhookURL,
+		},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			path, err := getTelegramBotWebhookPath(test.webhookURL)
+			assert.ErrorIs(t, err, test.expectedErr)
+			assert.Equal(t, test.expectedPath, path)
+		})
+	}
+}
+
+func TestApp_telegramBotWebhookHTTPHandler(t *testing.T) {
+	newTestApp := func() *app {
+		ctx, cancelFunc := context.WithCancel(context.Background())
+
+		return &app{
+			ctx:        ctx,
+			cancelFunc: cancelFunc,
+			config: config{
+				TelegramBot: telegramBotConfig{
+					Webhook: telegramBotWebhookConfig{
+						URL:         "https://example.com/telegram/webhook",
+						SecretToken: "secret",
+					},
+				},
+			},
+			telegramBotWebhookPath:    "/telegram/webhook",
+			telegramBotWebhookUpdates: make(chan tgbotapi.Update, 1),
+		}
+	}
+
+	doRequest := func(a *app, secretToken, body string) *fasthttp.RequestCtx {
+		ctx := &fasthttp.RequestCtx{}
+		ctx.Request.Header.SetMethod(fasthttp.MethodPost)
+		ctx.Request.SetRequestURI("/telegram/webhook")
+		ctx.Request.SetBodyString(body)
+
+		if secretToken != "" {
+			ctx.Request.Header.Set(headerNameTelegramBotAPISecretToken, secretToken)
+		}
+
+		a.getHTTPRequestHandler()(ctx)
+
+		return ctx
+	}
+
+	t.Run("no secret token", func(t *testing.T) {
+		a := newTestApp()
+
+		ctx := doRequest(a, "", `{"update_id":1}`)
+		assert.Equal(t, fasthttp.StatusUnauthorized, ctx.Response.StatusCode())
+		assert.Len(t, a.telegramBotWebhookUpdates, 0)
+	})
+
+	t.Run("wrong secret token", func(t *testing.T) {
+		a := newTestApp()
+
+		ctx := doRequest(a, "wrong", `{"update_id":1}`)
+		assert.Equal(t, fasthttp.StatusUnauthorized, ctx.Response.StatusCode())
+		assert.Len(t, a.telegramBotWebhookUpdates, 0)
+	})
+
+	t.Run("invalid update", func(t *testing.T) {
+		a := newTestApp()
+
+		ctx := doRequest(a, "secret", `{`)
+		assert.Equal(t, fasthttp.StatusBadRequest, ctx.Response.StatusCode())
+		assert.Len(t, a.telegramBotWebhookUpdates, 0)
+	})
+
+	t.Run("valid update", func(t *testing.T) {
+		a := newTestApp()
+
+		ctx := doRequest(a, "secret", `{"update_id":1,"message":{"text":"/start","chat":{"id":2}}}`)
+		assert.Equal(t, fasthttp.StatusOK, ctx.Response.StatusCode())
+
+		update := <-a.telegramBotWebhookUpdates
+		assert.Equal(t, 1, update.UpdateID)
+		assert.Equal(t, "/start", update.Message.Text)
+		assert.Equal(t, int64(2), update.Message.Chat.ID)
+	})
+
+	t.Run("shutting down", func(t *testing.T) {
+		a := newTestApp()
+		a.telegramBotWebhookUpdates = make(chan tgbotapi.Update)
+		a.cancelFunc()
+
+		ctx := doRequest(a, "secret", `{"update_id":1}`)
+		assert.Equal(t, fasthttp.StatusServiceUnavailable, ctx.Response.StatusCode())
+	})
+}
@@ -0,0 +1,79 @@
+package v1
+
+import (
+	"context"
+	"errors"
+	"os"
+
+	tgbotapi "github.com/go-telegram-bot-api/telegram-bot-api/v5"
+	"github.com/psyb0t/glogger"
+	"github.com/psyb0t/telegram-logger/internal/pkg/storage"
+	"github.com/psyb0t/telegram-logger/internal/pkg/types"
+)
+
+// getTelegramBotMigrateToChatID returns the new chat ID of a group that was
+// upgraded to a supergroup if the given error was returned by Telegram
+// because of that. It returns 0 for any other error.
+func getTelegramBotMigrateToChatID(err error) int64 {
+	var telegramBotAPIErr *tgbotapi.Error
+	if !errors.As(err, &telegramBotAPIErr) {
+		return 0
+	}
+
+	return telegramBotAPIErr.MigrateToChatID
+}
+
+// migrateTelegramChat moves the users and the role of a group that was
+// upgraded to a supergroup from the group's old chat ID to the new one.
+// Everything is moved in a single transaction. Migrating a chat
+// that was already migrated does nothing.
+func (a *app) migrateTelegramChat(ctx context.Context, oldChatID, newChatID int64) error {
+	log := glogger.New(glogger.Caller{
+		Service:  os.Getenv(serviceNameEnvVarName),
+		Package:  packageName,
+		Receiver: "app",
+		Function: "migrateTelegramChat",
+	})
+
+	log.Data("data", map[string]interface{}{
+		"oldChatID": oldChatID,
+		"newChatID": newChatID,
+	}).Info("migrating telegram chat")
+
+	err := a.db.InTx(ctx, func(tx storage.Repositories) error {
+		return migrateTelegramChatData(ctx, tx, oldChatID, newChatID)
+	})
+	if err != nil {
+		log.Err(err).Error("error when migrating the telegram chat")
+
+		return err //nolint:wrapcheck
+	}
+
+	return nil
+}
+
+// migrateTelegramChatData moves the users and the role of the old
+// chat ID to the new one in the given transaction.
+func migrateTelegramChatData(ctx context.Context, tx storage.Repositories, oldChatID, newChatID int64) error {
+	if err := tx.GetUserRepositoryWriter().UpdateTelegramChatID(ctx, oldChatID, newChatID); err != nil {
+		return err //nolint:wrapcheck
+	}
+
+	chatRole, err := tx.GetRoleRepositoryReader().Get(ctx, oldChatID)
+	if err != nil {
+		if errors.Is(err, storage.ErrNotFound) {
+			return nil
+		}
+
+		return err //nolint:wrapcheck
+	}
+
+	if err := tx.GetRoleRepositoryWriter().Set(ctx, types.ChatRole{
+		TelegramChatID: newChatID,
+		Role:           chatRole.Role,
+	}); err != nil {
+		return err //nolint:wrapcheck
+	}
+
+	return tx.GetRoleRepositoryWriter().Delete(ctx, oldChatID) //nolint:wrapcheck
+}
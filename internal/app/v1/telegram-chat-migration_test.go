@@ -0,0 +1,107 @@
+package v1
+
+import (
+	"context"
+	"fmt"
+	"testing"
+
+	tgbotapi "github.com/go-telegram-bot-api/telegram-bot-api/v5"
+	"github.com/psyb0t/telegram-logger/internal/pkg/storage"
+	"github.com/psyb0t/telegram-logger/internal/pkg/types"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestGetTelegramBotMigrateToChatID(t *testing.T) {
+	tests := []struct {
+		name     string
+		err      error
+		expected int64
+	}{
+		{
+			name:     "no error",
+			err:      nil,
+			expected: 0,
+		},
+		{
+			name:     "other error",
+			err:      errTestStorage,
+			expected: 0,
+		},
+		{
+			name:     "other telegram error",
+			err:      &tgbotapi.Error{Code: 400, Message: "Bad Request: chat not found"},
+			expected: 0,
+		},
+		{
+			name: "group chat was upgraded",
+			err: &tgbotapi.Error{
+				Code:               400,
+				Message:            "Bad Request: group chat was upgraded to a supergroup chat",
+				ResponseParameters: tgbotapi.ResponseParameters{MigrateToChatID: -1001234},
+			},
+			expected: -1001234,
+		},
+		{
+			name: "wrapped group chat was upgraded",
+			err: fmt.Errorf("send: %w", &tgbotapi.Error{
+				ResponseParameters: tgbotapi.ResponseParameters{MigrateToChatID: -1001234},
+			}),
+			expected: -1001234,
+		},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			assert.Equal(t, test.expected, getTelegramBotMigrateToChatID(test.err))
+		})
+	}
+}
+
+func TestApp_migrateTelegramChat(t *testing.T) {
+	ctx := context.Background()
+
+	t.Run("chat with role", func(t *testing.T) {
+		db := storage.NewMock()
+		userRepositoryWriter, _ := db.GetUserRepositoryWriter().(*storage.UserRepositoryWriterMock)
+		roleRepositoryReader, _ := db.GetRoleRepositoryReader().(*storage.RoleRepositoryReaderMock)
+		roleRepositoryWriter, _ := db.GetRoleRepositoryWriter().(*storage.RoleRepositoryWriterMock)
+
+		userRepositoryWriter.On("UpdateTelegramChatID", int64(-1), int64(-1001)).Return(nil)
+		roleRepositoryReader.On("Get", int64(-1)).Return(types.ChatRole{TelegramChatID: -1, Role: types.RoleAdmin}, nil)
+		roleRepositoryWriter.On("Set", types.ChatRole{TelegramChatID: -1001, Role: types.RoleAdmin}).Return(nil)
+		roleRepositoryWriter.On("Delete", int64(-1)).Return(nil)
+
+		a := &app{db: db}
+
+		assert.NoError(t, a.migrateTelegramChat(ctx, -1, -1001))
+		userRepositoryWriter.AssertExpectations(t)
+		roleRepositoryWriter.AssertExpectations(t)
+	})
+
+	t.Run("chat without role", func(t *testing.T) {
+		db := storage.NewMock()
+		userRepositoryWriter, _ := db.GetUserRepositoryWriter().(*storage.UserRepositoryWriterMock)
+		roleRepositoryReader, _ := db.GetRoleRepositoryReader().(*storage.RoleRepositoryReaderMock)
+		roleRepositoryWriter, _ := db.GetRoleRepositoryWriter().(*storage.RoleRepositoryWriterMock)
+
+		userRepositoryWriter.On("UpdateTelegramChatID", int64(-1), int64(-1001)).Return(nil)
+		roleRepositoryReader.On("Get", int64(-1)).Return(types.ChatRole{}, storage.ErrNotFound)
+
+		a := &app{db: db}
+
+		assert.NoError(t, a.migrateTelegramChat(ctx, -1, -1001))
+		userRepositoryWriter.AssertExpectations(t)
+		roleRepositoryWriter.AssertNotCalled(t, "Set")
+	})
+
+	t.Run("storage error", func(t *testing.T) {
+		db := storage.NewMock()
+		userRepositoryWriter, _ := db.GetUserRepositoryWriter().(*storage.UserRepositoryWriterMock)
+
+		userRepositoryWriter.On("UpdateTelegramChatID", int64(-1), int64(-1001)).Return(errTestStorage)
+
+		a := &app{db: db}
+
+		assert.ErrorIs(t, a.migrateTelegramChat(ctx, -1, -1001), errTestStorage)
+	})
+}
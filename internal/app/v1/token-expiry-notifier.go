@@ -0,0 +1,103 @@
+package v1
+
+import (
+	"context"
+	"fmt"
+	"os"
+	"sync"
+	"time"
+
+	"github.com/psyb0t/glogger"
+	"github.com/psyb0t/telegram-logger/internal/pkg/types"
+)
+
+const (
+	tokenExpiryCheckInterval = time.Hour
+
+	telegramBotTokenExpiryWarningMessageTpl = `Heads up!
+An ID used by this chat expires at %s.
+Ask an admin for a new one before it stops working.`
+)
+
+// startTokenExpiryNotifier periodically warns the users whose tokens are
+// about to expire until the app context is done.
+func (a *app) startTokenExpiryNotifier(wg *sync.WaitGroup) {
+	defer wg.Done()
+
+	log := glogger.New(glogger.Caller{
+		Service:  os.Getenv(serviceNameEnvVarName),
+		Package:  packageName,
+		Receiver: "app",
+		Function: "startTokenExpiryNotifier",
+	})
+
+	log.Info("starting the token expiry notifier")
+	defer log.Info("token expiry notifier stopped")
+
+	ticker := time.NewTicker(tokenExpiryCheckInterval)
+	defer ticker.Stop()
+
+	for {
+		if err := a.notifyExpiringTokens(a.ctx, time.Now()); err != nil {
+			log.Err(err).Error("an error occurred when notifying expiring tokens")
+		}
+
+		select {
+		case <-a.ctx.Done():
+			return
+		case <-ticker.C:
+		}
+	}
+}
+
+// notifyExpiringTokens sends a warning to the chat of every user whose token
+// expires within the configured warning period from now and marks
+// the user as warned so that the warning is only sent once.
+func (a *app) notifyExpiringTokens(ctx context.Context, now time.Time) error {
+	log := glogger.New(glogger.Caller{
+		Service:  os.Getenv(serviceNameEnvVarName),
+		Package:  packageName,
+		Receiver: "app",
+		Function: "notifyExpiringTokens",
+	})
+
+	err := a.db.GetUserRepositoryReader().Iterate(ctx, func(user types.User) error {
+		if !tokenExpiryWarningDue(user, now, a.config.Auth.TokenExpiryWarning) {
+			return nil
+		}
+
+		log.Data("id", user.ID).Debug("warning user about token expiry")
+		msg := fmt.Sprintf(telegramBotTokenExpiryWarningMessageTpl, user.ExpiresAt.Format(time.RFC1123))
+		if err := a.telegramBotSendMessage(ctx, user, msg); err != nil {
+			log.Err(err).Error("could not send telegram token expiry warning message")
+
+			return nil
+		}
+
+		user.ExpiryWarningSent = true
+		if err := a.db.GetUserRepositoryWriter().Update(ctx, user); err != nil {
+			log.Err(err).Error("error when marking the user as warned")
+
+			return err
+		}
+
+		return nil
+	})
+	if err != nil {
+		log.Err(err).Error("an error occurred when trying to go through the users")
+
+		return err
+	}
+
+	return nil
+}
+
+// tokenExpiryWarningDue checks if the given user should be warned at the
+// given time that the token expires within the given warning period.
+func tokenExpiryWarningDue(user types.User, now time.Time, warningPeriod time.Duration) bool {
+	if !user.IsActive() || user.ExpiresAt == nil || user.ExpiryWarningSent || user.IsExpired(now) {
+		return false
+	}
+
+	return !now.Add(warningPeriod).Before(*user.ExpiresAt)
+}
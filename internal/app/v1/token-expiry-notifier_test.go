@@ -0,0 +1,59 @@
+package v1
+
+import (
+	"testing"
+	"time"
+
+	"github.com/psyb0t/telegram-logger/internal/pkg/types"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestTokenExpiryWarningDue(t *testing.T) {
+	now := time.Date(2023, 5, 1, 12, 0, 0, 0, time.UTC)
+	inOneDay := now.Add(24 * time.Hour)
+	inTenDays := now.Add(10 * 24 * time.Hour)
+	oneDayAgo := now.Add(-24 * time.Hour)
+
+	tests := []struct {
+		name     string
+		user     types.User
+		expected bool
+	}{
+		{
+			name:     "never expires",
+			user:     types.User{},
+			expected: false,
+		},
+		{
+			name:     "expires within the warning period",
+			user:     types.User{ExpiresAt: &inOneDay},
+			expected: true,
+		},
+		{
+			name:     "already warned",
+			user:     types.User{ExpiresAt: &inOneDay, ExpiryWarningSent: true},
+			expected: false,
+		},
+		{
+			name:     "expires after the warning period",
+			user:     types.User{ExpiresAt: &inTenDays},
+			expected: false,
+		},
+		{
+			name:     "pending",
+			user:     types.User{ExpiresAt: &inOneDay, Status: types.UserStatusPending},
+			expected: false,
+		},
+		{
+			name:     "already expired",
+			user:     types.User{ExpiresAt: &oneDayAgo},
+			expected: false,
+		},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			assert.Equal(t, test.expected, tokenExpiryWarningDue(test.user, now, 72*time.Hour))
+		})
+	}
+}
@@ -0,0 +1,118 @@
+package v1
+
+import (
+	"fmt"
+	"net"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/psyb0t/telegram-logger/internal/pkg/types"
+)
+
+const (
+	userOptionTTL         = "ttl"
+	userOptionPermissions = "permissions"
+	userOptionLevels      = "levels"
+	userOptionEndpoints   = "endpoints"
+	userOptionCIDRs       = "cidrs"
+)
+
+// applyUserOptions parses the given key=value options and applies them
+// to the given user. The supported options are:
+//
+//	ttl=30d                       the token expires after the given duration
+//	permissions=ingest            comma separated list of granted permissions
+//	levels=error,fatal            comma separated list of allowed log levels
+//	endpoints=/                   comma separated list of allowed ingestion endpoints
+//	cidrs=10.0.0.0/8              comma separated list of IP ranges the token can be used from
+//
+// now is the time the ttl is relative to.
+//
+//nolint:cyclop,gocognit,funlen
+func applyUserOptions(user types.User, options []string, now time.Time) (types.User, error) {
+	for _, option := range options {
+		key, value, found := strings.Cut(option, "=")
+		if !found || value == "" {
+			return user, fmt.Errorf("%w: %s", ErrInvalidUserOption, option)
+		}
+
+		switch key {
+		case userOptionTTL:
+			ttl, err := parseDuration(value)
+			if err != nil || ttl <= 0 {
+				return user, fmt.Errorf("%w: %s", ErrInvalidUserOption, option)
+			}
+
+			expiresAt := now.Add(ttl)
+			user.ExpiresAt = &expiresAt
+		case userOptionPermissions:
+			user.Scopes.Permissions = []types.Permission{}
+			for _, p := range strings.Split(value, ",") {
+				permission := types.Permission(p)
+				if !isKnownPermission(permission) {
+					return user, fmt.Errorf("%w: unknown permission %s", ErrInvalidUserOption, p)
+				}
+
+				user.Scopes.Permissions = append(user.Scopes.Permissions, permission)
+			}
+		case userOptionLevels:
+			user.Scopes.Levels = []string{}
+			for _, l := range strings.Split(value, ",") {
+				level := getLogLevelName(l)
+				if level == "" {
+					return user, fmt.Errorf("%w: unknown level %s", ErrInvalidUserOption, l)
+				}
+
+				user.Scopes.Levels = append(user.Scopes.Levels, level)
+			}
+		case userOptionEndpoints:
+			user.Scopes.Endpoints = []string{}
+			for _, endpoint := range strings.Split(value, ",") {
+				if !strings.HasPrefix(endpoint, "/") {
+					return user, fmt.Errorf("%w: invalid endpoint %s", ErrInvalidUserOption, endpoint)
+				}
+
+				user.Scopes.Endpoints = append(user.Scopes.Endpoints, endpoint)
+			}
+		case userOptionCIDRs:
+			user.AllowedCIDRs = []string{}
+			for _, cidr := range strings.Split(value, ",") {
+				if _, _, err := net.ParseCIDR(cidr); err != nil {
+					return user, fmt.Errorf("%w: invalid CIDR %s", ErrInvalidUserOption, cidr)
+				}
+
+				user.AllowedCIDRs = append(user.AllowedCIDRs, cidr)
+			}
+		default:
+			return user, fmt.Errorf("%w: %s", ErrInvalidUserOption, option)
+		}
+	}
+
+	return user, nil
+}
+
+// parseDuration works like time.ParseDuration but it also
+// accepts a number of days such as "30d".
+func parseDuration(s string) (time.Duration, error) {
+	if strings.HasSuffix(s, "d") {
+		n, err := strconv.Atoi(strings.TrimSuffix(s, "d"))
+		if err != nil {
+			return 0, err //nolint:wrapcheck
+		}
+
+		return time.Duration(n) * 24 * time.Hour, nil
+	}
+
+	return time.ParseDuration(s) //nolint:wrapcheck
+}
+
+func isKnownPermission(permission types.Permission) bool {
+	for _, p := range types.Permissions {
+		if p == permission {
+			return true
+		}
+	}
+
+	return false
+}
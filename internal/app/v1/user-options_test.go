@@ -0,0 +1,122 @@
+package v1
+
+import (
+	"testing"
+	"time"
+
+	"github.com/psyb0t/telegram-logger/internal/pkg/types"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestApplyUserOptions(t *testing.T) {
+	now := time.Date(2023, 5, 1, 12, 0, 0, 0, time.UTC)
+	inThirtyDays := now.Add(30 * 24 * time.Hour)
+	inTwoHours := now.Add(2 * time.Hour)
+
+	tests := []struct {
+		name        string
+		options     []string
+		expectError bool
+		expected    types.User
+	}{
+		{
+			name:     "no options",
+			options:  nil,
+			expected: types.User{TelegramChatID: 1},
+		},
+		{
+			name:     "ttl in days",
+			options:  []string{"ttl=30d"},
+			expected: types.User{TelegramChatID: 1, ExpiresAt: &inThirtyDays},
+		},
+		{
+			name:     "ttl as duration",
+			options:  []string{"ttl=2h"},
+			expected: types.User{TelegramChatID: 1, ExpiresAt: &inTwoHours},
+		},
+		{
+			name:    "scopes",
+			options: []string{"permissions=ingest", "levels=ERR,warning", "endpoints=/"},
+			expected: types.User{
+				TelegramChatID: 1,
+				Scopes: types.Scopes{
+					Permissions: []types.Permission{types.PermissionIngest},
+					Levels:      []string{"error", "warn"},
+					Endpoints:   []string{"/"},
+				},
+			},
+		},
+		{
+			name:    "history read permission",
+			options: []string{"permissions=ingest,history:read"},
+			expected: types.User{
+				TelegramChatID: 1,
+				Scopes: types.Scopes{
+					Permissions: []types.Permission{types.PermissionIngest, types.PermissionHistoryRead},
+				},
+			},
+		},
+		{
+			name:    "cidrs",
+			options: []string{"cidrs=10.0.0.0/8,2001:db8::/32"},
+			expected: types.User{
+				TelegramChatID: 1,
+				AllowedCIDRs:   []string{"10.0.0.0/8", "2001:db8::/32"},
+			},
+		},
+		{
+			name:        "bad cidr",
+			options:     []string{"cidrs=10.0.0.1"},
+			expectError: true,
+		},
+		{
+			name:        "negative ttl",
+			options:     []string{"ttl=-1h"},
+			expectError: true,
+		},
+		{
+			name:        "bad ttl",
+			options:     []string{"ttl=abc"},
+			expectError: true,
+		},
+		{
+			name:        "unknown permission",
+			options:     []string{"permissions=admin"},
+			expectError: true,
+		},
+		{
+			name:        "unknown level",
+			options:     []string{"levels=loud"},
+			expectError: true,
+		},
+		{
+			name:        "bad endpoint",
+			options:     []string{"endpoints=logs"},
+			expectError: true,
+		},
+		{
+			name:        "unknown option",
+			options:     []string{"color=blue"},
+			expectError: true,
+		},
+		{
+			name:        "missing value",
+			options:     []string{"ttl"},
+			expectError: true,
+		},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			actual, err := applyUserOptions(types.User{TelegramChatID: 1}, test.options, now)
+			if test.expectError {
+				assert.ErrorIs(t, err, ErrInvalidUserOption)
+
+				return
+			}
+
+			assert.NoError(t, err)
+			assert.Equal(t, test.expected, actual)
+		})
+	}
+}
@@ -0,0 +1,66 @@
+package v1
+
+import (
+	"context"
+	"os"
+
+	"github.com/psyb0t/glogger"
+	"github.com/psyb0t/telegram-logger/internal/pkg/storage"
+)
+
+// migrateUserTokens migrates users stored by databases created before tokens
+// were hashed. Such users have the plain text token as their ID so they get
+// recreated with the hash of their ID as the new ID and the old entry is
+// removed. Clients keep on using the same token.
+func (a *app) migrateUserTokens(ctx context.Context) error {
+	log := glogger.New(glogger.Caller{
+		Service:  os.Getenv(serviceNameEnvVarName),
+		Package:  packageName,
+		Receiver: "app",
+		Function: "migrateUserTokens",
+	})
+
+	log.Debug("getting all users")
+	users, err := a.db.GetUserRepositoryReader().GetAll(ctx)
+	if err != nil {
+		log.Err(err).Error("an error occurred when trying to get all users")
+
+		return err
+	}
+
+	migrated := 0
+	for _, user := range users {
+		if isTokenHash(user.ID) {
+			continue
+		}
+
+		plainTextID := user.ID
+		user.ID = hashToken(a.config.Auth.TokenHashKey, plainTextID)
+
+		log.Data("chatID", user.TelegramChatID).Debug("migrating user token")
+		err := a.db.InTx(ctx, func(tx storage.Repositories) error {
+			if err := tx.GetUserRepositoryWriter().Create(ctx, user); err != nil {
+				log.Err(err).Error("error when creating the migrated user")
+
+				return err
+			}
+
+			if err := tx.GetUserRepositoryWriter().Delete(ctx, plainTextID); err != nil {
+				log.Err(err).Error("error when deleting the plain text user")
+
+				return err
+			}
+
+			return nil
+		})
+		if err != nil {
+			return err
+		}
+
+		migrated++
+	}
+
+	log.Data("migrated", migrated).Info("user token migration complete")
+
+	return nil
+}
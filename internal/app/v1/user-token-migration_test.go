@@ -0,0 +1,55 @@
+package v1
+
+import (
+	"context"
+	"testing"
+
+	"github.com/psyb0t/telegram-logger/internal/pkg/storage"
+	"github.com/psyb0t/telegram-logger/internal/pkg/storage/memory"
+	"github.com/psyb0t/telegram-logger/internal/pkg/types"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestApp_migrateUserTokens(t *testing.T) {
+	ctx := context.Background()
+
+	db, err := memory.New(ctx)
+	require.NoError(t, err)
+
+	a := &app{
+		config: config{Auth: authConfig{TokenHashKey: "key"}},
+		db:     db,
+	}
+
+	hashedUser := types.User{ID: hashToken("key", "hashed-token"), TelegramChatID: 2}
+
+	require.NoError(t, db.GetUserRepositoryWriter().Create(ctx, types.User{ID: "plain-token", TelegramChatID: 1}))
+	require.NoError(t, db.GetUserRepositoryWriter().Create(ctx, hashedUser))
+
+	require.NoError(t, a.migrateUserTokens(ctx))
+
+	// the plain text user is re-keyed to the hash of its token
+	_, err = db.GetUserRepositoryReader().Get(ctx, "plain-token")
+	assert.ErrorIs(t, err, storage.ErrNotFound)
+
+	migratedUser, err := db.GetUserRepositoryReader().Get(ctx, hashToken("key", "plain-token"))
+	require.NoError(t, err)
+	assert.Equal(t, int64(1), migratedUser.TelegramChatID)
+
+	// the already hashed user is left as it is
+	user, err := db.GetUserRepositoryReader().Get(ctx, hashedUser.ID)
+	require.NoError(t, err)
+	assert.Equal(t, hashedUser.TelegramChatID, user.TelegramChatID)
+
+	users, err := db.GetUserRepositoryReader().GetAll(ctx)
+	require.NoError(t, err)
+	assert.Len(t, users, 2)
+
+	// migrating again does nothing
+	require.NoError(t, a.migrateUserTokens(ctx))
+
+	users, err = db.GetUserRepositoryReader().GetAll(ctx)
+	require.NoError(t, err)
+	assert.Len(t, users, 2)
+}
@@ -1,16 +1,55 @@
 package v1
 
 import (
+	"crypto/hmac"
+	"crypto/sha256"
+	"encoding/hex"
 	"strings"
 
 	"github.com/google/uuid"
 )
 
-// generateUserID creates a unique user ID.
-func generateUserID() string {
+// generateToken creates a unique user token.
+func generateToken() string {
 	return uuid.New().String()
 }
 
+// hashToken returns the hex encoded HMAC-SHA256 of the given token keyed
+// with the given key. The result is used as the user ID in the database so
+// that tokens are never stored in plain text.
+func hashToken(key, token string) string {
+	mac := hmac.New(sha256.New, []byte(key))
+	mac.Write([]byte(token))
+
+	return hex.EncodeToString(mac.Sum(nil))
+}
+
+// getShortestKeyBetween returns the shortest prefix of hi that's greater
+// than lo, given that lo < hi. A listing starting from it starts with hi
+// and one ending right before it ends with lo so it can stand for hi in
+// a cursor, which keeps the inline keyboard button data short.
+func getShortestKeyBetween(lo, hi string) string {
+	for i := 1; i < len(hi); i++ {
+		if hi[:i] > lo {
+			return hi[:i]
+		}
+	}
+
+	return hi
+}
+
+// isTokenHash checks if the given string looks like a value
+// returned by hashToken.
+func isTokenHash(s string) bool {
+	if len(s) != sha256.Size*2 {
+		return false
+	}
+
+	_, err := hex.DecodeString(s)
+
+	return err == nil
+}
+
 var (
 	logLevelStringsDebug = []string{"debug", "dbg", "debugging"}
 	logLevelStringsInfo  = []string{"info", "inf", "information"}
@@ -28,6 +67,12 @@ var logLevelEmoji = map[string]string{
 }
 
 func getLogLevelEmoji(level string) string {
+	return logLevelEmoji[getLogLevelName(level)]
+}
+
+// getLogLevelName returns the canonical name of the given log level
+// (eg. "warn" for "WARNING") or an empty string if the level is unknown.
+func getLogLevelName(level string) string {
 	level = strings.ToLower(level)
 
 	logLevelStrings := [][]string{
@@ -41,7 +86,7 @@ func getLogLevelEmoji(level string) string {
 	for _, llstrings := range logLevelStrings {
 		for _, llstring := range llstrings {
 			if llstring == level {
-				return logLevelEmoji[llstrings[0]]
+				return llstrings[0]
 			}
 		}
 	}
@@ -32,3 +32,92 @@ func TestGetLogLevelEmoji(t *testing.T) {
 		assert.Equal(t, actual, test.expected)
 	}
 }
+
+func TestGetLogLevelName(t *testing.T) {
+	tests := []struct {
+		level    string
+		expected string
+	}{
+		{"DEBUG", "debug"},
+		{"information", "info"},
+		{"Warning", "warn"},
+		{"err", "error"},
+		{"critical", "fatal"},
+		{"invalid", ""},
+		{"", ""},
+	}
+
+	for _, test := range tests {
+		assert.Equal(t, test.expected, getLogLevelName(test.level), test.level)
+	}
+}
+
+func TestHashToken(t *testing.T) {
+	tests := []struct {
+		name     string
+		key      string
+		token    string
+		expected string
+	}{
+		{
+			name:     "empty key and token",
+			key:      "",
+			token:    "",
+			expected: "b613679a0814d9ec772f95d778c35fc5ff1697c493715653c6c712144292c5ad",
+		},
+		{
+			name:     "key and token",
+			key:      "key",
+			token:    "The quick brown fox jumps over the lazy dog",
+			expected: "f7bc83f430538424b13298e6aa6fb143ef4d59a14946175997479dbc2d1a3cd8",
+		},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			actual := hashToken(test.key, test.token)
+			assert.Equal(t, test.expected, actual)
+			assert.True(t, isTokenHash(actual))
+		})
+	}
+}
+
+func TestIsTokenHash(t *testing.T) {
+	tests := []struct {
+		value    string
+		expected bool
+	}{
+		{"f7bc83f430538424b13298e6aa6fb143ef4d59a14946175997479dbc2d1a3cd8", true},
+		{"f7bc83f430538424b13298e6aa6fb143ef4d59a14946175997479dbc2d1a3cdz", false},
+		{"f7bc83f4", false},
+		{"1b4e28ba-2fa1-11d2-883f-0016d3cca427", false},
+		{"", false},
+	}
+
+	for _, test := range tests {
+		assert.Equal(t, test.expected, isTokenHash(test.value), test.value)
+	}
+}
+
+func TestGetShortestKeyBetween(t *testing.T) {
+	tests := []struct {
+		lo       string
+		hi       string
+		expected string
+	}{
+		{"a", "b", "b"},
+		{"abc", "abd", "abd"},
+		{"abc", "abda", "abd"},
+		{"a", "ab", "ab"},
+		{"", "b", "b"},
+		{"2fa1", "f7bc83f4", "f"},
+		{"f7bc12", "f7bc83f4", "f7bc8"},
+	}
+
+	for _, test := range tests {
+		actual := getShortestKeyBetween(test.lo, test.hi)
+		assert.Equal(t, test.expected, actual, "%s %s", test.lo, test.hi)
+		assert.Greater(t, actual, test.lo)
+		assert.LessOrEqual(t, actual, test.hi)
+	}
+}
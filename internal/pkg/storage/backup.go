@@ -0,0 +1,19 @@
+package storage
+
+import (
+	"context"
+	"io"
+)
+
+// Backuper is implemented by the storages that can back up their own
+// data while they're in use and restore it, such as badgerDB. Unlike
+// an export, a backup can only be restored into the same kind of storage.
+type Backuper interface {
+	// Backup writes a full backup of the database to w.
+	Backup(ctx context.Context, w io.Writer) error
+
+	// Restore loads a backup written by Backup into the database,
+	// which should be empty, and applies the migrations the
+	// backup is missing.
+	Restore(ctx context.Context, r io.Reader) error
+}
@@ -0,0 +1,86 @@
+package badgerdb
+
+import (
+	"context"
+	"io"
+
+	"github.com/psyb0t/telegram-logger/internal/pkg/storage"
+)
+
+// maxPendingRestoreWrites is the number of writes
+// Restore lets badger have in flight at once.
+const maxPendingRestoreWrites = 256
+
+// Backup writes a full backup of the database to w using badger's
+// streaming backup so the database can be used in the meantime.
+// It stops as soon as the given context is done.
+func (db *badgerDB) Backup(ctx context.Context, w io.Writer) error {
+	db.wg.Add(1)
+	defer db.wg.Done()
+
+	if err := db.ctxErr(ctx); err != nil {
+		return err
+	}
+
+	// the stream can't be canceled so the
+	// writes fail once the context is done
+	_, err := db.db.Backup(ctxWriter{ctx: ctx, db: db, w: w}, 0)
+
+	return err
+}
+
+// Restore loads a backup written by Backup into the database, which
+// should be empty, and applies the migrations the backup is missing
+// since it may have been made by an older version of the app.
+// It stops as soon as the given context is done.
+func (db *badgerDB) Restore(ctx context.Context, r io.Reader) error {
+	if err := db.restore(ctx, r); err != nil {
+		return err
+	}
+
+	return storage.Migrate[*badgerDB](ctx, db, getMigrations())
+}
+
+// restore loads a backup written by Backup into the database.
+func (db *badgerDB) restore(ctx context.Context, r io.Reader) error {
+	db.wg.Add(1)
+	defer db.wg.Done()
+
+	if err := db.ctxErr(ctx); err != nil {
+		return err
+	}
+
+	return db.db.Load(ctxReader{ctx: ctx, db: db, r: r}, maxPendingRestoreWrites)
+}
+
+// ctxWriter is an io.Writer that fails once the given
+// context or the one of the database is done.
+type ctxWriter struct {
+	ctx context.Context //nolint:containedctx
+	db  *badgerDB
+	w   io.Writer
+}
+
+func (w ctxWriter) Write(p []byte) (int, error) {
+	if err := w.db.ctxErr(w.ctx); err != nil {
+		return 0, err
+	}
+
+	return w.w.Write(p)
+}
+
+// ctxReader is an io.Reader that fails once the given
+// context or the one of the database is done.
+type ctxReader struct {
+	ctx context.Context //nolint:containedctx
+	db  *badgerDB
+	r   io.Reader
+}
+
+func (r ctxReader) Read(p []byte) (int, error) {
+	if err := r.db.ctxErr(r.ctx); err != nil {
+		return 0, err
+	}
+
+	return r.r.Read(p)
+}
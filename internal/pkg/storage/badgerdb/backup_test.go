@@ -0,0 +1,67 @@
+package badgerdb
+
+import (
+	"bytes"
+	"context"
+	"testing"
+
+	"github.com/psyb0t/telegram-logger/internal/pkg/storage"
+	"github.com/psyb0t/telegram-logger/internal/pkg/types"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestBadgerDB_BackupRestore(t *testing.T) {
+	ctx := context.Background()
+
+	source, err := New(ctx, Config{})
+	require.NoError(t, err)
+	require.NoError(t, source.Open(ctx, t.TempDir()))
+
+	defer source.Close()
+
+	users := []types.User{
+		{ID: "a", TelegramChatID: 1},
+		{ID: "b", TelegramChatID: 1},
+		{ID: "c", TelegramChatID: 2},
+	}
+
+	for _, user := range users {
+		require.NoError(t, source.GetUserRepositoryWriter().Create(ctx, user))
+	}
+
+	require.NoError(t, source.GetSettingRepositoryWriter().Set(ctx, "a", "1"))
+
+	backup := &bytes.Buffer{}
+	require.NoError(t, source.(storage.Backuper).Backup(ctx, backup))
+
+	target, err := New(ctx, Config{})
+	require.NoError(t, err)
+	require.NoError(t, target.Open(ctx, t.TempDir()))
+
+	defer target.Close()
+
+	require.NoError(t, target.(storage.Backuper).Restore(ctx, backup))
+
+	all, err := target.GetUserRepositoryReader().GetAll(ctx)
+	require.NoError(t, err)
+	assert.Equal(t, users, all)
+
+	// the chat index is restored too
+	all, err = target.GetUserRepositoryReader().GetAllByTelegramChatID(ctx, 1)
+	require.NoError(t, err)
+	assert.Equal(t, users[:2], all)
+
+	value, err := target.GetSettingRepositoryReader().Get(ctx, "a")
+	require.NoError(t, err)
+	assert.Equal(t, "1", value)
+
+	version, err := target.(*badgerDB).GetSchemaVersion(ctx)
+	require.NoError(t, err)
+	assert.Equal(t, len(getMigrations()), version)
+
+	canceledCtx, cancel := context.WithCancel(ctx)
+	cancel()
+
+	assert.ErrorIs(t, source.(storage.Backuper).Backup(canceledCtx, &bytes.Buffer{}), context.Canceled)
+}
@@ -5,61 +5,133 @@ import (
 	"context"
 	"errors"
 	"sync"
+	"time"
 
 	badger "github.com/dgraph-io/badger/v3"
 	"github.com/psyb0t/telegram-logger/internal/pkg/storage"
 )
 
-const prefixUserKey = "user-"
+const (
+	prefixUserKey    = "user-"
+	prefixRoleKey    = "role-"
+	prefixInviteKey  = "invite-"
+	prefixSettingKey = "setting-"
 
-// filterFunc is a function that accepts a key and its value as parameters
-// used for filtering results retrieved from the database
-//
-// returns a boolean value. true if filter passed, false if not.
-type filterFunc func(key, val []byte) bool
+	// prefixChatIndexKey is the prefix of the keys indexing the users by
+	// their Telegram chat ID. The keys are chat-<chatID>-<userID> and
+	// have no value so the users of a chat can be found by prefix.
+	prefixChatIndexKey = "chat-"
+
+	// prefixMetaKey is the prefix of the keys holding
+	// information about the database itself.
+	prefixMetaKey = "meta-"
+
+	// maxTxRetries is the number of times a transaction is retried
+	// when it conflicts with another one.
+	maxTxRetries = 10
+)
+
+// Config holds the settings of a badgerDB instance.
+type Config struct {
+	// GCInterval is how often the garbage collection of the value log
+	// runs. It's disabled if it's 0.
+	GCInterval time.Duration
+
+	// FlattenInterval is how often the LSM tree is compacted into
+	// a single level. It's disabled if it's 0.
+	FlattenInterval time.Duration
+
+	// EncryptionKey is the AES key the data is encrypted with. It must
+	// be 16, 24 or 32 bytes long. The data isn't encrypted if it's empty.
+	EncryptionKey []byte
+
+	// IndexCacheSize is how many bytes of memory the indices and bloom
+	// filters of the tables can use. They're all kept in memory if it's 0
+	// unless the data is encrypted, in which case it's 100MB.
+	IndexCacheSize int64
+}
 
 // badgerDB is a struct that implements the Storage interface using a BadgerDB database.
 type badgerDB struct {
-	ctx            context.Context //nolint:containedctx
-	cancelFunc     context.CancelFunc
-	db             *badger.DB
-	wg             sync.WaitGroup
-	userRepository struct {
+	ctx        context.Context //nolint:containedctx
+	cancelFunc context.CancelFunc
+	config     Config
+	db         *badger.DB
+	wg         sync.WaitGroup
+	// the maintenance goroutine has a context and wait group of its own
+	// so that Close can stop it before waiting for the operations
+	maintenanceCtx  context.Context //nolint:containedctx
+	stopMaintenance context.CancelFunc
+	maintenanceWG   sync.WaitGroup
+	maintenance     maintenanceStats
+	userRepository  struct {
 		reader storage.UserRepositoryReader
 		writer storage.UserRepositoryWriter
 	}
+	roleRepository struct {
+		reader storage.RoleRepositoryReader
+		writer storage.RoleRepositoryWriter
+	}
+	inviteRepository struct {
+		reader storage.InviteRepositoryReader
+		writer storage.InviteRepositoryWriter
+	}
+	settingRepository struct {
+		reader storage.SettingRepositoryReader
+		writer storage.SettingRepositoryWriter
+	}
 }
 
-// New creates and returns a new badgerDB instance.
-func New(parentCtx context.Context) (storage.Storage, error) {
-	db := &badgerDB{}
+// New creates and returns a new badgerDB instance with the given config.
+func New(parentCtx context.Context, cfg Config) (storage.Storage, error) {
+	db := &badgerDB{config: cfg}
 	db.ctx, db.cancelFunc = context.WithCancel(parentCtx)
+	db.maintenanceCtx, db.stopMaintenance = context.WithCancel(db.ctx)
 
-	db.userRepository.reader = newUserRepositoryReader(db)
-	db.userRepository.writer = newUserRepositoryWriter(db)
+	// the repositories run each operation in a transaction of its own
+	t := txn{db: db}
+
+	db.userRepository.reader = newUserRepositoryReader(t)
+	db.userRepository.writer = newUserRepositoryWriter(t)
+	db.roleRepository.reader = newRoleRepositoryReader(t)
+	db.roleRepository.writer = newRoleRepositoryWriter(t)
+	db.inviteRepository.reader = newInviteRepositoryReader(t)
+	db.inviteRepository.writer = newInviteRepositoryWriter(t)
+	db.settingRepository.reader = newSettingRepositoryReader(t)
+	db.settingRepository.writer = newSettingRepositoryWriter(t)
 
 	return db, nil
 }
 
-// Open opens a connection to a BadgerDB database.
+// Open opens a connection to a BadgerDB database and starts the
+// maintenance goroutine which runs until the database is closed.
 // The provided DSN (Data Source Name) specifies the location of the database.
-func (db *badgerDB) Open(dsn string) error {
+func (db *badgerDB) Open(ctx context.Context, dsn string) error {
 	var err error
 
 	// Open a connection to the database.
-	opts := badger.DefaultOptions(dsn)
-	opts.Logger = nil
-
-	db.db, err = badger.Open(opts)
+	db.db, err = openDB(dsn, db.config)
 	if err != nil {
 		return err
 	}
 
+	if err := storage.Migrate[*badgerDB](ctx, db, getMigrations()); err != nil {
+		db.db.Close()
+
+		return err
+	}
+
+	db.startMaintenance()
+
 	return nil
 }
 
-// Close closes the connection to the BadgerDB database.
+// Close stops the maintenance goroutine and closes
+// the connection to the BadgerDB database.
 func (db *badgerDB) Close() error {
+	db.stopMaintenance()
+	db.maintenanceWG.Wait()
+
 	db.wg.Wait()
 	db.cancelFunc()
 
@@ -67,9 +139,19 @@ func (db *badgerDB) Close() error {
 }
 
 // Ping checks if the database is reachable and responding.
-// badgerDB does not have a method for this so it will just return nil.
-func (db *badgerDB) Ping() error {
-	return nil
+// badgerDB does not have a method for this so it will just
+// return nil unless the given context is done.
+func (db *badgerDB) Ping(ctx context.Context) error {
+	return db.ctxErr(ctx)
+}
+
+// InTx runs fn in a transaction which is committed if fn returns nil
+// and discarded otherwise. If the transaction conflicts with
+// another one, fn is run again in a new one.
+func (db *badgerDB) InTx(ctx context.Context, fn func(tx storage.Repositories) error) error {
+	return db.updateTx(ctx, func(tx *badger.Txn) error {
+		return fn(txRepositories{db: txn{db: db, tx: tx}})
+	})
 }
 
 // GetUserRepositoryReader returns a repository for reading user data from the database.
@@ -82,177 +164,82 @@ func (db *badgerDB) GetUserRepositoryWriter() storage.UserRepositoryWriter {
 	return db.userRepository.writer
 }
 
-// get retrieves a value by key.
-func (db *badgerDB) get(key []byte) ([]byte, error) {
-	db.wg.Add(1)
-	defer db.wg.Done()
-
-	// Start a new transaction.
-	tx := db.db.NewTransaction(false)
-	defer tx.Discard()
-
-	// Look up the data in the database using the provided key.
-	item, err := tx.Get(key)
-	if err != nil {
-		// If the key is not found, return an error.
-		if errors.Is(err, badger.ErrKeyNotFound) {
-			return nil, storage.ErrNotFound
-		}
-
-		return nil, err
-	}
-
-	// Retrieve the data from the item.
-	val, err := item.ValueCopy(nil)
-	if err != nil {
-		return nil, err
-	}
-
-	return val, err
+// GetRoleRepositoryReader returns a repository for reading chat role data from the database.
+func (db *badgerDB) GetRoleRepositoryReader() storage.RoleRepositoryReader {
+	return db.roleRepository.reader
 }
 
-// getAllByPrefix retrieves a slice of values for keys prefixed with prefix.
-func (db *badgerDB) getAllByPrefix(prefix []byte) ([][]byte, error) {
-	db.wg.Add(1)
-	defer db.wg.Done()
-
-	result := [][]byte{}
-
-	// Start a new transaction.
-	tx := db.db.NewTransaction(false)
-	defer tx.Discard()
-
-	// Create a new iterator to iterate over all prefixed keys in the database.
-	opts := badger.DefaultIteratorOptions
-	opts.Prefix = prefix
-
-	it := tx.NewIterator(opts)
-	defer it.Close()
-
-	// Iterate over all keys in the database.
-	for it.Seek(prefix); it.ValidForPrefix(prefix); it.Next() {
-		// Retrieve the data for the current item.
-		item := it.Item()
-		val, err := item.ValueCopy(nil)
-		if err != nil {
-			return nil, err
-		}
-
-		// Append the value to the result slice.
-		result = append(result, val)
-	}
-
-	return result, nil
+// GetRoleRepositoryWriter returns a repository for writing chat role data to the database.
+func (db *badgerDB) GetRoleRepositoryWriter() storage.RoleRepositoryWriter {
+	return db.roleRepository.writer
 }
 
-// getByPrefixAndFilterFunc retrieves the first n number of keys and their values from the db
-// based on the given key prefix and whose value also passes the filterFunc
-// if n < 0 then all matches get returned
-//
-// the results are returned as [][][]byte
-//
-// eg:
-//
-//	results := [][][]byte{
-//			[][]byte{[]byte("key1"), []byte("val1")},
-//			[][]byte{[]byte("key2"), []byte("val2")},
-//		}
-func (db *badgerDB) getByPrefixAndFilterFunc(prefix []byte, filterFn filterFunc, n int) ([][][]byte, error) {
-	db.wg.Add(1)
-	defer db.wg.Done()
-
-	results := [][][]byte{}
-
-	// if desired num results is 0 then just return
-	if n == 0 {
-		return results, nil
-	}
-
-	// Start a new transaction.
-	tx := db.db.NewTransaction(false)
-	defer tx.Discard()
-
-	// Create a new iterator to iterate over all prefixed keys in the database.
-	opts := badger.DefaultIteratorOptions
-	opts.Prefix = prefix
-
-	it := tx.NewIterator(opts)
-	defer it.Close()
-
-	// Iterate over all keys in the database.
-	for it.Seek(prefix); it.ValidForPrefix(prefix); it.Next() {
-		// Retrieve the data for the current item.
-		item := it.Item()
+// GetInviteRepositoryReader returns a repository for reading invite data from the database.
+func (db *badgerDB) GetInviteRepositoryReader() storage.InviteRepositoryReader {
+	return db.inviteRepository.reader
+}
 
-		val, err := item.ValueCopy(nil)
-		if err != nil {
-			return nil, err
-		}
+// GetInviteRepositoryWriter returns a repository for writing invite data to the database.
+func (db *badgerDB) GetInviteRepositoryWriter() storage.InviteRepositoryWriter {
+	return db.inviteRepository.writer
+}
 
-		// Check if the value passes the filter func
-		key := item.KeyCopy(nil)
-		if filterFn(key, val) {
-			// add the result to the list of results
-			results = append(results, [][]byte{item.KeyCopy(nil), val})
-			// if num results is the desired one return
-			if len(results) == n {
-				return results, nil
-			}
-		}
-	}
+// GetSettingRepositoryReader returns a repository for reading settings from the database.
+func (db *badgerDB) GetSettingRepositoryReader() storage.SettingRepositoryReader {
+	return db.settingRepository.reader
+}
 
-	return results, nil
+// GetSettingRepositoryWriter returns a repository for writing settings to the database.
+func (db *badgerDB) GetSettingRepositoryWriter() storage.SettingRepositoryWriter {
+	return db.settingRepository.writer
 }
 
-// create stores a value for the given key.
-func (db *badgerDB) create(key []byte, val []byte) error {
+// view runs the given function in a read-only transaction
+// unless the given context is already done.
+func (db *badgerDB) view(ctx context.Context, fn func(tx *badger.Txn) error) error {
 	db.wg.Add(1)
 	defer db.wg.Done()
 
-	// Start a new transaction.
-	tx := db.db.NewTransaction(true)
-	defer tx.Discard()
-
-	// Set the data in the database using the provided key.
-	if err := tx.Set(key, val); err != nil {
+	if err := db.ctxErr(ctx); err != nil {
 		return err
 	}
 
-	// Commit the transaction.
-	return tx.Commit()
+	return db.db.View(fn)
 }
 
-// delete removes data from the database by the given key.
-func (db *badgerDB) delete(key []byte) error {
+// updateTx runs the given function in a read-write transaction which
+// is committed if the function returns no error. If the transaction
+// conflicts with another one, the function is run again in a new one
+// as long as the given context is not done.
+func (db *badgerDB) updateTx(ctx context.Context, fn func(tx *badger.Txn) error) error {
 	db.wg.Add(1)
 	defer db.wg.Done()
 
-	// Start a new transaction.
-	tx := db.db.NewTransaction(true)
-	defer tx.Discard()
-
-	// Delete the data from the database using the provided key.
-	if err := tx.Delete(key); err != nil {
-		// If the key is not found, return the ErrNotFound error.
-		if errors.Is(err, badger.ErrKeyNotFound) {
-			return storage.ErrNotFound
+	for i := 0; i < maxTxRetries; i++ {
+		if err := db.ctxErr(ctx); err != nil {
+			return err
 		}
 
-		return err
+		err := db.db.Update(fn)
+		if !errors.Is(err, badger.ErrConflict) {
+			return err
+		}
 	}
 
-	// Commit the transaction.
-	return tx.Commit()
+	return ErrTooManyTxRetries
 }
 
-/*
-// isCtxDone checks if the context is done and returns a boolean
-func (db *badgerDB) isCtxDone() bool {
+// ctxErr returns the error of the given context if it's done or the one
+// of the database's own context which is done once the parent context
+// given to New is, so that the operations stop both when the caller
+// gives up and when the app shuts down.
+func (db *badgerDB) ctxErr(ctx context.Context) error {
 	select {
-	default:
-		return false
+	case <-ctx.Done():
+		return ctx.Err()
 	case <-db.ctx.Done():
-		return true
+		return db.ctx.Err()
+	default:
+		return nil
 	}
 }
-*/
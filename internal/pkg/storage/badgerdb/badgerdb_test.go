@@ -0,0 +1,38 @@
+package badgerdb
+
+import (
+	"context"
+	"testing"
+
+	"github.com/psyb0t/telegram-logger/internal/pkg/storage"
+	"github.com/psyb0t/telegram-logger/internal/pkg/storage/storagetest"
+	"github.com/stretchr/testify/require"
+)
+
+func TestBadgerDB(t *testing.T) {
+	ctx := context.Background()
+
+	storagetest.Run(t, func(t *testing.T) storage.Storage {
+		db, err := New(ctx, Config{})
+		require.NoError(t, err)
+		require.NoError(t, db.Open(ctx, t.TempDir()))
+
+		return db
+	})
+}
+
+func TestBadgerDB_parentContextDone(t *testing.T) {
+	parentCtx, cancel := context.WithCancel(context.Background())
+
+	db, err := New(parentCtx, Config{})
+	require.NoError(t, err)
+	require.NoError(t, db.Open(context.Background(), t.TempDir()))
+
+	defer db.Close()
+
+	// the operations stop on shutdown even if the caller doesn't
+	cancel()
+
+	_, err = db.GetUserRepositoryReader().GetAll(context.Background())
+	require.ErrorIs(t, err, context.Canceled)
+}
@@ -0,0 +1,61 @@
+package badgerdb
+
+import (
+	"context"
+	"encoding/json"
+
+	badger "github.com/dgraph-io/badger/v3"
+	"github.com/psyb0t/telegram-logger/internal/pkg/types"
+)
+
+// rebuildChatIndex removes all of the chat index keys and adds one
+// for every stored user. It's written in batches since the whole
+// database may not fit in a single transaction. The index is kept up
+// to date by the user writes from then on. It stops as soon as the
+// given context is done leaving a partial index behind which is
+// rebuilt from scratch on the next run.
+func (db *badgerDB) rebuildChatIndex(ctx context.Context) error {
+	if err := db.db.DropPrefix([]byte(prefixChatIndexKey)); err != nil {
+		return err
+	}
+
+	wb := db.db.NewWriteBatch()
+	defer wb.Cancel()
+
+	err := db.view(ctx, func(tx *badger.Txn) error {
+		prefix := []byte(prefixUserKey)
+
+		opts := badger.DefaultIteratorOptions
+		opts.Prefix = prefix
+
+		it := tx.NewIterator(opts)
+		defer it.Close()
+
+		for it.Seek(prefix); it.ValidForPrefix(prefix); it.Next() {
+			if err := db.ctxErr(ctx); err != nil {
+				return err
+			}
+
+			val, err := it.Item().ValueCopy(nil)
+			if err != nil {
+				return err
+			}
+
+			var user types.User
+			if err := json.Unmarshal(val, &user); err != nil {
+				return err
+			}
+
+			if err := wb.Set(getChatIndexKey(user.TelegramChatID, user.ID), nil); err != nil {
+				return err
+			}
+		}
+
+		return nil
+	})
+	if err != nil {
+		return err
+	}
+
+	return wb.Flush()
+}
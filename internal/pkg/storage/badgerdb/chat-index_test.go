@@ -0,0 +1,54 @@
+package badgerdb
+
+import (
+	"context"
+	"testing"
+
+	"github.com/psyb0t/telegram-logger/internal/pkg/types"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestBadgerDB_rebuildChatIndex(t *testing.T) {
+	ctx := context.Background()
+
+	dsn := t.TempDir()
+
+	db, err := New(ctx, Config{})
+	require.NoError(t, err)
+	require.NoError(t, db.Open(ctx, dsn))
+
+	users := []types.User{
+		{ID: "a", TelegramChatID: 1},
+		{ID: "b", TelegramChatID: 1},
+		{ID: "c", TelegramChatID: 12},
+	}
+
+	for _, user := range users {
+		require.NoError(t, db.GetUserRepositoryWriter().Create(ctx, user))
+	}
+
+	// make it look like a database created before the migrations
+	bdb := db.(*badgerDB) //nolint:forcetypeassert
+	require.NoError(t, bdb.db.DropPrefix([]byte(prefixChatIndexKey), []byte(prefixMetaKey)))
+
+	all, err := db.GetUserRepositoryReader().GetAllByTelegramChatID(ctx, 1)
+	require.NoError(t, err)
+	assert.Empty(t, all)
+
+	require.NoError(t, db.Close())
+
+	db, err = New(ctx, Config{})
+	require.NoError(t, err)
+	require.NoError(t, db.Open(ctx, dsn))
+
+	defer db.Close()
+
+	all, err = db.GetUserRepositoryReader().GetAllByTelegramChatID(ctx, 1)
+	require.NoError(t, err)
+	assert.Equal(t, users[:2], all)
+
+	found, err := db.GetUserRepositoryReader().GetByTelegramChatID(ctx, 12)
+	require.NoError(t, err)
+	assert.Equal(t, users[2], found)
+}
@@ -0,0 +1,102 @@
+package badgerdb
+
+import (
+	"crypto/aes"
+	"errors"
+
+	badger "github.com/dgraph-io/badger/v3"
+)
+
+// defaultEncryptedIndexCacheSize is the size of the index cache of an
+// encrypted database if none is configured. Badger can't read encrypted
+// tables without one since it only keeps the decrypted indices in it.
+const defaultEncryptedIndexCacheSize = 100 << 20
+
+// getOptions returns the badger options of the database
+// in the given directory with the given config.
+func getOptions(dir string, cfg Config) badger.Options {
+	indexCacheSize := cfg.IndexCacheSize
+	if len(cfg.EncryptionKey) > 0 && indexCacheSize <= 0 {
+		indexCacheSize = defaultEncryptedIndexCacheSize
+	}
+
+	opts := badger.DefaultOptions(dir).
+		WithEncryptionKey(cfg.EncryptionKey).
+		WithIndexCacheSize(indexCacheSize)
+	opts.Logger = nil
+
+	return opts
+}
+
+// openDB opens the badger database in the given directory with the given
+// config. The errors badger returns when the encryption key is wrong
+// are replaced by clearer ones.
+func openDB(dir string, cfg Config) (*badger.DB, error) {
+	if len(cfg.EncryptionKey) > 0 && !isValidEncryptionKey(cfg.EncryptionKey) {
+		return nil, ErrInvalidEncryptionKey
+	}
+
+	db, err := badger.Open(getOptions(dir, cfg))
+	if errors.Is(err, badger.ErrEncryptionKeyMismatch) {
+		return nil, ErrEncryptionKeyMismatch
+	}
+
+	return db, err
+}
+
+// RotateEncryptionKey encrypts the database in the given directory, which
+// is encrypted with cfg.EncryptionKey, with newKey instead. Badger encrypts
+// the data with data keys of its own which are the only thing encrypted
+// with the given key so only they are rewritten.
+//
+// It returns ErrNotEncrypted if cfg.EncryptionKey is empty since badger
+// would only encrypt the data written from then on and leave the existing
+// data as it is.
+//
+// The database must not be in use. It's opened first, which fails if
+// another process has it open and checks the current key.
+func RotateEncryptionKey(dir string, cfg Config, newKey []byte) error {
+	if len(cfg.EncryptionKey) == 0 {
+		return ErrNotEncrypted
+	}
+
+	if !isValidEncryptionKey(newKey) {
+		return ErrInvalidEncryptionKey
+	}
+
+	db, err := openDB(dir, cfg)
+	if err != nil {
+		return err
+	}
+
+	if err := db.Close(); err != nil {
+		return err
+	}
+
+	opts := getOptions(dir, cfg)
+	registryOpts := badger.KeyRegistryOptions{
+		Dir:                           opts.Dir,
+		ReadOnly:                      true,
+		EncryptionKey:                 opts.EncryptionKey,
+		EncryptionKeyRotationDuration: opts.EncryptionKeyRotationDuration,
+	}
+
+	// the registry holds the data keys and is
+	// closed right away when it's read only
+	registry, err := badger.OpenKeyRegistry(registryOpts)
+	if err != nil {
+		return err
+	}
+
+	registryOpts.EncryptionKey = newKey
+
+	return badger.WriteKeyRegistry(registry, registryOpts)
+}
+
+// isValidEncryptionKey checks if the given key can be used to encrypt
+// the database. AES only takes keys of the lengths badger accepts.
+func isValidEncryptionKey(key []byte) bool {
+	_, err := aes.NewCipher(key)
+
+	return err == nil
+}
@@ -0,0 +1,111 @@
+package badgerdb
+
+import (
+	"bytes"
+	"context"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/psyb0t/telegram-logger/internal/pkg/types"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestBadgerDB_encryption(t *testing.T) {
+	ctx := context.Background()
+	dir := t.TempDir()
+	key := []byte("0123456789abcdef0123456789abcdef")
+	newKey := []byte("fedcba9876543210")
+	user := types.User{ID: "plaintext-user-id", TelegramChatID: 1}
+
+	openDB := func(cfg Config) error {
+		db, err := New(ctx, cfg)
+		require.NoError(t, err)
+
+		if err := db.Open(ctx, dir); err != nil {
+			return err
+		}
+
+		_, err = db.GetUserRepositoryReader().Get(ctx, user.ID)
+		require.NoError(t, err)
+
+		return db.Close()
+	}
+
+	db, err := New(ctx, Config{EncryptionKey: key, IndexCacheSize: 1 << 20})
+	require.NoError(t, err)
+	require.NoError(t, db.Open(ctx, dir))
+	require.NoError(t, db.GetUserRepositoryWriter().Create(ctx, user))
+	require.NoError(t, db.Close())
+
+	t.Run("data is encrypted on disk", func(t *testing.T) {
+		err := filepath.Walk(dir, func(path string, info os.FileInfo, err error) error {
+			if err != nil || info.IsDir() {
+				return err
+			}
+
+			data, err := os.ReadFile(path)
+			require.NoError(t, err)
+			assert.False(t, bytes.Contains(data, []byte(user.ID)), path)
+
+			return nil
+		})
+		require.NoError(t, err)
+	})
+
+	t.Run("same key", func(t *testing.T) {
+		require.NoError(t, openDB(Config{EncryptionKey: key}))
+	})
+
+	t.Run("wrong key", func(t *testing.T) {
+		err := openDB(Config{EncryptionKey: newKey})
+		require.ErrorIs(t, err, ErrEncryptionKeyMismatch)
+	})
+
+	t.Run("no key", func(t *testing.T) {
+		err := openDB(Config{})
+		require.ErrorIs(t, err, ErrEncryptionKeyMismatch)
+	})
+
+	t.Run("invalid key", func(t *testing.T) {
+		err := openDB(Config{EncryptionKey: []byte("short")})
+		require.ErrorIs(t, err, ErrInvalidEncryptionKey)
+	})
+
+	t.Run("rotate", func(t *testing.T) {
+		err := RotateEncryptionKey(dir, Config{EncryptionKey: key}, []byte("short"))
+		require.ErrorIs(t, err, ErrInvalidEncryptionKey)
+
+		err = RotateEncryptionKey(dir, Config{EncryptionKey: newKey}, key)
+		require.ErrorIs(t, err, ErrEncryptionKeyMismatch)
+
+		require.NoError(t, RotateEncryptionKey(dir, Config{EncryptionKey: key}, newKey))
+
+		require.ErrorIs(t, openDB(Config{EncryptionKey: key}), ErrEncryptionKeyMismatch)
+		require.NoError(t, openDB(Config{EncryptionKey: newKey}))
+	})
+}
+
+func TestRotateEncryptionKey_unencrypted(t *testing.T) {
+	ctx := context.Background()
+	dir := t.TempDir()
+
+	db, err := New(ctx, Config{})
+	require.NoError(t, err)
+	require.NoError(t, db.Open(ctx, dir))
+	require.NoError(t, db.GetUserRepositoryWriter().Create(ctx, types.User{ID: "a", TelegramChatID: 1}))
+	require.NoError(t, db.Close())
+
+	assert.ErrorIs(t, RotateEncryptionKey(dir, Config{}, []byte("0123456789abcdef")), ErrNotEncrypted)
+
+	// the database is left unencrypted
+	db, err = New(ctx, Config{})
+	require.NoError(t, err)
+	require.NoError(t, db.Open(ctx, dir))
+
+	defer db.Close()
+
+	_, err = db.GetUserRepositoryReader().Get(ctx, "a")
+	require.NoError(t, err)
+}
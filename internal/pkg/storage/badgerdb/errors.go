@@ -1 +1,19 @@
 package badgerdb
+
+import "errors"
+
+var (
+	// ErrTooManyTxRetries is returned when a transaction keeps
+	// conflicting with the ones running at the same time.
+	ErrTooManyTxRetries = errors.New("too many transaction retries")
+	// ErrEncryptionKeyMismatch is returned when the database is opened with a
+	// different encryption key than the one it's encrypted with, including
+	// when an encrypted database is opened without a key and vice versa.
+	ErrEncryptionKeyMismatch = errors.New("the encryption key doesn't match the one the database is encrypted with")
+	// ErrInvalidEncryptionKey is returned when the length of an encryption key is invalid.
+	ErrInvalidEncryptionKey = errors.New("the encryption key must be 16, 24 or 32 bytes long")
+	// ErrNotEncrypted is returned when rotating the encryption key of a
+	// database which isn't encrypted since it would leave the existing data
+	// unencrypted. It has to be exported and imported into a new encrypted one.
+	ErrNotEncrypted = errors.New("the database isn't encrypted")
+)
@@ -0,0 +1,68 @@
+package badgerdb
+
+import (
+	"context"
+	"encoding/json"
+
+	"github.com/psyb0t/telegram-logger/internal/pkg/storage"
+	"github.com/psyb0t/telegram-logger/internal/pkg/types"
+)
+
+// inviteRepositoryReader is a struct that implements the
+// storage.InviteRepositoryReader interface using a badgerDB instance.
+type inviteRepositoryReader struct {
+	// db runs the operations in the underlying badgerDB
+	// instance, in a transaction if it holds one.
+	db txn
+}
+
+// newInviteRepositoryReader creates and returns
+// a new inviteRepositoryReader instance.
+func newInviteRepositoryReader(db txn) storage.InviteRepositoryReader {
+	return inviteRepositoryReader{db: db}
+}
+
+// Get retrieves an invite by ID.
+func (r inviteRepositoryReader) Get(ctx context.Context, id string) (types.Invite, error) {
+	invite := types.Invite{}
+
+	if id == "" {
+		return invite, storage.ErrEmptyID
+	}
+
+	val, err := r.db.get(ctx, getInviteKey(id))
+	if err != nil {
+		return invite, err
+	}
+
+	// Unmarshal the invite data into the invite struct.
+	if err := json.Unmarshal(val, &invite); err != nil {
+		return invite, err
+	}
+
+	return invite, nil
+}
+
+// GetAll retrieves all invites from the database.
+func (r inviteRepositoryReader) GetAll(ctx context.Context) ([]types.Invite, error) {
+	invites := []types.Invite{}
+
+	// Get all invite data from the db
+	vals, err := r.db.getAllByPrefix(ctx, []byte(prefixInviteKey))
+	if err != nil {
+		return nil, err
+	}
+
+	// Go through all of the returned values
+	for _, val := range vals {
+		// Unmarshal the invite data into an invite struct.
+		var invite types.Invite
+		if err := json.Unmarshal(val, &invite); err != nil {
+			return invites, err
+		}
+
+		invites = append(invites, invite)
+	}
+
+	return invites, nil
+}
@@ -0,0 +1,182 @@
+package badgerdb
+
+import (
+	"context"
+	"errors"
+	"strconv"
+	"sync"
+	"time"
+
+	badger "github.com/dgraph-io/badger/v3"
+	"github.com/psyb0t/telegram-logger/internal/pkg/storage"
+)
+
+const (
+	// gcDiscardRatio is the fraction of a value log file which
+	// has to be stale for the garbage collection to rewrite it.
+	gcDiscardRatio = 0.5
+
+	// flattenWorkers is the number of goroutines compacting
+	// the LSM tree when it's flattened.
+	flattenWorkers = 1
+)
+
+// maintenanceStats holds what the maintenance goroutine did so far.
+type maintenanceStats struct {
+	mu             sync.Mutex
+	gcRuns         int
+	gcRewrites     int
+	lastGCAt       time.Time
+	lastGCErr      error
+	flattens       int
+	lastFlattenAt  time.Time
+	lastFlattenErr error
+}
+
+// startMaintenance starts the maintenance goroutine
+// unless all of the maintenance is disabled.
+func (db *badgerDB) startMaintenance() {
+	if db.config.GCInterval <= 0 && db.config.FlattenInterval <= 0 {
+		return
+	}
+
+	db.maintenanceWG.Add(1)
+
+	go db.runMaintenance(db.maintenanceCtx)
+}
+
+// runMaintenance garbage collects the value log and flattens the LSM tree
+// on their configured intervals until the given context is done.
+func (db *badgerDB) runMaintenance(ctx context.Context) {
+	defer db.maintenanceWG.Done()
+
+	gcTick, stopGCTicker := newTicker(db.config.GCInterval)
+	defer stopGCTicker()
+
+	flattenTick, stopFlattenTicker := newTicker(db.config.FlattenInterval)
+	defer stopFlattenTicker()
+
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case <-gcTick:
+			db.runValueLogGC(ctx)
+		case <-flattenTick:
+			db.flatten()
+		}
+	}
+}
+
+// runValueLogGC rewrites the value log files until none of them has
+// enough stale data left or the given context is done. Without it
+// the deleted and overwritten values are never removed from disk.
+func (db *badgerDB) runValueLogGC(ctx context.Context) {
+	var err error
+
+	rewrites := 0
+
+	for ctx.Err() == nil {
+		if err = db.db.RunValueLogGC(gcDiscardRatio); err != nil {
+			break
+		}
+
+		rewrites++
+	}
+
+	// badger returns ErrNoRewrite once there's nothing left to collect
+	if errors.Is(err, badger.ErrNoRewrite) {
+		err = nil
+	}
+
+	db.maintenance.mu.Lock()
+	defer db.maintenance.mu.Unlock()
+
+	db.maintenance.gcRuns++
+	db.maintenance.gcRewrites += rewrites
+	db.maintenance.lastGCAt = time.Now()
+	db.maintenance.lastGCErr = err
+}
+
+// flatten compacts all of the tables of the LSM tree into a single level.
+func (db *badgerDB) flatten() {
+	err := db.db.Flatten(flattenWorkers)
+
+	db.maintenance.mu.Lock()
+	defer db.maintenance.mu.Unlock()
+
+	db.maintenance.flattens++
+	db.maintenance.lastFlattenAt = time.Now()
+	db.maintenance.lastFlattenErr = err
+}
+
+// Stats returns the size of the database files and
+// what the maintenance goroutine did so far.
+func (db *badgerDB) Stats(ctx context.Context) ([]storage.Stat, error) {
+	db.wg.Add(1)
+	defer db.wg.Done()
+
+	if err := db.ctxErr(ctx); err != nil {
+		return nil, err
+	}
+
+	lsmSize, vlogSize := db.db.Size()
+
+	db.maintenance.mu.Lock()
+	defer db.maintenance.mu.Unlock()
+
+	return []storage.Stat{
+		{Name: "lsmSize", Value: strconv.FormatInt(lsmSize, 10)},
+		{Name: "vlogSize", Value: strconv.FormatInt(vlogSize, 10)},
+		{Name: "lsmTables", Value: strconv.Itoa(len(db.db.Tables()))},
+		{Name: "gcInterval", Value: formatInterval(db.config.GCInterval)},
+		{Name: "gcRuns", Value: strconv.Itoa(db.maintenance.gcRuns)},
+		{Name: "gcRewrites", Value: strconv.Itoa(db.maintenance.gcRewrites)},
+		{Name: "lastGC", Value: formatTime(db.maintenance.lastGCAt)},
+		{Name: "lastGCError", Value: formatErr(db.maintenance.lastGCErr)},
+		{Name: "flattenInterval", Value: formatInterval(db.config.FlattenInterval)},
+		{Name: "flattens", Value: strconv.Itoa(db.maintenance.flattens)},
+		{Name: "lastFlatten", Value: formatTime(db.maintenance.lastFlattenAt)},
+		{Name: "lastFlattenError", Value: formatErr(db.maintenance.lastFlattenErr)},
+	}, nil
+}
+
+// newTicker returns the channel of a ticker ticking on the given interval
+// and the function stopping it. If the interval is not positive the
+// channel is nil so that it never ticks.
+func newTicker(interval time.Duration) (<-chan time.Time, func()) {
+	if interval <= 0 {
+		return nil, func() {}
+	}
+
+	ticker := time.NewTicker(interval)
+
+	return ticker.C, ticker.Stop
+}
+
+// formatInterval formats a maintenance interval, 0 meaning disabled.
+func formatInterval(interval time.Duration) string {
+	if interval <= 0 {
+		return "disabled"
+	}
+
+	return interval.String()
+}
+
+// formatTime formats the time a maintenance task last ran at.
+func formatTime(t time.Time) string {
+	if t.IsZero() {
+		return "never"
+	}
+
+	return t.UTC().Format(time.RFC3339)
+}
+
+// formatErr formats the error a maintenance task last ran with.
+func formatErr(err error) string {
+	if err == nil {
+		return "none"
+	}
+
+	return err.Error()
+}
@@ -0,0 +1,69 @@
+package badgerdb
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/psyb0t/telegram-logger/internal/pkg/storage"
+	"github.com/psyb0t/telegram-logger/internal/pkg/types"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestBadgerDB_maintenance(t *testing.T) {
+	ctx := context.Background()
+
+	db, err := New(ctx, Config{
+		GCInterval:      10 * time.Millisecond,
+		FlattenInterval: 10 * time.Millisecond,
+	})
+	require.NoError(t, err)
+	require.NoError(t, db.Open(ctx, t.TempDir()))
+
+	for _, id := range []string{"a", "b", "c"} {
+		require.NoError(t, db.GetUserRepositoryWriter().Create(ctx, types.User{ID: id, TelegramChatID: 1}))
+		require.NoError(t, db.GetUserRepositoryWriter().Delete(ctx, id))
+	}
+
+	getStat := func(name string) string {
+		stats, err := db.(storage.StatsReporter).Stats(ctx)
+		require.NoError(t, err)
+
+		for _, stat := range stats {
+			if stat.Name == name {
+				return stat.Value
+			}
+		}
+
+		return ""
+	}
+
+	require.Eventually(t, func() bool {
+		return getStat("gcRuns") != "0" && getStat("flattens") != "0"
+	}, 5*time.Second, 10*time.Millisecond)
+
+	assert.Equal(t, "none", getStat("lastGCError"))
+	assert.Equal(t, "none", getStat("lastFlattenError"))
+	assert.NotEqual(t, "never", getStat("lastGC"))
+
+	// the maintenance goroutine stops before the database is closed
+	require.NoError(t, db.Close())
+}
+
+func TestBadgerDB_maintenanceDisabled(t *testing.T) {
+	ctx := context.Background()
+
+	db, err := New(ctx, Config{})
+	require.NoError(t, err)
+	require.NoError(t, db.Open(ctx, t.TempDir()))
+
+	defer db.Close()
+
+	stats, err := db.(storage.StatsReporter).Stats(ctx)
+	require.NoError(t, err)
+
+	assert.Contains(t, stats, storage.Stat{Name: "gcInterval", Value: "disabled"})
+	assert.Contains(t, stats, storage.Stat{Name: "gcRuns", Value: "0"})
+	assert.Contains(t, stats, storage.Stat{Name: "lastFlatten", Value: "never"})
+}
@@ -0,0 +1,51 @@
+package badgerdb
+
+import (
+	"context"
+	"errors"
+	"strconv"
+
+	"github.com/psyb0t/telegram-logger/internal/pkg/storage"
+)
+
+// metaKeySchemaVersion holds the version of the last applied migration.
+const metaKeySchemaVersion = "schemaVersion"
+
+// getMigrations returns the migrations of the database in order.
+// They can't run in a single transaction since they may have to
+// change the whole database so each of them must be idempotent.
+func getMigrations() []storage.Migration[*badgerDB] {
+	return []storage.Migration[*badgerDB]{
+		{
+			Version: 1,
+			Name:    "build the chat index",
+			Apply: func(ctx context.Context, db *badgerDB) error {
+				return db.rebuildChatIndex(ctx)
+			},
+		},
+	}
+}
+
+// GetSchemaVersion returns the version of the last applied
+// migration or 0 if no migration was applied yet.
+func (db *badgerDB) GetSchemaVersion(ctx context.Context) (int, error) {
+	val, err := txn{db: db}.get(ctx, getMetaKey(metaKeySchemaVersion))
+	if err != nil {
+		if errors.Is(err, storage.ErrNotFound) {
+			return 0, nil
+		}
+
+		return 0, err
+	}
+
+	return strconv.Atoi(string(val))
+}
+
+// ApplyMigration applies the given migration and records its version.
+func (db *badgerDB) ApplyMigration(ctx context.Context, m storage.Migration[*badgerDB]) error {
+	if err := m.Apply(ctx, db); err != nil {
+		return err
+	}
+
+	return txn{db: db}.create(ctx, getMetaKey(metaKeySchemaVersion), []byte(strconv.Itoa(m.Version)))
+}
@@ -0,0 +1,34 @@
+package badgerdb
+
+import (
+	"context"
+	"testing"
+
+	"github.com/psyb0t/telegram-logger/internal/pkg/storage"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestBadgerDB_migrate(t *testing.T) {
+	ctx := context.Background()
+
+	dsn := t.TempDir()
+
+	db, err := New(ctx, Config{})
+	require.NoError(t, err)
+	require.NoError(t, db.Open(ctx, dsn))
+
+	bdb := db.(*badgerDB) //nolint:forcetypeassert
+
+	version, err := bdb.GetSchemaVersion(ctx)
+	require.NoError(t, err)
+	assert.Equal(t, len(getMigrations()), version)
+
+	// pretend a newer version of the app migrated the database
+	require.NoError(t, txn{db: bdb}.create(ctx, getMetaKey(metaKeySchemaVersion), []byte("1000")))
+	require.NoError(t, db.Close())
+
+	db, err = New(ctx, Config{})
+	require.NoError(t, err)
+	assert.ErrorIs(t, db.Open(ctx, dsn), storage.ErrSchemaVersionTooNew)
+}
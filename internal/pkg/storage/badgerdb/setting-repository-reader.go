@@ -0,0 +1,71 @@
+package badgerdb
+
+import (
+	"context"
+
+	badger "github.com/dgraph-io/badger/v3"
+
+	"github.com/psyb0t/telegram-logger/internal/pkg/storage"
+)
+
+// settingRepositoryReader is a struct that implements the
+// storage.SettingRepositoryReader interface using a badgerDB instance.
+type settingRepositoryReader struct {
+	// db runs the operations in the underlying badgerDB
+	// instance, in a transaction if it holds one.
+	db txn
+}
+
+// newSettingRepositoryReader creates and returns
+// a new settingRepositoryReader instance.
+func newSettingRepositoryReader(db txn) storage.SettingRepositoryReader {
+	return settingRepositoryReader{db: db}
+}
+
+// Get retrieves the value of a setting by key.
+func (r settingRepositoryReader) Get(ctx context.Context, key string) (string, error) {
+	if key == "" {
+		return "", storage.ErrEmptyKey
+	}
+
+	val, err := r.db.get(ctx, getSettingKey(key))
+	if err != nil {
+		return "", err
+	}
+
+	return string(val), nil
+}
+
+// GetAll retrieves all settings from the database by key.
+func (r settingRepositoryReader) GetAll(ctx context.Context) (map[string]string, error) {
+	settings := map[string]string{}
+	prefix := []byte(prefixSettingKey)
+
+	err := r.db.view(ctx, func(tx *badger.Txn) error {
+		opts := badger.DefaultIteratorOptions
+		opts.Prefix = prefix
+
+		it := tx.NewIterator(opts)
+		defer it.Close()
+
+		for it.Seek(prefix); it.ValidForPrefix(prefix); it.Next() {
+			if err := r.db.db.ctxErr(ctx); err != nil {
+				return err
+			}
+
+			val, err := it.Item().ValueCopy(nil)
+			if err != nil {
+				return err
+			}
+
+			settings[string(it.Item().Key()[len(prefix):])] = string(val)
+		}
+
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	return settings, nil
+}
@@ -0,0 +1,31 @@
+package badgerdb
+
+import (
+	"context"
+
+	"github.com/psyb0t/telegram-logger/internal/pkg/storage"
+)
+
+// settingRepositoryWriter is a struct that implements the
+// storage.SettingRepositoryWriter interface using a badgerDB instance.
+type settingRepositoryWriter struct {
+	// db runs the operations in the underlying badgerDB
+	// instance, in a transaction if it holds one.
+	db txn
+}
+
+// newSettingRepositoryWriter creates and returns
+// a new settingRepositoryWriter instance.
+func newSettingRepositoryWriter(db txn) storage.SettingRepositoryWriter {
+	return settingRepositoryWriter{db: db}
+}
+
+// Set stores the value of a setting replacing the existing one.
+func (r settingRepositoryWriter) Set(ctx context.Context, key, value string) error {
+	if key == "" {
+		return storage.ErrEmptyKey
+	}
+
+	// Store the setting
+	return r.db.create(ctx, getSettingKey(key), []byte(value))
+}
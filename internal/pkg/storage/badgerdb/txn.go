@@ -0,0 +1,245 @@
+package badgerdb
+
+import (
+	"bytes"
+	"context"
+	"errors"
+
+	badger "github.com/dgraph-io/badger/v3"
+	"github.com/psyb0t/telegram-logger/internal/pkg/storage"
+)
+
+// txn is what the repositories read and write through. If it holds a
+// transaction, every operation runs in it. Otherwise each operation
+// runs in a transaction of its own.
+type txn struct {
+	db *badgerDB
+	tx *badger.Txn
+}
+
+// view runs the given function in the transaction or in a read-only one.
+func (t txn) view(ctx context.Context, fn func(tx *badger.Txn) error) error {
+	if t.tx != nil {
+		if err := t.db.ctxErr(ctx); err != nil {
+			return err
+		}
+
+		return fn(t.tx)
+	}
+
+	return t.db.view(ctx, fn)
+}
+
+// updateTx runs the given function in the transaction or in a
+// read-write one which is committed if the function returns no error.
+func (t txn) updateTx(ctx context.Context, fn func(tx *badger.Txn) error) error {
+	if t.tx != nil {
+		if err := t.db.ctxErr(ctx); err != nil {
+			return err
+		}
+
+		return fn(t.tx)
+	}
+
+	return t.db.updateTx(ctx, fn)
+}
+
+// get retrieves a value by key.
+func (t txn) get(ctx context.Context, key []byte) ([]byte, error) {
+	var val []byte
+
+	err := t.view(ctx, func(tx *badger.Txn) error {
+		// Look up the data in the database using the provided key.
+		item, err := tx.Get(key)
+		if err != nil {
+			// If the key is not found, return an error.
+			if errors.Is(err, badger.ErrKeyNotFound) {
+				return storage.ErrNotFound
+			}
+
+			return err
+		}
+
+		// Retrieve the data from the item.
+		val, err = item.ValueCopy(nil)
+
+		return err
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	return val, nil
+}
+
+// getAllByPrefix retrieves a slice of values for keys prefixed with
+// prefix. It stops as soon as the given context is done.
+func (t txn) getAllByPrefix(ctx context.Context, prefix []byte) ([][]byte, error) {
+	result := [][]byte{}
+
+	err := t.view(ctx, func(tx *badger.Txn) error {
+		// Create a new iterator to iterate over all prefixed keys in the database.
+		opts := badger.DefaultIteratorOptions
+		opts.Prefix = prefix
+
+		it := tx.NewIterator(opts)
+		defer it.Close()
+
+		// Iterate over all keys in the database.
+		for it.Seek(prefix); it.ValidForPrefix(prefix); it.Next() {
+			if err := t.db.ctxErr(ctx); err != nil {
+				return err
+			}
+
+			// Retrieve the data for the current item.
+			val, err := it.Item().ValueCopy(nil)
+			if err != nil {
+				return err
+			}
+
+			// Append the value to the result slice.
+			result = append(result, val)
+		}
+
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	return result, nil
+}
+
+// getPageByPrefix retrieves the values of the page of keys prefixed with
+// prefix selected by the given cursor whose keys are compared without the
+// prefix. It stops as soon as the given context is done.
+func (t txn) getPageByPrefix(ctx context.Context, prefix []byte, cursor storage.Cursor) ([][]byte, error) {
+	result := [][]byte{}
+
+	err := t.view(ctx, func(tx *badger.Txn) error {
+		opts := badger.DefaultIteratorOptions
+		opts.Prefix = prefix
+		opts.Reverse = cursor.Backward
+
+		// a reverse iterator seeks to the last key lower than or equal to
+		// the seek key so one past the last key with the prefix is needed
+		// when the page ends with the last one
+		seekKey := append(append([]byte{}, prefix...), cursor.StartKey...)
+		if cursor.Backward && cursor.StartKey == "" {
+			seekKey = append(seekKey, 0xff)
+		}
+
+		it := tx.NewIterator(opts)
+		defer it.Close()
+
+		for it.Seek(seekKey); it.ValidForPrefix(prefix) && len(result) < cursor.Limit; it.Next() {
+			if err := t.db.ctxErr(ctx); err != nil {
+				return err
+			}
+
+			// the page ends right before the start key
+			if cursor.Backward && bytes.Equal(it.Item().Key(), seekKey) {
+				continue
+			}
+
+			// Retrieve the data for the current item.
+			val, err := it.Item().ValueCopy(nil)
+			if err != nil {
+				return err
+			}
+
+			result = append(result, val)
+		}
+
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	if cursor.Backward {
+		for i, j := 0, len(result)-1; i < j; i, j = i+1, j-1 {
+			result[i], result[j] = result[j], result[i]
+		}
+	}
+
+	return result, nil
+}
+
+// create stores a value for the given key.
+func (t txn) create(ctx context.Context, key []byte, val []byte) error {
+	return t.updateTx(ctx, func(tx *badger.Txn) error {
+		// Set the data in the database using the provided key.
+		return tx.Set(key, val)
+	})
+}
+
+// update replaces the value of an existing key.
+func (t txn) update(ctx context.Context, key []byte, val []byte) error {
+	return t.updateTx(ctx, func(tx *badger.Txn) error {
+		// Make sure the key exists.
+		if _, err := tx.Get(key); err != nil {
+			if errors.Is(err, badger.ErrKeyNotFound) {
+				return storage.ErrNotFound
+			}
+
+			return err
+		}
+
+		// Set the data in the database using the provided key.
+		return tx.Set(key, val)
+	})
+}
+
+// delete removes data from the database by the given key.
+func (t txn) delete(ctx context.Context, key []byte) error {
+	return t.updateTx(ctx, func(tx *badger.Txn) error {
+		// Delete the data from the database using the provided key.
+		return tx.Delete(key)
+	})
+}
+
+// txRepositories hands out the repositories of a transaction.
+type txRepositories struct {
+	db txn
+}
+
+// GetUserRepositoryReader returns a repository for reading user data in the transaction.
+func (r txRepositories) GetUserRepositoryReader() storage.UserRepositoryReader {
+	return newUserRepositoryReader(r.db)
+}
+
+// GetUserRepositoryWriter returns a repository for writing user data in the transaction.
+func (r txRepositories) GetUserRepositoryWriter() storage.UserRepositoryWriter {
+	return newUserRepositoryWriter(r.db)
+}
+
+// GetRoleRepositoryReader returns a repository for reading chat role data in the transaction.
+func (r txRepositories) GetRoleRepositoryReader() storage.RoleRepositoryReader {
+	return newRoleRepositoryReader(r.db)
+}
+
+// GetRoleRepositoryWriter returns a repository for writing chat role data in the transaction.
+func (r txRepositories) GetRoleRepositoryWriter() storage.RoleRepositoryWriter {
+	return newRoleRepositoryWriter(r.db)
+}
+
+// GetInviteRepositoryReader returns a repository for reading invite data in the transaction.
+func (r txRepositories) GetInviteRepositoryReader() storage.InviteRepositoryReader {
+	return newInviteRepositoryReader(r.db)
+}
+
+// GetInviteRepositoryWriter returns a repository for writing invite data in the transaction.
+func (r txRepositories) GetInviteRepositoryWriter() storage.InviteRepositoryWriter {
+	return newInviteRepositoryWriter(r.db)
+}
+
+// GetSettingRepositoryReader returns a repository for reading settings in the transaction.
+func (r txRepositories) GetSettingRepositoryReader() storage.SettingRepositoryReader {
+	return newSettingRepositoryReader(r.db)
+}
+
+// GetSettingRepositoryWriter returns a repository for writing settings in the transaction.
+func (r txRepositories) GetSettingRepositoryWriter() storage.SettingRepositoryWriter {
+	return newSettingRepositoryWriter(r.db)
+}
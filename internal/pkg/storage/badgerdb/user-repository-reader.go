@@ -1,7 +1,11 @@
 package badgerdb
 
 import (
+	"context"
 	"encoding/json"
+	"errors"
+
+	badger "github.com/dgraph-io/badger/v3"
 
 	"github.com/psyb0t/telegram-logger/internal/pkg/storage"
 	"github.com/psyb0t/telegram-logger/internal/pkg/types"
@@ -10,25 +14,26 @@ import (
 // userRepositoryReader is a struct that implements the
 // storage.UserRepositoryReader interface using a badgerDB instance.
 type userRepositoryReader struct {
-	// db is a pointer to the underlying badgerDB instance.
-	db *badgerDB
+	// db runs the operations in the underlying badgerDB
+	// instance, in a transaction if it holds one.
+	db txn
 }
 
 // newUserRepositoryReader creates and returns
 // a new userRepositoryReader instance.
-func newUserRepositoryReader(db *badgerDB) storage.UserRepositoryReader {
+func newUserRepositoryReader(db txn) storage.UserRepositoryReader {
 	return userRepositoryReader{db: db}
 }
 
 // Get retrieves a user by ID.
-func (r userRepositoryReader) Get(id string) (types.User, error) {
+func (r userRepositoryReader) Get(ctx context.Context, id string) (types.User, error) {
 	user := types.User{}
 
 	if id == "" {
 		return user, storage.ErrEmptyID
 	}
 
-	val, err := r.db.get(getUserKey(id))
+	val, err := r.db.get(ctx, getUserKey(id))
 	if err != nil {
 		return user, err
 	}
@@ -42,11 +47,11 @@ func (r userRepositoryReader) Get(id string) (types.User, error) {
 }
 
 // GetAll retrieves all users from the database.
-func (r userRepositoryReader) GetAll() ([]types.User, error) {
+func (r userRepositoryReader) GetAll(ctx context.Context) ([]types.User, error) {
 	users := []types.User{}
 
 	// Get all user data from the db
-	vals, err := r.db.getAllByPrefix([]byte(prefixUserKey))
+	vals, err := r.db.getAllByPrefix(ctx, []byte(prefixUserKey))
 	if err != nil {
 		return nil, err
 	}
@@ -65,32 +70,148 @@ func (r userRepositoryReader) GetAll() ([]types.User, error) {
 	return users, nil
 }
 
+// GetPage retrieves the page of users, sorted by ID, selected by the given cursor.
+func (r userRepositoryReader) GetPage(ctx context.Context, cursor storage.Cursor) ([]types.User, error) {
+	if err := cursor.Validate(); err != nil {
+		return nil, err
+	}
+
+	vals, err := r.db.getPageByPrefix(ctx, []byte(prefixUserKey), cursor)
+	if err != nil {
+		return nil, err
+	}
+
+	users := make([]types.User, 0, len(vals))
+
+	for _, val := range vals {
+		// Unmarshal the user data into a user struct.
+		var user types.User
+		if err := json.Unmarshal(val, &user); err != nil {
+			return nil, err
+		}
+
+		users = append(users, user)
+	}
+
+	return users, nil
+}
+
+// Iterate calls fn for every user, sorted by ID, reading them a page at a
+// time so that fn doesn't run in a read-only transaction. It stops at
+// the first error returned by fn.
+func (r userRepositoryReader) Iterate(ctx context.Context, fn func(user types.User) error) error {
+	return storage.IterateUsers(ctx, r, fn)
+}
+
 // GetByTelegramChatID retrieves the first user found by its Telegram chat ID.
-func (r userRepositoryReader) GetByTelegramChatID(chatID int64) (types.User, error) {
-	var user types.User
+func (r userRepositoryReader) GetByTelegramChatID(ctx context.Context, chatID int64) (types.User, error) {
+	users, err := r.getAllByTelegramChatID(ctx, chatID, 1)
+	if err != nil {
+		return types.User{}, err
+	}
+
+	if len(users) == 0 {
+		return types.User{}, storage.ErrNotFound
+	}
+
+	return users[0], nil
+}
+
+// GetAllByTelegramChatID retrieves all users matching the given Telegram chat ID.
+func (r userRepositoryReader) GetAllByTelegramChatID(ctx context.Context, chatID int64) ([]types.User, error) {
+	return r.getAllByTelegramChatID(ctx, chatID, -1)
+}
+
+// getAllByTelegramChatID retrieves the first n users matching the given
+// Telegram chat ID through the chat index. If n < 0 all of them are returned.
+func (r userRepositoryReader) getAllByTelegramChatID(ctx context.Context, chatID int64, n int) ([]types.User, error) {
+	users := []types.User{}
 	if chatID == 0 {
-		return user, storage.ErrEmptyTelegramChatID
+		return users, storage.ErrEmptyTelegramChatID
 	}
 
-	// define filter function which unmarshals the value and checks if
-	// the Telegram chat ID matches the provided one
-	filterFn := func(key, val []byte) bool {
-		if err := json.Unmarshal(val, &user); err != nil {
-			return false
+	err := r.db.view(ctx, func(tx *badger.Txn) error {
+		userIDs, err := getChatUserIDs(ctx, tx, chatID)
+		if err != nil {
+			return err
+		}
+
+		for _, userID := range userIDs {
+			if len(users) == n {
+				break
+			}
+
+			user, err := getUser(tx, userID)
+			if err != nil {
+				// the index is written along with the users so this
+				// should not happen but there's nothing to return
+				if errors.Is(err, storage.ErrNotFound) {
+					continue
+				}
+
+				return err
+			}
+
+			users = append(users, user)
 		}
 
-		if user.TelegramChatID == chatID {
-			return true
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	return users, nil
+}
+
+// getUser retrieves a user by ID in the given transaction.
+func getUser(tx *badger.Txn, id string) (types.User, error) {
+	user := types.User{}
+
+	item, err := tx.Get(getUserKey(id))
+	if err != nil {
+		if errors.Is(err, badger.ErrKeyNotFound) {
+			return user, storage.ErrNotFound
 		}
 
-		return false
+		return user, err
 	}
 
-	// do get
-	_, err := r.db.getByPrefixAndFilterFunc([]byte(prefixUserKey), filterFn, 1)
+	val, err := item.ValueCopy(nil)
 	if err != nil {
 		return user, err
 	}
 
+	// Unmarshal the user data into the user struct.
+	if err := json.Unmarshal(val, &user); err != nil {
+		return user, err
+	}
+
 	return user, nil
 }
+
+// getChatUserIDs retrieves the IDs of the users of the given
+// Telegram chat from the chat index in the given transaction.
+// It stops as soon as the given context is done.
+func getChatUserIDs(ctx context.Context, tx *badger.Txn, chatID int64) ([]string, error) {
+	userIDs := []string{}
+	prefix := getChatIndexPrefix(chatID)
+
+	// only the keys are needed
+	opts := badger.DefaultIteratorOptions
+	opts.Prefix = prefix
+	opts.PrefetchValues = false
+
+	it := tx.NewIterator(opts)
+	defer it.Close()
+
+	for it.Seek(prefix); it.ValidForPrefix(prefix); it.Next() {
+		if err := ctx.Err(); err != nil {
+			return nil, err
+		}
+
+		userIDs = append(userIDs, getUserIDFromChatIndexKey(chatID, it.Item().Key()))
+	}
+
+	return userIDs, nil
+}
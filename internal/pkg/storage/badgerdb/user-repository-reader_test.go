@@ -0,0 +1,38 @@
+package badgerdb
+
+import (
+	"context"
+	"testing"
+
+	"github.com/psyb0t/telegram-logger/internal/pkg/types"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestUserRepositoryReader_GetAllByTelegramChatID(t *testing.T) {
+	ctx := context.Background()
+
+	db, err := New(ctx, Config{})
+	require.NoError(t, err)
+	require.NoError(t, db.Open(ctx, t.TempDir()))
+
+	defer db.Close()
+
+	users := []types.User{
+		{ID: "a", TelegramChatID: 1},
+		{ID: "b", TelegramChatID: 1},
+		{ID: "c", TelegramChatID: 2},
+	}
+
+	for _, user := range users {
+		require.NoError(t, db.GetUserRepositoryWriter().Create(ctx, user))
+	}
+
+	found, err := db.GetUserRepositoryReader().GetAllByTelegramChatID(ctx, 1)
+	require.NoError(t, err)
+	assert.ElementsMatch(t, users[:2], found)
+
+	found, err = db.GetUserRepositoryReader().GetAllByTelegramChatID(ctx, 3)
+	require.NoError(t, err)
+	assert.Empty(t, found)
+}
@@ -1,7 +1,11 @@
 package badgerdb
 
 import (
+	"context"
 	"encoding/json"
+	"errors"
+
+	badger "github.com/dgraph-io/badger/v3"
 
 	"github.com/psyb0t/telegram-logger/internal/pkg/storage"
 	"github.com/psyb0t/telegram-logger/internal/pkg/types"
@@ -10,78 +14,150 @@ import (
 // userRepositoryWriter is a struct that implements the
 // storage.UserRepositoryWriter interface using a badgerDB instance.
 type userRepositoryWriter struct {
-	// db is a pointer to the underlying badgerDB instance.
-	db *badgerDB
+	// db runs the operations in the underlying badgerDB
+	// instance, in a transaction if it holds one.
+	db txn
 }
 
 // newUserRepositoryWriter creates and returns
 // a new userRepositoryWriter instance.
-func newUserRepositoryWriter(db *badgerDB) storage.UserRepositoryWriter {
+func newUserRepositoryWriter(db txn) storage.UserRepositoryWriter {
 	return userRepositoryWriter{db: db}
 }
 
 // Create stores a new user in the database.
 //
 // user is the user to be stored. It must have a non-empty ID field.
-func (r userRepositoryWriter) Create(user types.User) error {
+func (r userRepositoryWriter) Create(ctx context.Context, user types.User) error {
 	if user.ID == "" {
 		return storage.ErrEmptyID
 	}
 
-	// Convert the user struct to a byte slice.
-	val, err := json.Marshal(user)
-	if err != nil {
-		return err
+	return r.db.updateTx(ctx, func(tx *badger.Txn) error {
+		return setUser(tx, user, false)
+	})
+}
+
+// Update replaces an existing user in the database.
+//
+// user is the user to be stored. It must have a non-empty ID field.
+func (r userRepositoryWriter) Update(ctx context.Context, user types.User) error {
+	if user.ID == "" {
+		return storage.ErrEmptyID
 	}
 
-	// Create the user
-	return r.db.create(getUserKey(user.ID), val)
+	return r.db.updateTx(ctx, func(tx *badger.Txn) error {
+		return setUser(tx, user, true)
+	})
 }
 
 // Delete removes a user from the database by ID.
-func (r userRepositoryWriter) Delete(id string) error {
+func (r userRepositoryWriter) Delete(ctx context.Context, id string) error {
 	if id == "" {
 		return storage.ErrEmptyID
 	}
 
-	// Delete the user data from the database using the prefixed provided ID as the key.
-	return r.db.delete(getUserKey(id))
+	return r.db.updateTx(ctx, func(tx *badger.Txn) error {
+		user, err := getUser(tx, id)
+		if err != nil {
+			if errors.Is(err, storage.ErrNotFound) {
+				return nil
+			}
+
+			return err
+		}
+
+		return deleteUser(tx, user)
+	})
 }
 
 // DeleteAllByTelegramChatID removes all users from the database
 // matching the given Telegram chat ID.
-func (r userRepositoryWriter) DeleteAllByTelegramChatID(chatID int64) error {
+func (r userRepositoryWriter) DeleteAllByTelegramChatID(ctx context.Context, chatID int64) error {
 	if chatID == 0 {
 		return storage.ErrEmptyTelegramChatID
 	}
 
-	// define filter function which unmarshals the value and checks if
-	// the Telegram chat ID matches the provided one
-	filterFn := func(key, val []byte) bool {
-		var user types.User
-		if err := json.Unmarshal(val, &user); err != nil {
-			return false
+	return r.db.updateTx(ctx, func(tx *badger.Txn) error {
+		userIDs, err := getChatUserIDs(ctx, tx, chatID)
+		if err != nil {
+			return err
 		}
 
-		if user.TelegramChatID == chatID {
-			return true
+		for _, userID := range userIDs {
+			if err := deleteUser(tx, types.User{ID: userID, TelegramChatID: chatID}); err != nil {
+				return err
+			}
 		}
 
-		return false
+		return nil
+	})
+}
+
+// UpdateTelegramChatID replaces the Telegram chat ID of all of the users
+// matching oldChatID with newChatID in a single transaction.
+func (r userRepositoryWriter) UpdateTelegramChatID(ctx context.Context, oldChatID, newChatID int64) error {
+	if oldChatID == 0 || newChatID == 0 {
+		return storage.ErrEmptyTelegramChatID
 	}
 
-	// find users to delete
-	results, err := r.db.getByPrefixAndFilterFunc([]byte(prefixUserKey), filterFn, -1)
+	return r.db.updateTx(ctx, func(tx *badger.Txn) error {
+		userIDs, err := getChatUserIDs(ctx, tx, oldChatID)
+		if err != nil {
+			return err
+		}
+
+		for _, userID := range userIDs {
+			user, err := getUser(tx, userID)
+			if err != nil {
+				return err
+			}
+
+			user.TelegramChatID = newChatID
+
+			if err := setUser(tx, user, true); err != nil {
+				return err
+			}
+		}
+
+		return nil
+	})
+}
+
+// setUser stores the given user in the given transaction and moves its
+// chat index key if its Telegram chat ID changed. If mustExist is true
+// and the user doesn't exist, storage.ErrNotFound is returned.
+func setUser(tx *badger.Txn, user types.User, mustExist bool) error {
+	oldUser, err := getUser(tx, user.ID)
+	if err != nil {
+		if !errors.Is(err, storage.ErrNotFound) || mustExist {
+			return err
+		}
+	} else if oldUser.TelegramChatID != user.TelegramChatID {
+		if err := tx.Delete(getChatIndexKey(oldUser.TelegramChatID, oldUser.ID)); err != nil {
+			return err
+		}
+	}
+
+	// Convert the user struct to a byte slice.
+	val, err := json.Marshal(user)
 	if err != nil {
 		return err
 	}
 
-	for _, kv := range results {
-		key := kv[0]
-		if err := r.db.delete(key); err != nil {
-			return err
-		}
+	if err := tx.Set(getUserKey(user.ID), val); err != nil {
+		return err
+	}
+
+	return tx.Set(getChatIndexKey(user.TelegramChatID, user.ID), nil)
+}
+
+// deleteUser removes the given user and its chat
+// index key from the database in the given transaction.
+func deleteUser(tx *badger.Txn, user types.User) error {
+	if err := tx.Delete(getUserKey(user.ID)); err != nil {
+		return err
 	}
 
-	return nil
+	return tx.Delete(getChatIndexKey(user.TelegramChatID, user.ID))
 }
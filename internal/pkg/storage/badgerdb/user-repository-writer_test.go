@@ -0,0 +1,39 @@
+package badgerdb
+
+import (
+	"context"
+	"testing"
+
+	"github.com/psyb0t/telegram-logger/internal/pkg/types"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestUserRepositoryWriter_UpdateTelegramChatID(t *testing.T) {
+	ctx := context.Background()
+
+	db, err := New(ctx, Config{})
+	require.NoError(t, err)
+	require.NoError(t, db.Open(ctx, t.TempDir()))
+
+	defer db.Close()
+
+	users := []types.User{
+		{ID: "a", TelegramChatID: 1},
+		{ID: "b", TelegramChatID: 1},
+		{ID: "c", TelegramChatID: 2},
+	}
+
+	for _, user := range users {
+		require.NoError(t, db.GetUserRepositoryWriter().Create(ctx, user))
+	}
+
+	require.NoError(t, db.GetUserRepositoryWriter().UpdateTelegramChatID(ctx, 1, 3))
+
+	expected := map[string]int64{"a": 3, "b": 3, "c": 2}
+	for id, chatID := range expected {
+		user, err := db.GetUserRepositoryReader().Get(ctx, id)
+		require.NoError(t, err)
+		assert.Equal(t, chatID, user.TelegramChatID)
+	}
+}
@@ -1,5 +1,40 @@
 package badgerdb
 
+import (
+	"bytes"
+	"strconv"
+)
+
 func getUserKey(userID string) []byte {
 	return []byte(prefixUserKey + userID)
 }
+
+func getRoleKey(chatID int64) []byte {
+	return []byte(prefixRoleKey + strconv.FormatInt(chatID, 10))
+}
+
+func getInviteKey(inviteID string) []byte {
+	return []byte(prefixInviteKey + inviteID)
+}
+
+func getSettingKey(key string) []byte {
+	return []byte(prefixSettingKey + key)
+}
+
+func getChatIndexPrefix(chatID int64) []byte {
+	return []byte(prefixChatIndexKey + strconv.FormatInt(chatID, 10) + "-")
+}
+
+func getChatIndexKey(chatID int64, userID string) []byte {
+	return append(getChatIndexPrefix(chatID), userID...)
+}
+
+// getUserIDFromChatIndexKey returns the user ID of the
+// given chat index key of the given chat.
+func getUserIDFromChatIndexKey(chatID int64, key []byte) string {
+	return string(bytes.TrimPrefix(key, getChatIndexPrefix(chatID)))
+}
+
+func getMetaKey(key string) []byte {
+	return []byte(prefixMetaKey + key)
+}
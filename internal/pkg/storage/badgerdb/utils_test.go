@@ -32,3 +32,130 @@ func TestGetUserKey(t *testing.T) {
 		})
 	}
 }
+
+func TestGetRoleKey(t *testing.T) {
+	testCases := []struct {
+		name     string
+		chatID   int64
+		expected []byte
+	}{
+		{
+			name:     "chat ID 12345",
+			chatID:   12345,
+			expected: []byte("role-12345"),
+		},
+		{
+			name:     "chat ID -10012345",
+			chatID:   -10012345,
+			expected: []byte("role--10012345"),
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			actual := getRoleKey(tc.chatID)
+			if !bytes.Equal(actual, tc.expected) {
+				t.Errorf("got %v, want %v", actual, tc.expected)
+			}
+		})
+	}
+}
+
+func TestGetInviteKey(t *testing.T) {
+	testCases := []struct {
+		name     string
+		inviteID string
+		expected []byte
+	}{
+		{
+			name:     "invite ID 12345",
+			inviteID: "12345",
+			expected: []byte("invite-12345"),
+		},
+		{
+			name:     "invite ID abcdef",
+			inviteID: "abcdef",
+			expected: []byte("invite-abcdef"),
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			actual := getInviteKey(tc.inviteID)
+			if !bytes.Equal(actual, tc.expected) {
+				t.Errorf("got %v, want %v", actual, tc.expected)
+			}
+		})
+	}
+}
+
+func TestGetSettingKey(t *testing.T) {
+	testCases := []struct {
+		name     string
+		key      string
+		expected []byte
+	}{
+		{
+			name:     "key abc",
+			key:      "abc",
+			expected: []byte("setting-abc"),
+		},
+		{
+			name:     "key telegramBotUpdateOffset",
+			key:      "telegramBotUpdateOffset",
+			expected: []byte("setting-telegramBotUpdateOffset"),
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			actual := getSettingKey(tc.key)
+			if !bytes.Equal(actual, tc.expected) {
+				t.Errorf("got %v, want %v", actual, tc.expected)
+			}
+		})
+	}
+}
+
+func TestGetChatIndexKey(t *testing.T) {
+	testCases := []struct {
+		name     string
+		chatID   int64
+		userID   string
+		expected []byte
+	}{
+		{
+			name:     "chat ID 12345",
+			chatID:   12345,
+			userID:   "abcdef",
+			expected: []byte("chat-12345-abcdef"),
+		},
+		{
+			name:     "chat ID -10012345",
+			chatID:   -10012345,
+			userID:   "abcdef",
+			expected: []byte("chat--10012345-abcdef"),
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			actual := getChatIndexKey(tc.chatID, tc.userID)
+			if !bytes.Equal(actual, tc.expected) {
+				t.Errorf("got %v, want %v", actual, tc.expected)
+			}
+
+			if userID := getUserIDFromChatIndexKey(tc.chatID, actual); userID != tc.userID {
+				t.Errorf("got %v, want %v", userID, tc.userID)
+			}
+		})
+	}
+}
+
+func TestGetChatIndexPrefix(t *testing.T) {
+	// the prefix of a chat must not match the keys of
+	// another chat whose ID starts with the same digits
+	if bytes.HasPrefix(getChatIndexKey(123, "abc"), getChatIndexPrefix(12)) {
+		t.Errorf("chat 12 prefix matches chat 123 key")
+	}
+}
@@ -0,0 +1,106 @@
+package storage
+
+import (
+	"context"
+	"sort"
+
+	"github.com/psyb0t/telegram-logger/internal/pkg/types"
+)
+
+// IterateBatchSize is the number of users IterateUsers reads at a time.
+const IterateBatchSize = 100
+
+// Cursor selects a page of a listing sorted by key.
+type Cursor struct {
+	// StartKey is where the page starts. The page starts with the first
+	// item whose key is greater than or equal to StartKey, or with the
+	// first item if StartKey is empty. StartKey doesn't have to be
+	// the key of an existing item.
+	StartKey string
+
+	// Limit is the maximum number of items in the page. It must be positive.
+	Limit int
+
+	// Backward makes the page end right before StartKey instead so
+	// that the previous page can be listed. The page then holds the
+	// last items whose keys are lower than StartKey, or the last items
+	// if StartKey is empty. The items are sorted in ascending order
+	// either way.
+	Backward bool
+}
+
+// Validate checks if the cursor can be used for listing a page.
+func (c Cursor) Validate() error {
+	if c.Limit <= 0 {
+		return ErrInvalidLimit
+	}
+
+	return nil
+}
+
+// Page returns the keys of the page selected by the
+// cursor out of the given keys sorted in ascending order.
+func (c Cursor) Page(keys []string) []string {
+	start := 0
+	if c.StartKey != "" {
+		start = sort.SearchStrings(keys, c.StartKey)
+	}
+
+	if !c.Backward {
+		end := start + c.Limit
+		if end > len(keys) {
+			end = len(keys)
+		}
+
+		return keys[start:end]
+	}
+
+	// the page ends right before StartKey or with the last key
+	end := start
+	if c.StartKey == "" {
+		end = len(keys)
+	}
+
+	start = end - c.Limit
+	if start < 0 {
+		start = 0
+	}
+
+	return keys[start:end]
+}
+
+// IterateUsers calls fn for every user returned by r, sorted by ID,
+// reading them IterateBatchSize at a time. It stops at the first
+// error returned by fn. The backends implement
+// UserRepositoryReader.Iterate with it so that fn can use the
+// storage while iterating.
+func IterateUsers(ctx context.Context, r UserRepositoryReader, fn func(user types.User) error) error {
+	cursor := Cursor{Limit: IterateBatchSize}
+
+	for {
+		users, err := r.GetPage(ctx, cursor)
+		if err != nil {
+			return err
+		}
+
+		n := len(users)
+
+		// the page starts with the last user of the previous one
+		// unless it was deleted in the meantime
+		if n > 0 && cursor.StartKey != "" && users[0].ID == cursor.StartKey {
+			users = users[1:]
+		}
+
+		for _, user := range users {
+			if err := fn(user); err != nil {
+				return err
+			}
+		}
+
+		if n < cursor.Limit || len(users) == 0 {
+			return nil
+		}
+
+		cursor.StartKey = users[len(users)-1].ID
+	}
+}
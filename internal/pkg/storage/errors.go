@@ -9,6 +9,34 @@ var (
 	// ErrEmptyTelegramChatID is returned when an Telegram chat ID is empty.
 	ErrEmptyTelegramChatID = errors.New("empty Telegram chat ID")
 
-	// ErrNotFound is returned when a user is not found.
+	// ErrNotFound is returned when a user or another entity is not found.
 	ErrNotFound = errors.New("not found")
+
+	// ErrEmptyRole is returned when a role is empty.
+	ErrEmptyRole = errors.New("empty role")
+
+	// ErrEmptyKey is returned when a setting key is empty.
+	ErrEmptyKey = errors.New("empty key")
+
+	// ErrInvalidLimit is returned when the limit of a page isn't positive.
+	ErrInvalidLimit = errors.New("invalid limit")
+
+	// ErrUnsupportedExportFormat is returned when data is
+	// exported or imported in an unknown format.
+	ErrUnsupportedExportFormat = errors.New("unsupported export format")
+
+	// ErrUnsupportedExportVersion is returned when an export
+	// was made by an incompatible version of the app.
+	ErrUnsupportedExportVersion = errors.New("unsupported export version")
+
+	// ErrInvalidExport is returned when an export can't be read.
+	ErrInvalidExport = errors.New("invalid export")
+
+	// ErrSchemaVersionTooNew is returned when the stored data was
+	// migrated by a newer version of the app.
+	ErrSchemaVersionTooNew = errors.New("schema version is newer than the supported one")
+
+	// ErrInvalidMigrationVersion is returned when the versions of
+	// the migrations don't start from 1 and grow by 1.
+	ErrInvalidMigrationVersion = errors.New("invalid migration version")
 )
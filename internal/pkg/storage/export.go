@@ -0,0 +1,290 @@
+package storage
+
+import (
+	"bufio"
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+
+	"github.com/psyb0t/telegram-logger/internal/pkg/types"
+)
+
+// ExportFormat is a format the data of a storage can be exported in.
+type ExportFormat string
+
+// ExportFormatJSON is a JSON object holding the users,
+// the chat roles, the invites and the settings.
+const ExportFormatJSON ExportFormat = "json"
+
+// exportVersion is the version of the layout of the
+// exports. It's bumped on incompatible changes.
+const exportVersion = 1
+
+const (
+	exportFieldVersion  = "version"
+	exportFieldUsers    = "users"
+	exportFieldRoles    = "roles"
+	exportFieldInvites  = "invites"
+	exportFieldSettings = "settings"
+)
+
+// Export writes all of the data read through db to w in the given format.
+// The users are streamed so they're never all in memory. The exports
+// don't depend on the storage they come from so they can be used for
+// moving the data to another kind of storage with Import.
+func Export(ctx context.Context, db Repositories, w io.Writer, format ExportFormat) error {
+	if format != ExportFormatJSON {
+		return fmt.Errorf("%w: %s", ErrUnsupportedExportFormat, format)
+	}
+
+	bw := bufio.NewWriter(w)
+
+	if err := exportJSON(ctx, db, bw); err != nil {
+		return err
+	}
+
+	return bw.Flush()
+}
+
+// exportJSON writes the data read through db to w as a single JSON
+// object whose version comes first so that Import can check it
+// before writing anything.
+func exportJSON(ctx context.Context, db Repositories, w io.Writer) error {
+	if _, err := fmt.Fprintf(w, `{"%s":%d,"%s":[`, exportFieldVersion, exportVersion, exportFieldUsers); err != nil {
+		return err
+	}
+
+	separator := "\n"
+
+	if err := db.GetUserRepositoryReader().Iterate(ctx, func(user types.User) error {
+		userJSON, err := json.Marshal(user)
+		if err != nil {
+			return err
+		}
+
+		if _, err := fmt.Fprintf(w, "%s%s", separator, userJSON); err != nil {
+			return err
+		}
+
+		separator = ",\n"
+
+		return nil
+	}); err != nil {
+		return err
+	}
+
+	if _, err := io.WriteString(w, "\n]"); err != nil {
+		return err
+	}
+
+	roles, err := db.GetRoleRepositoryReader().GetAll(ctx)
+	if err != nil {
+		return err
+	}
+
+	invites, err := db.GetInviteRepositoryReader().GetAll(ctx)
+	if err != nil {
+		return err
+	}
+
+	settings, err := db.GetSettingRepositoryReader().GetAll(ctx)
+	if err != nil {
+		return err
+	}
+
+	fields := []struct {
+		name  string
+		value interface{}
+	}{
+		{name: exportFieldRoles, value: roles},
+		{name: exportFieldInvites, value: invites},
+		{name: exportFieldSettings, value: settings},
+	}
+
+	for _, field := range fields {
+		valueJSON, err := json.Marshal(field.value)
+		if err != nil {
+			return err
+		}
+
+		if _, err := fmt.Fprintf(w, ",\n\"%s\":%s", field.name, valueJSON); err != nil {
+			return err
+		}
+	}
+
+	_, err = io.WriteString(w, "}\n")
+
+	return err
+}
+
+// Import writes the data exported by Export in the given format from r
+// through db. The users are streamed so they're never all in memory.
+// The writes aren't made in a single transaction since it could get
+// too big for some of the storages so db should be empty and, if
+// Import fails, emptied before trying again.
+func Import(ctx context.Context, db Repositories, r io.Reader, format ExportFormat) error {
+	if format != ExportFormatJSON {
+		return fmt.Errorf("%w: %s", ErrUnsupportedExportFormat, format)
+	}
+
+	dec := json.NewDecoder(r)
+
+	if err := expectJSONDelim(dec, '{'); err != nil {
+		return err
+	}
+
+	versionChecked := false
+
+	for dec.More() {
+		field, err := readJSONObjectKey(dec)
+		if err != nil {
+			return err
+		}
+
+		if field != exportFieldVersion && !versionChecked {
+			return fmt.Errorf("%w: the version must come first", ErrInvalidExport)
+		}
+
+		switch field {
+		case exportFieldVersion:
+			if err := checkExportVersion(dec); err != nil {
+				return err
+			}
+
+			versionChecked = true
+		case exportFieldUsers:
+			err = importUsers(ctx, db.GetUserRepositoryWriter(), dec)
+		case exportFieldRoles:
+			err = importRoles(ctx, db.GetRoleRepositoryWriter(), dec)
+		case exportFieldInvites:
+			err = importInvites(ctx, db.GetInviteRepositoryWriter(), dec)
+		case exportFieldSettings:
+			err = importSettings(ctx, db.GetSettingRepositoryWriter(), dec)
+		default:
+			// skip the fields added by newer versions that
+			// didn't need the version to be bumped
+			var value json.RawMessage
+			err = dec.Decode(&value)
+		}
+
+		if err != nil {
+			return err
+		}
+	}
+
+	return expectJSONDelim(dec, '}')
+}
+
+// checkExportVersion reads the version of the export and
+// makes sure that it's the one Import can read.
+func checkExportVersion(dec *json.Decoder) error {
+	var version int
+	if err := dec.Decode(&version); err != nil {
+		return fmt.Errorf("%w: %s", ErrInvalidExport, err.Error())
+	}
+
+	if version != exportVersion {
+		return fmt.Errorf("%w: %d", ErrUnsupportedExportVersion, version)
+	}
+
+	return nil
+}
+
+// importUsers creates the users of the array read from dec one at a time.
+func importUsers(ctx context.Context, writer UserRepositoryWriter, dec *json.Decoder) error {
+	if err := expectJSONDelim(dec, '['); err != nil {
+		return err
+	}
+
+	for dec.More() {
+		var user types.User
+		if err := dec.Decode(&user); err != nil {
+			return fmt.Errorf("%w: %s", ErrInvalidExport, err.Error())
+		}
+
+		if err := writer.Create(ctx, user); err != nil {
+			return err
+		}
+	}
+
+	return expectJSONDelim(dec, ']')
+}
+
+// importRoles sets the chat roles of the array read from dec.
+func importRoles(ctx context.Context, writer RoleRepositoryWriter, dec *json.Decoder) error {
+	roles := []types.ChatRole{}
+	if err := dec.Decode(&roles); err != nil {
+		return fmt.Errorf("%w: %s", ErrInvalidExport, err.Error())
+	}
+
+	for _, role := range roles {
+		if err := writer.Set(ctx, role); err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+// importInvites creates the invites of the array read from dec.
+func importInvites(ctx context.Context, writer InviteRepositoryWriter, dec *json.Decoder) error {
+	invites := []types.Invite{}
+	if err := dec.Decode(&invites); err != nil {
+		return fmt.Errorf("%w: %s", ErrInvalidExport, err.Error())
+	}
+
+	for _, invite := range invites {
+		if err := writer.Create(ctx, invite); err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+// importSettings sets the settings of the object read from dec.
+func importSettings(ctx context.Context, writer SettingRepositoryWriter, dec *json.Decoder) error {
+	settings := map[string]string{}
+	if err := dec.Decode(&settings); err != nil {
+		return fmt.Errorf("%w: %s", ErrInvalidExport, err.Error())
+	}
+
+	for key, value := range settings {
+		if err := writer.Set(ctx, key, value); err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+// readJSONObjectKey reads the next key of the object read from dec.
+func readJSONObjectKey(dec *json.Decoder) (string, error) {
+	token, err := dec.Token()
+	if err != nil {
+		return "", fmt.Errorf("%w: %s", ErrInvalidExport, err.Error())
+	}
+
+	key, ok := token.(string)
+	if !ok {
+		return "", fmt.Errorf("%w: unexpected %v", ErrInvalidExport, token)
+	}
+
+	return key, nil
+}
+
+// expectJSONDelim reads the next token from dec and
+// makes sure that it's the given delimiter.
+func expectJSONDelim(dec *json.Decoder, delim json.Delim) error {
+	token, err := dec.Token()
+	if err != nil {
+		return fmt.Errorf("%w: %s", ErrInvalidExport, err.Error())
+	}
+
+	if token != delim {
+		return fmt.Errorf("%w: expected %s but got %v", ErrInvalidExport, delim, token)
+	}
+
+	return nil
+}
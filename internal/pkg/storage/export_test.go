@@ -0,0 +1,145 @@
+package storage_test
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/psyb0t/telegram-logger/internal/pkg/storage"
+	"github.com/psyb0t/telegram-logger/internal/pkg/storage/memory"
+	"github.com/psyb0t/telegram-logger/internal/pkg/types"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func newMemoryStorage(t *testing.T) storage.Storage {
+	t.Helper()
+
+	db, err := memory.New(context.Background())
+	require.NoError(t, err)
+	require.NoError(t, db.Open(context.Background(), ""))
+
+	t.Cleanup(func() {
+		assert.NoError(t, db.Close())
+	})
+
+	return db
+}
+
+func TestExportImport(t *testing.T) {
+	ctx := context.Background()
+	source := newMemoryStorage(t)
+
+	expiresAt := time.Date(2030, 1, 2, 3, 4, 5, 0, time.UTC)
+	users := []types.User{}
+
+	// more than a page of users
+	for i := 0; i < storage.IterateBatchSize+1; i++ {
+		users = append(users, types.User{
+			ID:             strings.Repeat("a", i+1),
+			TelegramChatID: int64(i%3 + 1),
+			ExpiresAt:      &expiresAt,
+			Scopes:         types.Scopes{Levels: []string{"err"}},
+		})
+	}
+
+	for _, user := range users {
+		require.NoError(t, source.GetUserRepositoryWriter().Create(ctx, user))
+	}
+
+	role := types.ChatRole{TelegramChatID: 1, Role: types.RoleAdmin}
+	require.NoError(t, source.GetRoleRepositoryWriter().Set(ctx, role))
+
+	invite := types.Invite{ID: "i", CreatedBy: 1, CreatedAt: expiresAt, MaxUses: 1}
+	require.NoError(t, source.GetInviteRepositoryWriter().Create(ctx, invite))
+
+	require.NoError(t, source.GetSettingRepositoryWriter().Set(ctx, "offset", "42"))
+
+	export := &bytes.Buffer{}
+	require.NoError(t, storage.Export(ctx, source, export, storage.ExportFormatJSON))
+	assert.True(t, json.Valid(export.Bytes()))
+
+	target := newMemoryStorage(t)
+	require.NoError(t, storage.Import(ctx, target, export, storage.ExportFormatJSON))
+
+	expectedUsers, err := source.GetUserRepositoryReader().GetAll(ctx)
+	require.NoError(t, err)
+
+	actualUsers, err := target.GetUserRepositoryReader().GetAll(ctx)
+	require.NoError(t, err)
+	assert.Equal(t, expectedUsers, actualUsers)
+
+	roles, err := target.GetRoleRepositoryReader().GetAll(ctx)
+	require.NoError(t, err)
+	assert.Equal(t, []types.ChatRole{role}, roles)
+
+	invites, err := target.GetInviteRepositoryReader().GetAll(ctx)
+	require.NoError(t, err)
+	assert.Equal(t, []types.Invite{invite}, invites)
+
+	settings, err := target.GetSettingRepositoryReader().GetAll(ctx)
+	require.NoError(t, err)
+	assert.Equal(t, map[string]string{"offset": "42"}, settings)
+}
+
+func TestExport_emptyStorage(t *testing.T) {
+	export := &bytes.Buffer{}
+	require.NoError(t, storage.Export(context.Background(), newMemoryStorage(t), export, storage.ExportFormatJSON))
+	assert.JSONEq(t, `{"version":1,"users":[],"roles":[],"invites":[],"settings":{}}`, export.String())
+}
+
+func TestImport_errors(t *testing.T) {
+	tests := []struct {
+		name     string
+		format   storage.ExportFormat
+		export   string
+		expected error
+	}{
+		{
+			name:     "unsupported format",
+			format:   "csv",
+			export:   `{"version":1}`,
+			expected: storage.ErrUnsupportedExportFormat,
+		},
+		{
+			name:     "unsupported version",
+			format:   storage.ExportFormatJSON,
+			export:   `{"version":2,"users":[]}`,
+			expected: storage.ErrUnsupportedExportVersion,
+		},
+		{
+			name:     "version not first",
+			format:   storage.ExportFormatJSON,
+			export:   `{"users":[],"version":1}`,
+			expected: storage.ErrInvalidExport,
+		},
+		{
+			name:     "not an object",
+			format:   storage.ExportFormatJSON,
+			export:   `[]`,
+			expected: storage.ErrInvalidExport,
+		},
+		{
+			name:     "invalid user",
+			format:   storage.ExportFormatJSON,
+			export:   `{"version":1,"users":[{"id":1}]}`,
+			expected: storage.ErrInvalidExport,
+		},
+		{
+			name:     "truncated",
+			format:   storage.ExportFormatJSON,
+			export:   `{"version":1,"users":[{"id":"a","telegramChatID":1}`,
+			expected: storage.ErrInvalidExport,
+		},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			err := storage.Import(context.Background(), newMemoryStorage(t), strings.NewReader(test.export), test.format)
+			assert.ErrorIs(t, err, test.expected)
+		})
+	}
+}
@@ -0,0 +1,42 @@
+package storage
+
+import (
+	"context"
+
+	"github.com/psyb0t/telegram-logger/internal/pkg/types"
+	"github.com/stretchr/testify/mock"
+)
+
+// InviteRepositoryReaderMock is a mock implementation of InviteRepositoryReader.
+type InviteRepositoryReaderMock struct {
+	mock.Mock
+}
+
+// Get retrieves an invite by ID.
+func (r *InviteRepositoryReaderMock) Get(_ context.Context, id string) (types.Invite, error) {
+	args := r.Called(id)
+	return args.Get(0).(types.Invite), args.Error(1)
+}
+
+// GetAll retrieves all invites from the database.
+func (r *InviteRepositoryReaderMock) GetAll(_ context.Context) ([]types.Invite, error) {
+	args := r.Called()
+	return args.Get(0).([]types.Invite), args.Error(1)
+}
+
+// InviteRepositoryWriterMock is a mock implementation of InviteRepositoryWriter.
+type InviteRepositoryWriterMock struct {
+	mock.Mock
+}
+
+// Create stores a new invite in the database.
+func (r *InviteRepositoryWriterMock) Create(_ context.Context, invite types.Invite) error {
+	args := r.Called(invite)
+	return args.Error(0)
+}
+
+// Update replaces an existing invite in the database.
+func (r *InviteRepositoryWriterMock) Update(_ context.Context, invite types.Invite) error {
+	args := r.Called(invite)
+	return args.Error(0)
+}
@@ -0,0 +1,28 @@
+package storage
+
+import (
+	"context"
+
+	"github.com/psyb0t/telegram-logger/internal/pkg/types"
+)
+
+// InviteRepositoryReader is an interface for reading
+// invite data stored in the database.
+type InviteRepositoryReader interface {
+	// Get retrieves an invite by ID.
+	Get(ctx context.Context, id string) (types.Invite, error)
+
+	// GetAll retrieves all invites from the database.
+	GetAll(ctx context.Context) ([]types.Invite, error)
+}
+
+// InviteRepositoryWriter is an interface for writing
+// invite data stored in the database. Invites are never
+// deleted so that they can be audited.
+type InviteRepositoryWriter interface {
+	// Create stores a new invite in the database.
+	Create(ctx context.Context, invite types.Invite) error
+
+	// Update replaces an existing invite in the database.
+	Update(ctx context.Context, invite types.Invite) error
+}
@@ -0,0 +1,65 @@
+package memory
+
+import (
+	"context"
+	"encoding/json"
+
+	"github.com/psyb0t/telegram-logger/internal/pkg/storage"
+	"github.com/psyb0t/telegram-logger/internal/pkg/types"
+)
+
+// inviteRepositoryReader is a struct that implements the
+// storage.InviteRepositoryReader interface using a memoryDB instance.
+type inviteRepositoryReader struct {
+	// db accesses the data of the underlying memoryDB
+	// instance or of a transaction.
+	db txn
+}
+
+// newInviteRepositoryReader creates and returns
+// a new inviteRepositoryReader instance.
+func newInviteRepositoryReader(db txn) storage.InviteRepositoryReader {
+	return inviteRepositoryReader{db: db}
+}
+
+// Get retrieves an invite by ID.
+func (r inviteRepositoryReader) Get(ctx context.Context, id string) (types.Invite, error) {
+	invite := types.Invite{}
+
+	if id == "" {
+		return invite, storage.ErrEmptyID
+	}
+
+	err := r.db.read(ctx, func(data *snapshot) error {
+		val, ok := data.Invites[id]
+		if !ok {
+			return storage.ErrNotFound
+		}
+
+		// Unmarshal the invite data into the invite struct.
+		return json.Unmarshal(val, &invite)
+	})
+
+	return invite, err
+}
+
+// GetAll retrieves all invites from the database.
+func (r inviteRepositoryReader) GetAll(ctx context.Context) ([]types.Invite, error) {
+	invites := []types.Invite{}
+
+	err := r.db.read(ctx, func(data *snapshot) error {
+		for _, val := range sortedValues(data.Invites) {
+			// Unmarshal the invite data into an invite struct.
+			var invite types.Invite
+			if err := json.Unmarshal(val, &invite); err != nil {
+				return err
+			}
+
+			invites = append(invites, invite)
+		}
+
+		return nil
+	})
+
+	return invites, err
+}
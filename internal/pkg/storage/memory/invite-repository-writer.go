@@ -0,0 +1,69 @@
+package memory
+
+import (
+	"context"
+	"encoding/json"
+
+	"github.com/psyb0t/telegram-logger/internal/pkg/storage"
+	"github.com/psyb0t/telegram-logger/internal/pkg/types"
+)
+
+// inviteRepositoryWriter is a struct that implements the
+// storage.InviteRepositoryWriter interface using a memoryDB instance.
+type inviteRepositoryWriter struct {
+	// db accesses the data of the underlying memoryDB
+	// instance or of a transaction.
+	db txn
+}
+
+// newInviteRepositoryWriter creates and returns
+// a new inviteRepositoryWriter instance.
+func newInviteRepositoryWriter(db txn) storage.InviteRepositoryWriter {
+	return inviteRepositoryWriter{db: db}
+}
+
+// Create stores a new invite in the database.
+//
+// invite is the invite to be stored. It must have a non-empty ID field.
+func (r inviteRepositoryWriter) Create(ctx context.Context, invite types.Invite) error {
+	if invite.ID == "" {
+		return storage.ErrEmptyID
+	}
+
+	// Convert the invite struct to a byte slice.
+	val, err := json.Marshal(invite)
+	if err != nil {
+		return err
+	}
+
+	return r.db.write(ctx, func(data *snapshot) error {
+		data.Invites[invite.ID] = val
+
+		return nil
+	})
+}
+
+// Update replaces an existing invite in the database.
+//
+// invite is the invite to be stored. It must have a non-empty ID field.
+func (r inviteRepositoryWriter) Update(ctx context.Context, invite types.Invite) error {
+	if invite.ID == "" {
+		return storage.ErrEmptyID
+	}
+
+	// Convert the invite struct to a byte slice.
+	val, err := json.Marshal(invite)
+	if err != nil {
+		return err
+	}
+
+	return r.db.write(ctx, func(data *snapshot) error {
+		if _, ok := data.Invites[invite.ID]; !ok {
+			return storage.ErrNotFound
+		}
+
+		data.Invites[invite.ID] = val
+
+		return nil
+	})
+}
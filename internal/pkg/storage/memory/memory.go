@@ -0,0 +1,286 @@
+// Package memory provides an in-memory implementation of the storage.Storage interface.
+package memory
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"os"
+	"path/filepath"
+	"sort"
+	"sync"
+
+	"github.com/psyb0t/telegram-logger/internal/pkg/storage"
+)
+
+const snapshotFileMode = 0o600
+
+// snapshot is the content of the JSON file the data is saved to.
+type snapshot struct {
+	// SchemaVersion is the version of the last applied migration.
+	SchemaVersion int `json:"schemaVersion"`
+
+	Users    map[string]json.RawMessage `json:"users"`
+	Roles    map[string]json.RawMessage `json:"roles"`
+	Invites  map[string]json.RawMessage `json:"invites"`
+	Settings map[string]string          `json:"settings"`
+}
+
+// memoryDB is a struct that implements the Storage interface by keeping
+// the data in memory. Like the other implementations, the entities are
+// stored as JSON so that the callers never share memory with the stored
+// data. It's safe for concurrent use.
+type memoryDB struct {
+	ctx        context.Context //nolint:containedctx
+	cancelFunc context.CancelFunc
+	mu         sync.RWMutex
+	// snapshotPath is the path of the JSON file the data is loaded
+	// from on Open and saved to on Close. It's empty if the data
+	// shouldn't outlive the process.
+	snapshotPath   string
+	data           snapshot
+	userRepository struct {
+		reader storage.UserRepositoryReader
+		writer storage.UserRepositoryWriter
+	}
+	roleRepository struct {
+		reader storage.RoleRepositoryReader
+		writer storage.RoleRepositoryWriter
+	}
+	inviteRepository struct {
+		reader storage.InviteRepositoryReader
+		writer storage.InviteRepositoryWriter
+	}
+	settingRepository struct {
+		reader storage.SettingRepositoryReader
+		writer storage.SettingRepositoryWriter
+	}
+}
+
+// New creates and returns a new memoryDB instance.
+func New(parentCtx context.Context) (storage.Storage, error) {
+	db := &memoryDB{}
+	db.ctx, db.cancelFunc = context.WithCancel(parentCtx)
+	db.data = newSnapshot()
+
+	// the repositories lock the data for each operation
+	t := txn{db: db}
+
+	db.userRepository.reader = newUserRepositoryReader(t)
+	db.userRepository.writer = newUserRepositoryWriter(t)
+	db.roleRepository.reader = newRoleRepositoryReader(t)
+	db.roleRepository.writer = newRoleRepositoryWriter(t)
+	db.inviteRepository.reader = newInviteRepositoryReader(t)
+	db.inviteRepository.writer = newInviteRepositoryWriter(t)
+	db.settingRepository.reader = newSettingRepositoryReader(t)
+	db.settingRepository.writer = newSettingRepositoryWriter(t)
+
+	return db, nil
+}
+
+// Open prepares the database and applies the migrations. The provided
+// DSN (Data Source Name) is the path of the JSON snapshot file the data
+// is loaded from, if it exists, and saved to on Close. If it's empty,
+// the data is lost when the process exits.
+func (db *memoryDB) Open(ctx context.Context, dsn string) error {
+	db.mu.Lock()
+	defer db.mu.Unlock()
+
+	db.snapshotPath = dsn
+	db.data = newSnapshot()
+
+	if err := db.loadSnapshot(); err != nil {
+		return err
+	}
+
+	return storage.Migrate[*snapshot](ctx, &db.data, getMigrations())
+}
+
+// loadSnapshot loads the data from the snapshot file, if any.
+func (db *memoryDB) loadSnapshot() error {
+	if db.snapshotPath == "" {
+		return nil
+	}
+
+	content, err := os.ReadFile(db.snapshotPath)
+	if err != nil {
+		if errors.Is(err, os.ErrNotExist) {
+			return nil
+		}
+
+		return err
+	}
+
+	if err := json.Unmarshal(content, &db.data); err != nil {
+		return err
+	}
+
+	// make sure a snapshot missing some of the maps can be written to
+	fillSnapshot(&db.data)
+
+	return nil
+}
+
+// Close saves the data to the snapshot file, if any.
+func (db *memoryDB) Close() error {
+	defer db.cancelFunc()
+
+	db.mu.RLock()
+	defer db.mu.RUnlock()
+
+	if db.snapshotPath == "" {
+		return nil
+	}
+
+	content, err := json.Marshal(db.data)
+	if err != nil {
+		return err
+	}
+
+	// write to a temporary file first so that a failure
+	// can't leave a partially written snapshot behind
+	tmpFile, err := os.CreateTemp(filepath.Dir(db.snapshotPath), filepath.Base(db.snapshotPath)+".*")
+	if err != nil {
+		return err
+	}
+
+	defer os.Remove(tmpFile.Name())
+
+	if _, err := tmpFile.Write(content); err != nil {
+		tmpFile.Close()
+
+		return err
+	}
+
+	if err := tmpFile.Close(); err != nil {
+		return err
+	}
+
+	if err := os.Chmod(tmpFile.Name(), snapshotFileMode); err != nil {
+		return err
+	}
+
+	return os.Rename(tmpFile.Name(), db.snapshotPath)
+}
+
+// Ping checks if the database is reachable and responding.
+// memoryDB is always reachable so it will just return
+// nil unless the given context is done.
+func (db *memoryDB) Ping(ctx context.Context) error {
+	return ctx.Err()
+}
+
+// InTx runs fn in a transaction which is committed if fn returns nil
+// and rolled back otherwise. The data is locked for the whole
+// transaction so the transactions never conflict.
+func (db *memoryDB) InTx(ctx context.Context, fn func(tx storage.Repositories) error) error {
+	db.mu.Lock()
+	defer db.mu.Unlock()
+
+	if err := ctx.Err(); err != nil {
+		return err
+	}
+
+	// work on a copy so that it can be dropped if fn fails
+	data := db.data.clone()
+
+	if err := fn(txRepositories{db: txn{db: db, data: &data}}); err != nil {
+		return err
+	}
+
+	db.data = data
+
+	return nil
+}
+
+// GetUserRepositoryReader returns a repository for reading user data from the database.
+func (db *memoryDB) GetUserRepositoryReader() storage.UserRepositoryReader {
+	return db.userRepository.reader
+}
+
+// GetUserRepositoryWriter returns a repository for writing user data from the database.
+func (db *memoryDB) GetUserRepositoryWriter() storage.UserRepositoryWriter {
+	return db.userRepository.writer
+}
+
+// GetRoleRepositoryReader returns a repository for reading chat role data from the database.
+func (db *memoryDB) GetRoleRepositoryReader() storage.RoleRepositoryReader {
+	return db.roleRepository.reader
+}
+
+// GetRoleRepositoryWriter returns a repository for writing chat role data to the database.
+func (db *memoryDB) GetRoleRepositoryWriter() storage.RoleRepositoryWriter {
+	return db.roleRepository.writer
+}
+
+// GetInviteRepositoryReader returns a repository for reading invite data from the database.
+func (db *memoryDB) GetInviteRepositoryReader() storage.InviteRepositoryReader {
+	return db.inviteRepository.reader
+}
+
+// GetInviteRepositoryWriter returns a repository for writing invite data to the database.
+func (db *memoryDB) GetInviteRepositoryWriter() storage.InviteRepositoryWriter {
+	return db.inviteRepository.writer
+}
+
+// GetSettingRepositoryReader returns a repository for reading settings from the database.
+func (db *memoryDB) GetSettingRepositoryReader() storage.SettingRepositoryReader {
+	return db.settingRepository.reader
+}
+
+// GetSettingRepositoryWriter returns a repository for writing settings to the database.
+func (db *memoryDB) GetSettingRepositoryWriter() storage.SettingRepositoryWriter {
+	return db.settingRepository.writer
+}
+
+// newSnapshot returns a snapshot with empty maps.
+func newSnapshot() snapshot {
+	s := snapshot{}
+	fillSnapshot(&s)
+
+	return s
+}
+
+// fillSnapshot replaces the nil maps of the given snapshot with empty ones.
+func fillSnapshot(s *snapshot) {
+	if s.Users == nil {
+		s.Users = map[string]json.RawMessage{}
+	}
+
+	if s.Roles == nil {
+		s.Roles = map[string]json.RawMessage{}
+	}
+
+	if s.Invites == nil {
+		s.Invites = map[string]json.RawMessage{}
+	}
+
+	if s.Settings == nil {
+		s.Settings = map[string]string{}
+	}
+}
+
+// sortedKeys returns the keys of the given map sorted.
+func sortedKeys(m map[string]json.RawMessage) []string {
+	keys := make([]string, 0, len(m))
+	for key := range m {
+		keys = append(keys, key)
+	}
+
+	sort.Strings(keys)
+
+	return keys
+}
+
+// sortedValues returns the values of the given map sorted by key
+// so that the order is stable.
+func sortedValues(m map[string]json.RawMessage) []json.RawMessage {
+	keys := sortedKeys(m)
+
+	vals := make([]json.RawMessage, 0, len(keys))
+	for _, key := range keys {
+		vals = append(vals, m[key])
+	}
+
+	return vals
+}
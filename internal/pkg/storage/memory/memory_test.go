@@ -0,0 +1,124 @@
+package memory
+
+import (
+	"context"
+	"fmt"
+	"os"
+	"path/filepath"
+	"sync"
+	"testing"
+
+	"github.com/psyb0t/telegram-logger/internal/pkg/storage"
+	"github.com/psyb0t/telegram-logger/internal/pkg/storage/storagetest"
+	"github.com/psyb0t/telegram-logger/internal/pkg/types"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestMemoryDB(t *testing.T) {
+	ctx := context.Background()
+
+	storagetest.Run(t, func(t *testing.T) storage.Storage {
+		db, err := New(ctx)
+		require.NoError(t, err)
+		require.NoError(t, db.Open(ctx, ""))
+
+		return db
+	})
+}
+
+func TestMemoryDB_snapshot(t *testing.T) {
+	ctx := context.Background()
+
+	snapshotPath := filepath.Join(t.TempDir(), "db.json")
+
+	db, err := New(ctx)
+	require.NoError(t, err)
+	require.NoError(t, db.Open(ctx, snapshotPath))
+
+	user := types.User{ID: "a", TelegramChatID: 1}
+	require.NoError(t, db.GetUserRepositoryWriter().Create(ctx, user))
+	require.NoError(t, db.GetRoleRepositoryWriter().Set(ctx, types.ChatRole{TelegramChatID: 1, Role: types.RoleAdmin}))
+	require.NoError(t, db.GetSettingRepositoryWriter().Set(ctx, "a", "1"))
+	require.NoError(t, db.Close())
+
+	info, err := os.Stat(snapshotPath)
+	require.NoError(t, err)
+	assert.Equal(t, os.FileMode(snapshotFileMode), info.Mode().Perm())
+
+	db, err = New(ctx)
+	require.NoError(t, err)
+	require.NoError(t, db.Open(ctx, snapshotPath))
+
+	defer db.Close()
+
+	found, err := db.GetUserRepositoryReader().Get(ctx, "a")
+	require.NoError(t, err)
+	assert.Equal(t, user, found)
+
+	chatRole, err := db.GetRoleRepositoryReader().Get(ctx, 1)
+	require.NoError(t, err)
+	assert.Equal(t, types.RoleAdmin, chatRole.Role)
+
+	value, err := db.GetSettingRepositoryReader().Get(ctx, "a")
+	require.NoError(t, err)
+	assert.Equal(t, "1", value)
+
+	invites, err := db.GetInviteRepositoryReader().GetAll(ctx)
+	require.NoError(t, err)
+	assert.Empty(t, invites)
+}
+
+func TestMemoryDB_Open_invalidSnapshot(t *testing.T) {
+	ctx := context.Background()
+
+	snapshotPath := filepath.Join(t.TempDir(), "db.json")
+	require.NoError(t, os.WriteFile(snapshotPath, []byte("{"), snapshotFileMode))
+
+	db, err := New(ctx)
+	require.NoError(t, err)
+	assert.Error(t, db.Open(ctx, snapshotPath))
+}
+
+func TestMemoryDB_Open_newerSchema(t *testing.T) {
+	ctx := context.Background()
+
+	snapshotPath := filepath.Join(t.TempDir(), "db.json")
+	require.NoError(t, os.WriteFile(snapshotPath, []byte(`{"schemaVersion":1000}`), snapshotFileMode))
+
+	db, err := New(ctx)
+	require.NoError(t, err)
+	assert.ErrorIs(t, db.Open(ctx, snapshotPath), storage.ErrSchemaVersionTooNew)
+}
+
+func TestMemoryDB_concurrency(t *testing.T) {
+	ctx := context.Background()
+
+	db, err := New(ctx)
+	require.NoError(t, err)
+	require.NoError(t, db.Open(ctx, ""))
+
+	defer db.Close()
+
+	var wg sync.WaitGroup
+
+	for i := 0; i < 50; i++ {
+		wg.Add(1)
+
+		go func(i int) {
+			defer wg.Done()
+
+			user := types.User{ID: fmt.Sprintf("%d", i), TelegramChatID: int64(i%5 + 1)}
+			assert.NoError(t, db.GetUserRepositoryWriter().Create(ctx, user))
+
+			_, err := db.GetUserRepositoryReader().GetAllByTelegramChatID(ctx, user.TelegramChatID)
+			assert.NoError(t, err)
+		}(i)
+	}
+
+	wg.Wait()
+
+	users, err := db.GetUserRepositoryReader().GetAll(ctx)
+	require.NoError(t, err)
+	assert.Len(t, users, 50)
+}
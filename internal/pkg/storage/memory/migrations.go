@@ -0,0 +1,39 @@
+package memory
+
+import (
+	"context"
+
+	"github.com/psyb0t/telegram-logger/internal/pkg/storage"
+)
+
+// getMigrations returns the migrations of the snapshot in order.
+// The first one only marks the layout of the snapshot struct.
+func getMigrations() []storage.Migration[*snapshot] {
+	return []storage.Migration[*snapshot]{
+		{
+			Version: 1,
+			Name:    "initial snapshot layout",
+			Apply: func(context.Context, *snapshot) error {
+				return nil
+			},
+		},
+	}
+}
+
+// GetSchemaVersion returns the version of the last applied
+// migration or 0 if no migration was applied yet.
+func (s *snapshot) GetSchemaVersion(context.Context) (int, error) {
+	return s.SchemaVersion, nil
+}
+
+// ApplyMigration applies the given migration and records its version.
+// The snapshot isn't saved until Close so both happen at once.
+func (s *snapshot) ApplyMigration(ctx context.Context, m storage.Migration[*snapshot]) error {
+	if err := m.Apply(ctx, s); err != nil {
+		return err
+	}
+
+	s.SchemaVersion = m.Version
+
+	return nil
+}
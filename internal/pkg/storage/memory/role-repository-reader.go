@@ -0,0 +1,66 @@
+package memory
+
+import (
+	"context"
+	"encoding/json"
+	"strconv"
+
+	"github.com/psyb0t/telegram-logger/internal/pkg/storage"
+	"github.com/psyb0t/telegram-logger/internal/pkg/types"
+)
+
+// roleRepositoryReader is a struct that implements the
+// storage.RoleRepositoryReader interface using a memoryDB instance.
+type roleRepositoryReader struct {
+	// db accesses the data of the underlying memoryDB
+	// instance or of a transaction.
+	db txn
+}
+
+// newRoleRepositoryReader creates and returns
+// a new roleRepositoryReader instance.
+func newRoleRepositoryReader(db txn) storage.RoleRepositoryReader {
+	return roleRepositoryReader{db: db}
+}
+
+// Get retrieves the role of a Telegram chat ID.
+func (r roleRepositoryReader) Get(ctx context.Context, chatID int64) (types.ChatRole, error) {
+	chatRole := types.ChatRole{}
+
+	if chatID == 0 {
+		return chatRole, storage.ErrEmptyTelegramChatID
+	}
+
+	err := r.db.read(ctx, func(data *snapshot) error {
+		val, ok := data.Roles[strconv.FormatInt(chatID, 10)]
+		if !ok {
+			return storage.ErrNotFound
+		}
+
+		// Unmarshal the role data into the chat role struct.
+		return json.Unmarshal(val, &chatRole)
+	})
+
+	return chatRole, err
+}
+
+// GetAll retrieves all chat roles from the database.
+func (r roleRepositoryReader) GetAll(ctx context.Context) ([]types.ChatRole, error) {
+	chatRoles := []types.ChatRole{}
+
+	err := r.db.read(ctx, func(data *snapshot) error {
+		for _, val := range sortedValues(data.Roles) {
+			// Unmarshal the role data into a chat role struct.
+			var chatRole types.ChatRole
+			if err := json.Unmarshal(val, &chatRole); err != nil {
+				return err
+			}
+
+			chatRoles = append(chatRoles, chatRole)
+		}
+
+		return nil
+	})
+
+	return chatRoles, err
+}
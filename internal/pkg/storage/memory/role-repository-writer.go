@@ -0,0 +1,60 @@
+package memory
+
+import (
+	"context"
+	"encoding/json"
+	"strconv"
+
+	"github.com/psyb0t/telegram-logger/internal/pkg/storage"
+	"github.com/psyb0t/telegram-logger/internal/pkg/types"
+)
+
+// roleRepositoryWriter is a struct that implements the
+// storage.RoleRepositoryWriter interface using a memoryDB instance.
+type roleRepositoryWriter struct {
+	// db accesses the data of the underlying memoryDB
+	// instance or of a transaction.
+	db txn
+}
+
+// newRoleRepositoryWriter creates and returns
+// a new roleRepositoryWriter instance.
+func newRoleRepositoryWriter(db txn) storage.RoleRepositoryWriter {
+	return roleRepositoryWriter{db: db}
+}
+
+// Set stores the role of a Telegram chat ID replacing the existing one.
+func (r roleRepositoryWriter) Set(ctx context.Context, chatRole types.ChatRole) error {
+	if chatRole.TelegramChatID == 0 {
+		return storage.ErrEmptyTelegramChatID
+	}
+
+	if chatRole.Role == "" {
+		return storage.ErrEmptyRole
+	}
+
+	// Convert the chat role struct to a byte slice.
+	val, err := json.Marshal(chatRole)
+	if err != nil {
+		return err
+	}
+
+	return r.db.write(ctx, func(data *snapshot) error {
+		data.Roles[strconv.FormatInt(chatRole.TelegramChatID, 10)] = val
+
+		return nil
+	})
+}
+
+// Delete removes the role of a Telegram chat ID from the database.
+func (r roleRepositoryWriter) Delete(ctx context.Context, chatID int64) error {
+	if chatID == 0 {
+		return storage.ErrEmptyTelegramChatID
+	}
+
+	return r.db.write(ctx, func(data *snapshot) error {
+		delete(data.Roles, strconv.FormatInt(chatID, 10))
+
+		return nil
+	})
+}
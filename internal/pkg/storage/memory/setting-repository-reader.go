@@ -0,0 +1,59 @@
+package memory
+
+import (
+	"context"
+
+	"github.com/psyb0t/telegram-logger/internal/pkg/storage"
+)
+
+// settingRepositoryReader is a struct that implements the
+// storage.SettingRepositoryReader interface using a memoryDB instance.
+type settingRepositoryReader struct {
+	// db accesses the data of the underlying memoryDB
+	// instance or of a transaction.
+	db txn
+}
+
+// newSettingRepositoryReader creates and returns
+// a new settingRepositoryReader instance.
+func newSettingRepositoryReader(db txn) storage.SettingRepositoryReader {
+	return settingRepositoryReader{db: db}
+}
+
+// Get retrieves the value of a setting by key.
+func (r settingRepositoryReader) Get(ctx context.Context, key string) (string, error) {
+	if key == "" {
+		return "", storage.ErrEmptyKey
+	}
+
+	var value string
+
+	err := r.db.read(ctx, func(data *snapshot) error {
+		var ok bool
+		if value, ok = data.Settings[key]; !ok {
+			return storage.ErrNotFound
+		}
+
+		return nil
+	})
+
+	return value, err
+}
+
+// GetAll retrieves all settings from the database by key.
+func (r settingRepositoryReader) GetAll(ctx context.Context) (map[string]string, error) {
+	settings := map[string]string{}
+
+	err := r.db.read(ctx, func(data *snapshot) error {
+		for key, value := range data.Settings {
+			settings[key] = value
+		}
+
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	return settings, nil
+}
@@ -0,0 +1,34 @@
+package memory
+
+import (
+	"context"
+
+	"github.com/psyb0t/telegram-logger/internal/pkg/storage"
+)
+
+// settingRepositoryWriter is a struct that implements the
+// storage.SettingRepositoryWriter interface using a memoryDB instance.
+type settingRepositoryWriter struct {
+	// db accesses the data of the underlying memoryDB
+	// instance or of a transaction.
+	db txn
+}
+
+// newSettingRepositoryWriter creates and returns
+// a new settingRepositoryWriter instance.
+func newSettingRepositoryWriter(db txn) storage.SettingRepositoryWriter {
+	return settingRepositoryWriter{db: db}
+}
+
+// Set stores the value of a setting replacing the existing one.
+func (r settingRepositoryWriter) Set(ctx context.Context, key, value string) error {
+	if key == "" {
+		return storage.ErrEmptyKey
+	}
+
+	return r.db.write(ctx, func(data *snapshot) error {
+		data.Settings[key] = value
+
+		return nil
+	})
+}
@@ -0,0 +1,127 @@
+package memory
+
+import (
+	"context"
+	"encoding/json"
+
+	"github.com/psyb0t/telegram-logger/internal/pkg/storage"
+)
+
+// txn is what the repositories access the data through. If it holds
+// the data of a transaction, the lock is already held by InTx and the
+// data is used as is. Otherwise the data of the database is locked
+// for each operation.
+type txn struct {
+	db   *memoryDB
+	data *snapshot
+}
+
+// read runs the given function with the data locked for
+// reading unless the given context is already done.
+func (t txn) read(ctx context.Context, fn func(data *snapshot) error) error {
+	if err := ctx.Err(); err != nil {
+		return err
+	}
+
+	if t.data != nil {
+		return fn(t.data)
+	}
+
+	t.db.mu.RLock()
+	defer t.db.mu.RUnlock()
+
+	return fn(&t.db.data)
+}
+
+// write runs the given function with the data locked for
+// writing unless the given context is already done.
+func (t txn) write(ctx context.Context, fn func(data *snapshot) error) error {
+	if err := ctx.Err(); err != nil {
+		return err
+	}
+
+	if t.data != nil {
+		return fn(t.data)
+	}
+
+	t.db.mu.Lock()
+	defer t.db.mu.Unlock()
+
+	return fn(&t.db.data)
+}
+
+// clone returns a copy of the snapshot which can be changed without
+// changing the original. The stored values are replaced and never
+// changed in place so copying the maps is enough.
+func (s snapshot) clone() snapshot {
+	c := snapshot{
+		SchemaVersion: s.SchemaVersion,
+		Users:         make(map[string]json.RawMessage, len(s.Users)),
+		Roles:         make(map[string]json.RawMessage, len(s.Roles)),
+		Invites:       make(map[string]json.RawMessage, len(s.Invites)),
+		Settings:      make(map[string]string, len(s.Settings)),
+	}
+
+	for k, v := range s.Users {
+		c.Users[k] = v
+	}
+
+	for k, v := range s.Roles {
+		c.Roles[k] = v
+	}
+
+	for k, v := range s.Invites {
+		c.Invites[k] = v
+	}
+
+	for k, v := range s.Settings {
+		c.Settings[k] = v
+	}
+
+	return c
+}
+
+// txRepositories hands out the repositories of a transaction.
+type txRepositories struct {
+	db txn
+}
+
+// GetUserRepositoryReader returns a repository for reading user data in the transaction.
+func (r txRepositories) GetUserRepositoryReader() storage.UserRepositoryReader {
+	return newUserRepositoryReader(r.db)
+}
+
+// GetUserRepositoryWriter returns a repository for writing user data in the transaction.
+func (r txRepositories) GetUserRepositoryWriter() storage.UserRepositoryWriter {
+	return newUserRepositoryWriter(r.db)
+}
+
+// GetRoleRepositoryReader returns a repository for reading chat role data in the transaction.
+func (r txRepositories) GetRoleRepositoryReader() storage.RoleRepositoryReader {
+	return newRoleRepositoryReader(r.db)
+}
+
+// GetRoleRepositoryWriter returns a repository for writing chat role data in the transaction.
+func (r txRepositories) GetRoleRepositoryWriter() storage.RoleRepositoryWriter {
+	return newRoleRepositoryWriter(r.db)
+}
+
+// GetInviteRepositoryReader returns a repository for reading invite data in the transaction.
+func (r txRepositories) GetInviteRepositoryReader() storage.InviteRepositoryReader {
+	return newInviteRepositoryReader(r.db)
+}
+
+// GetInviteRepositoryWriter returns a repository for writing invite data in the transaction.
+func (r txRepositories) GetInviteRepositoryWriter() storage.InviteRepositoryWriter {
+	return newInviteRepositoryWriter(r.db)
+}
+
+// GetSettingRepositoryReader returns a repository for reading settings in the transaction.
+func (r txRepositories) GetSettingRepositoryReader() storage.SettingRepositoryReader {
+	return newSettingRepositoryReader(r.db)
+}
+
+// GetSettingRepositoryWriter returns a repository for writing settings in the transaction.
+func (r txRepositories) GetSettingRepositoryWriter() storage.SettingRepositoryWriter {
+	return newSettingRepositoryWriter(r.db)
+}
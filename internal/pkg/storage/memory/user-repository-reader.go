@@ -0,0 +1,140 @@
+package memory
+
+import (
+	"context"
+	"encoding/json"
+
+	"github.com/psyb0t/telegram-logger/internal/pkg/storage"
+	"github.com/psyb0t/telegram-logger/internal/pkg/types"
+)
+
+// userRepositoryReader is a struct that implements the
+// storage.UserRepositoryReader interface using a memoryDB instance.
+type userRepositoryReader struct {
+	// db accesses the data of the underlying memoryDB
+	// instance or of a transaction.
+	db txn
+}
+
+// newUserRepositoryReader creates and returns
+// a new userRepositoryReader instance.
+func newUserRepositoryReader(db txn) storage.UserRepositoryReader {
+	return userRepositoryReader{db: db}
+}
+
+// Get retrieves a user by ID.
+func (r userRepositoryReader) Get(ctx context.Context, id string) (types.User, error) {
+	user := types.User{}
+
+	if id == "" {
+		return user, storage.ErrEmptyID
+	}
+
+	err := r.db.read(ctx, func(data *snapshot) error {
+		val, ok := data.Users[id]
+		if !ok {
+			return storage.ErrNotFound
+		}
+
+		// Unmarshal the user data into the user struct.
+		return json.Unmarshal(val, &user)
+	})
+
+	return user, err
+}
+
+// GetAll retrieves all users from the database.
+func (r userRepositoryReader) GetAll(ctx context.Context) ([]types.User, error) {
+	return r.getAllByFilterFunc(ctx, func(types.User) bool {
+		return true
+	})
+}
+
+// GetPage retrieves the page of users, sorted by ID, selected by the given cursor.
+func (r userRepositoryReader) GetPage(ctx context.Context, cursor storage.Cursor) ([]types.User, error) {
+	if err := cursor.Validate(); err != nil {
+		return nil, err
+	}
+
+	users := []types.User{}
+
+	err := r.db.read(ctx, func(data *snapshot) error {
+		for _, id := range cursor.Page(sortedKeys(data.Users)) {
+			// Unmarshal the user data into a user struct.
+			var user types.User
+			if err := json.Unmarshal(data.Users[id], &user); err != nil {
+				return err
+			}
+
+			users = append(users, user)
+		}
+
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	return users, nil
+}
+
+// Iterate calls fn for every user, sorted by ID, reading them a page
+// at a time so that fn can use the storage. It stops at the first
+// error returned by fn.
+func (r userRepositoryReader) Iterate(ctx context.Context, fn func(user types.User) error) error {
+	return storage.IterateUsers(ctx, r, fn)
+}
+
+// GetByTelegramChatID retrieves the first user found by its Telegram chat ID.
+func (r userRepositoryReader) GetByTelegramChatID(ctx context.Context, chatID int64) (types.User, error) {
+	users, err := r.GetAllByTelegramChatID(ctx, chatID)
+	if err != nil {
+		return types.User{}, err
+	}
+
+	if len(users) == 0 {
+		return types.User{}, storage.ErrNotFound
+	}
+
+	return users[0], nil
+}
+
+// GetAllByTelegramChatID retrieves all users matching the given Telegram chat ID.
+func (r userRepositoryReader) GetAllByTelegramChatID(ctx context.Context, chatID int64) ([]types.User, error) {
+	if chatID == 0 {
+		return []types.User{}, storage.ErrEmptyTelegramChatID
+	}
+
+	return r.getAllByFilterFunc(ctx, func(user types.User) bool {
+		return user.TelegramChatID == chatID
+	})
+}
+
+// getAllByFilterFunc retrieves all users passing the given filter, sorted by ID.
+func (r userRepositoryReader) getAllByFilterFunc(ctx context.Context,
+	filterFn func(types.User) bool,
+) ([]types.User, error) {
+	users := []types.User{}
+
+	err := r.db.read(ctx, func(data *snapshot) error {
+		for _, val := range sortedValues(data.Users) {
+			if err := ctx.Err(); err != nil {
+				return err
+			}
+
+			// Unmarshal the user data into a user struct.
+			var user types.User
+			if err := json.Unmarshal(val, &user); err != nil {
+				return err
+			}
+
+			if filterFn(user) {
+				users = append(users, user)
+			}
+		}
+
+		return nil
+	})
+
+	return users, err
+}
@@ -0,0 +1,146 @@
+package memory
+
+import (
+	"context"
+	"encoding/json"
+
+	"github.com/psyb0t/telegram-logger/internal/pkg/storage"
+	"github.com/psyb0t/telegram-logger/internal/pkg/types"
+)
+
+// userRepositoryWriter is a struct that implements the
+// storage.UserRepositoryWriter interface using a memoryDB instance.
+type userRepositoryWriter struct {
+	// db accesses the data of the underlying memoryDB
+	// instance or of a transaction.
+	db txn
+}
+
+// newUserRepositoryWriter creates and returns
+// a new userRepositoryWriter instance.
+func newUserRepositoryWriter(db txn) storage.UserRepositoryWriter {
+	return userRepositoryWriter{db: db}
+}
+
+// Create stores a new user in the database.
+//
+// user is the user to be stored. It must have a non-empty ID field.
+func (r userRepositoryWriter) Create(ctx context.Context, user types.User) error {
+	if user.ID == "" {
+		return storage.ErrEmptyID
+	}
+
+	// Convert the user struct to a byte slice.
+	val, err := json.Marshal(user)
+	if err != nil {
+		return err
+	}
+
+	return r.db.write(ctx, func(data *snapshot) error {
+		data.Users[user.ID] = val
+
+		return nil
+	})
+}
+
+// Update replaces an existing user in the database.
+//
+// user is the user to be stored. It must have a non-empty ID field.
+func (r userRepositoryWriter) Update(ctx context.Context, user types.User) error {
+	if user.ID == "" {
+		return storage.ErrEmptyID
+	}
+
+	// Convert the user struct to a byte slice.
+	val, err := json.Marshal(user)
+	if err != nil {
+		return err
+	}
+
+	return r.db.write(ctx, func(data *snapshot) error {
+		if _, ok := data.Users[user.ID]; !ok {
+			return storage.ErrNotFound
+		}
+
+		data.Users[user.ID] = val
+
+		return nil
+	})
+}
+
+// Delete removes a user from the database by ID.
+func (r userRepositoryWriter) Delete(ctx context.Context, id string) error {
+	if id == "" {
+		return storage.ErrEmptyID
+	}
+
+	return r.db.write(ctx, func(data *snapshot) error {
+		delete(data.Users, id)
+
+		return nil
+	})
+}
+
+// DeleteAllByTelegramChatID removes all users from the database
+// matching the given Telegram chat ID.
+func (r userRepositoryWriter) DeleteAllByTelegramChatID(ctx context.Context, chatID int64) error {
+	if chatID == 0 {
+		return storage.ErrEmptyTelegramChatID
+	}
+
+	return r.db.write(ctx, func(data *snapshot) error {
+		for id, val := range data.Users {
+			var user types.User
+			if err := json.Unmarshal(val, &user); err != nil {
+				return err
+			}
+
+			if user.TelegramChatID == chatID {
+				delete(data.Users, id)
+			}
+		}
+
+		return nil
+	})
+}
+
+// UpdateTelegramChatID replaces the Telegram chat ID of all of the users
+// matching oldChatID with newChatID. The lock is held for the whole
+// change so it's seen all at once.
+func (r userRepositoryWriter) UpdateTelegramChatID(ctx context.Context, oldChatID, newChatID int64) error {
+	if oldChatID == 0 || newChatID == 0 {
+		return storage.ErrEmptyTelegramChatID
+	}
+
+	return r.db.write(ctx, func(data *snapshot) error {
+		// Collect the changes first so that either all
+		// or none of the users are changed.
+		updates := map[string]json.RawMessage{}
+
+		for id, val := range data.Users {
+			var user types.User
+			if err := json.Unmarshal(val, &user); err != nil {
+				return err
+			}
+
+			if user.TelegramChatID != oldChatID {
+				continue
+			}
+
+			user.TelegramChatID = newChatID
+
+			newVal, err := json.Marshal(user)
+			if err != nil {
+				return err
+			}
+
+			updates[id] = newVal
+		}
+
+		for id, val := range updates {
+			data.Users[id] = val
+		}
+
+		return nil
+	})
+}
@@ -0,0 +1,65 @@
+package storage
+
+import (
+	"context"
+	"fmt"
+)
+
+// Migration is a change to the schema or to the stored data which is
+// applied once, in the order of its version. T is whatever the storage
+// hands to the migrations to make the change, eg. a transaction.
+//
+// Apply should be idempotent since not every storage can apply a
+// migration and record its version atomically so a migration can
+// run again if the process stops in between.
+type Migration[T any] struct {
+	// Version is the schema version after the migration is applied.
+	// The versions of a storage's migrations start from 1 and grow by 1.
+	Version int
+
+	// Name describes the migration.
+	Name string
+
+	// Apply makes the change.
+	Apply func(ctx context.Context, tx T) error
+}
+
+// SchemaVersionStore is implemented by the storages to keep track of
+// the version of the schema of the stored data.
+type SchemaVersionStore[T any] interface {
+	// GetSchemaVersion returns the version of the last applied
+	// migration or 0 if no migration was applied yet.
+	GetSchemaVersion(ctx context.Context) (int, error)
+
+	// ApplyMigration applies the given migration and records its version.
+	ApplyMigration(ctx context.Context, m Migration[T]) error
+}
+
+// Migrate applies the migrations newer than the stored schema version in
+// the order of their version. It refuses to touch a schema that is newer
+// than the last migration since it was written by a newer version of
+// the app which this one doesn't know how to read.
+func Migrate[T any](ctx context.Context, store SchemaVersionStore[T], migrations []Migration[T]) error {
+	for i, m := range migrations {
+		if m.Version != i+1 {
+			return fmt.Errorf("%w: %d (%s)", ErrInvalidMigrationVersion, m.Version, m.Name)
+		}
+	}
+
+	version, err := store.GetSchemaVersion(ctx)
+	if err != nil {
+		return err
+	}
+
+	if version > len(migrations) {
+		return fmt.Errorf("%w: %d > %d", ErrSchemaVersionTooNew, version, len(migrations))
+	}
+
+	for _, m := range migrations[version:] {
+		if err := store.ApplyMigration(ctx, m); err != nil {
+			return fmt.Errorf("migration %d (%s): %w", m.Version, m.Name, err)
+		}
+	}
+
+	return nil
+}
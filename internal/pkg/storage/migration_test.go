@@ -0,0 +1,125 @@
+package storage
+
+import (
+	"context"
+	"errors"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// testSchemaVersionStore keeps the schema version in memory
+// and hands itself to the migrations.
+type testSchemaVersionStore struct {
+	version int
+	applied []string
+}
+
+func (s *testSchemaVersionStore) GetSchemaVersion(context.Context) (int, error) {
+	return s.version, nil
+}
+
+func (s *testSchemaVersionStore) ApplyMigration(ctx context.Context, m Migration[*testSchemaVersionStore]) error {
+	if err := m.Apply(ctx, s); err != nil {
+		return err
+	}
+
+	s.version = m.Version
+
+	return nil
+}
+
+func newTestMigration(version int, name string) Migration[*testSchemaVersionStore] {
+	return Migration[*testSchemaVersionStore]{
+		Version: version,
+		Name:    name,
+		Apply: func(_ context.Context, s *testSchemaVersionStore) error {
+			s.applied = append(s.applied, name)
+
+			return nil
+		},
+	}
+}
+
+func TestMigrate(t *testing.T) {
+	errTest := errors.New("test")
+
+	migrations := []Migration[*testSchemaVersionStore]{
+		newTestMigration(1, "a"),
+		newTestMigration(2, "b"),
+		newTestMigration(3, "c"),
+	}
+
+	failing := newTestMigration(3, "c")
+	failing.Apply = func(context.Context, *testSchemaVersionStore) error {
+		return errTest
+	}
+
+	testCases := []struct {
+		name            string
+		version         int
+		migrations      []Migration[*testSchemaVersionStore]
+		expectedVersion int
+		expectedApplied []string
+		expectedErr     error
+	}{
+		{
+			name:            "new storage",
+			migrations:      migrations,
+			expectedVersion: 3,
+			expectedApplied: []string{"a", "b", "c"},
+		},
+		{
+			name:            "applied migrations are skipped",
+			version:         1,
+			migrations:      migrations,
+			expectedVersion: 3,
+			expectedApplied: []string{"b", "c"},
+		},
+		{
+			name:            "up to date",
+			version:         3,
+			migrations:      migrations,
+			expectedVersion: 3,
+		},
+		{
+			name:            "newer schema",
+			version:         4,
+			migrations:      migrations,
+			expectedVersion: 4,
+			expectedErr:     ErrSchemaVersionTooNew,
+		},
+		{
+			name:            "versions out of order",
+			migrations:      []Migration[*testSchemaVersionStore]{migrations[1], migrations[0]},
+			expectedVersion: 0,
+			expectedErr:     ErrInvalidMigrationVersion,
+		},
+		{
+			name:            "failing migration",
+			migrations:      []Migration[*testSchemaVersionStore]{migrations[0], migrations[1], failing},
+			expectedVersion: 2,
+			expectedApplied: []string{"a", "b"},
+			expectedErr:     errTest,
+		},
+	}
+
+	for _, tc := range testCases {
+		tc := tc
+
+		t.Run(tc.name, func(t *testing.T) {
+			store := &testSchemaVersionStore{version: tc.version}
+
+			err := Migrate[*testSchemaVersionStore](context.Background(), store, tc.migrations)
+			if tc.expectedErr != nil {
+				assert.ErrorIs(t, err, tc.expectedErr)
+			} else {
+				require.NoError(t, err)
+			}
+
+			assert.Equal(t, tc.expectedVersion, store.version)
+			assert.Equal(t, tc.expectedApplied, store.applied)
+		})
+	}
+}
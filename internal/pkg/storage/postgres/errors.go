@@ -0,0 +1,11 @@
+package postgres
+
+import "errors"
+
+// ErrInvalidMigrationName is returned when an embedded migration
+// file isn't named <version>_<name>.sql.
+var ErrInvalidMigrationName = errors.New("invalid migration name")
+
+// ErrTooManyTxRetries is returned when a transaction keeps failing
+// because it can't be serialized with the ones running at the same time.
+var ErrTooManyTxRetries = errors.New("too many transaction retries")
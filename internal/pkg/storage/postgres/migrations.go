@@ -0,0 +1,139 @@
+package postgres
+
+import (
+	"context"
+	"embed"
+	"fmt"
+	"io/fs"
+	"sort"
+	"strconv"
+	"strings"
+
+	"github.com/jackc/pgx/v5"
+	"github.com/psyb0t/telegram-logger/internal/pkg/storage"
+)
+
+// migrationsAdvisoryLockID is the ID of the advisory lock held while
+// migrating so that replicas starting at the same time don't race.
+const migrationsAdvisoryLockID = 7283461
+
+//go:embed migrations/*.sql
+var migrationsFS embed.FS
+
+// migrationFile is an embedded SQL migration.
+type migrationFile struct {
+	version int
+	name    string
+	query   string
+}
+
+// getMigrations returns the embedded migrations sorted by version. The
+// files are named <version>_<name>.sql, eg. 0001_create_tables.sql.
+func getMigrations() ([]migrationFile, error) {
+	entries, err := fs.ReadDir(migrationsFS, "migrations")
+	if err != nil {
+		return nil, err
+	}
+
+	migrations := make([]migrationFile, 0, len(entries))
+
+	for _, entry := range entries {
+		versionStr, name, ok := strings.Cut(strings.TrimSuffix(entry.Name(), ".sql"), "_")
+		if !ok {
+			return nil, fmt.Errorf("%w: %s", ErrInvalidMigrationName, entry.Name())
+		}
+
+		version, err := strconv.Atoi(versionStr)
+		if err != nil {
+			return nil, fmt.Errorf("%w: %s", ErrInvalidMigrationName, entry.Name())
+		}
+
+		query, err := fs.ReadFile(migrationsFS, "migrations/"+entry.Name())
+		if err != nil {
+			return nil, err
+		}
+
+		migrations = append(migrations, migrationFile{version: version, name: name, query: string(query)})
+	}
+
+	sort.Slice(migrations, func(i, j int) bool {
+		return migrations[i].version < migrations[j].version
+	})
+
+	return migrations, nil
+}
+
+// migrate applies the migrations that weren't applied yet.
+func (db *postgresDB) migrate(ctx context.Context) error {
+	files, err := getMigrations()
+	if err != nil {
+		return err
+	}
+
+	if _, err := db.pool.Exec(ctx, `CREATE TABLE IF NOT EXISTS schema_migrations (
+		version INTEGER PRIMARY KEY,
+		name TEXT NOT NULL,
+		applied_at TIMESTAMPTZ NOT NULL DEFAULT now()
+	)`); err != nil {
+		return err
+	}
+
+	migrations := make([]storage.Migration[pgx.Tx], 0, len(files))
+
+	for _, f := range files {
+		query := f.query
+
+		migrations = append(migrations, storage.Migration[pgx.Tx]{
+			Version: f.version,
+			Name:    f.name,
+			Apply: func(ctx context.Context, tx pgx.Tx) error {
+				_, err := tx.Exec(ctx, query)
+
+				return err
+			},
+		})
+	}
+
+	return storage.Migrate[pgx.Tx](ctx, db, migrations)
+}
+
+// GetSchemaVersion returns the version of the last applied
+// migration or 0 if no migration was applied yet.
+func (db *postgresDB) GetSchemaVersion(ctx context.Context) (int, error) {
+	var version int
+
+	err := db.pool.QueryRow(ctx, `SELECT COALESCE(MAX(version), 0) FROM schema_migrations`).Scan(&version)
+
+	return version, err
+}
+
+// ApplyMigration applies the given migration unless it was already
+// applied and records its version in the same transaction.
+func (db *postgresDB) ApplyMigration(ctx context.Context, m storage.Migration[pgx.Tx]) error {
+	return pgx.BeginFunc(ctx, db.pool, func(tx pgx.Tx) error {
+		// the lock is released when the transaction ends
+		if _, err := tx.Exec(ctx, `SELECT pg_advisory_xact_lock($1)`, migrationsAdvisoryLockID); err != nil {
+			return err
+		}
+
+		// a replica starting at the same time may have applied it
+		// while this one was waiting for the lock
+		var applied bool
+		if err := tx.QueryRow(ctx, `SELECT EXISTS (SELECT 1 FROM schema_migrations WHERE version = $1)`,
+			m.Version).Scan(&applied); err != nil {
+			return err
+		}
+
+		if applied {
+			return nil
+		}
+
+		if err := m.Apply(ctx, tx); err != nil {
+			return err
+		}
+
+		_, err := tx.Exec(ctx, `INSERT INTO schema_migrations (version, name) VALUES ($1, $2)`, m.Version, m.Name)
+
+		return err
+	})
+}
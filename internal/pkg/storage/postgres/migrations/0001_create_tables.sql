@@ -0,0 +1,25 @@
+-- The entities are stored as JSON in the data column so that adding
+-- fields doesn't need a migration. The columns that are looked up
+-- by are stored next to it.
+CREATE TABLE users (
+	id TEXT PRIMARY KEY,
+	telegram_chat_id BIGINT NOT NULL,
+	data JSONB NOT NULL
+);
+
+CREATE INDEX users_telegram_chat_id ON users (telegram_chat_id);
+
+CREATE TABLE roles (
+	telegram_chat_id BIGINT PRIMARY KEY,
+	data JSONB NOT NULL
+);
+
+CREATE TABLE invites (
+	id TEXT PRIMARY KEY,
+	data JSONB NOT NULL
+);
+
+CREATE TABLE settings (
+	key TEXT PRIMARY KEY,
+	value TEXT NOT NULL
+);
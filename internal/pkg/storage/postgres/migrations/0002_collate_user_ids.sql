@@ -0,0 +1,4 @@
+-- The users are listed in pages by ID so the IDs have to be compared
+-- byte by byte, like every other backend does, whatever the collation
+-- of the database is. The primary key index is rebuilt with it.
+ALTER TABLE users ALTER COLUMN id TYPE TEXT COLLATE "C";
@@ -0,0 +1,32 @@
+package postgres
+
+import (
+	"context"
+
+	"github.com/psyb0t/telegram-logger/internal/pkg/storage"
+)
+
+// settingRepositoryWriter is a struct that implements the
+// storage.SettingRepositoryWriter interface using a postgresDB instance.
+type settingRepositoryWriter struct {
+	// db runs the statements on the underlying postgresDB
+	// instance, in a transaction if it holds one.
+	db txn
+}
+
+// newSettingRepositoryWriter creates and returns
+// a new settingRepositoryWriter instance.
+func newSettingRepositoryWriter(db txn) storage.SettingRepositoryWriter {
+	return settingRepositoryWriter{db: db}
+}
+
+// Set stores the value of a setting replacing the existing one.
+func (r settingRepositoryWriter) Set(ctx context.Context, key, value string) error {
+	if key == "" {
+		return storage.ErrEmptyKey
+	}
+
+	// Store the setting
+	return r.db.exec(ctx, `INSERT INTO settings (key, value) VALUES ($1, $2)
+		ON CONFLICT (key) DO UPDATE SET value = excluded.value`, key, value)
+}
@@ -0,0 +1,150 @@
+package postgres
+
+import (
+	"context"
+	"errors"
+
+	"github.com/jackc/pgx/v5"
+	"github.com/jackc/pgx/v5/pgconn"
+	"github.com/psyb0t/telegram-logger/internal/pkg/storage"
+)
+
+// sqlStateSerializationFailure is the error code of a transaction
+// that can't be serialized with the ones running at the same time.
+const sqlStateSerializationFailure = "40001"
+
+// querier is implemented by both *pgxpool.Pool and pgx.Tx.
+type querier interface {
+	Exec(ctx context.Context, sql string, args ...interface{}) (pgconn.CommandTag, error)
+	Query(ctx context.Context, sql string, args ...interface{}) (pgx.Rows, error)
+	QueryRow(ctx context.Context, sql string, args ...interface{}) pgx.Row
+}
+
+// txn is what the repositories run their statements through. If it
+// holds a transaction, every statement runs in it. Otherwise each
+// statement runs on its own.
+type txn struct {
+	db *postgresDB
+	tx pgx.Tx
+}
+
+// querier returns the transaction or, if there's none, the connection pool.
+func (t txn) querier() querier {
+	if t.tx != nil {
+		return t.tx
+	}
+
+	return t.db.pool
+}
+
+// get retrieves the single column of the first row returned by the given query.
+func (t txn) get(ctx context.Context, query string, args ...interface{}) ([]byte, error) {
+	var val []byte
+
+	err := t.querier().QueryRow(ctx, query, args...).Scan(&val)
+	if err != nil {
+		// If there's no row, return an error.
+		if errors.Is(err, pgx.ErrNoRows) {
+			return nil, storage.ErrNotFound
+		}
+
+		return nil, err
+	}
+
+	return val, nil
+}
+
+// getAll retrieves the single column of all of the rows returned by the given query.
+func (t txn) getAll(ctx context.Context, query string, args ...interface{}) ([][]byte, error) {
+	rows, err := t.querier().Query(ctx, query, args...)
+	if err != nil {
+		return nil, err
+	}
+
+	result, err := pgx.CollectRows(rows, pgx.RowTo[[]byte])
+	if err != nil {
+		return nil, err
+	}
+
+	// CollectRows returns nil if there are no rows
+	if result == nil {
+		result = [][]byte{}
+	}
+
+	return result, nil
+}
+
+// exec runs the given statement.
+func (t txn) exec(ctx context.Context, query string, args ...interface{}) error {
+	_, err := t.querier().Exec(ctx, query, args...)
+
+	return err
+}
+
+// update runs the given statement and returns storage.ErrNotFound
+// if it didn't change any row.
+func (t txn) update(ctx context.Context, query string, args ...interface{}) error {
+	tag, err := t.querier().Exec(ctx, query, args...)
+	if err != nil {
+		return err
+	}
+
+	if tag.RowsAffected() == 0 {
+		return storage.ErrNotFound
+	}
+
+	return nil
+}
+
+// isSerializationFailure checks if the given error was returned because
+// the transaction couldn't be serialized and should be retried.
+func isSerializationFailure(err error) bool {
+	var pgErr *pgconn.PgError
+
+	return errors.As(err, &pgErr) && pgErr.Code == sqlStateSerializationFailure
+}
+
+// txRepositories hands out the repositories of a transaction.
+type txRepositories struct {
+	db txn
+}
+
+// GetUserRepositoryReader returns a repository for reading user data in the transaction.
+func (r txRepositories) GetUserRepositoryReader() storage.UserRepositoryReader {
+	return newUserRepositoryReader(r.db)
+}
+
+// GetUserRepositoryWriter returns a repository for writing user data in the transaction.
+func (r txRepositories) GetUserRepositoryWriter() storage.UserRepositoryWriter {
+	return newUserRepositoryWriter(r.db)
+}
+
+// GetRoleRepositoryReader returns a repository for reading chat role data in the transaction.
+func (r txRepositories) GetRoleRepositoryReader() storage.RoleRepositoryReader {
+	return newRoleRepositoryReader(r.db)
+}
+
+// GetRoleRepositoryWriter returns a repository for writing chat role data in the transaction.
+func (r txRepositories) GetRoleRepositoryWriter() storage.RoleRepositoryWriter {
+	return newRoleRepositoryWriter(r.db)
+}
+
+// GetInviteRepositoryReader returns a repository for reading invite data in the transaction.
+func (r txRepositories) GetInviteRepositoryReader() storage.InviteRepositoryReader {
+	return newInviteRepositoryReader(r.db)
+}
+
+// GetInviteRepositoryWriter returns a repository for writing invite data in the transaction.
+func (r txRepositories) GetInviteRepositoryWriter() storage.InviteRepositoryWriter {
+	return newInviteRepositoryWriter(r.db)
+}
+
+// GetSettingRepositoryReader returns a repository for reading settings in the transaction.
+func (r txRepositories) GetSettingRepositoryReader() storage.SettingRepositoryReader {
+	return newSettingRepositoryReader(r.db)
+}
+
+// GetSettingRepositoryWriter returns a repository for writing settings in the transaction.
+func (r txRepositories) GetSettingRepositoryWriter() storage.SettingRepositoryWriter {
+	return newSettingRepositoryWriter(r.db)
+}
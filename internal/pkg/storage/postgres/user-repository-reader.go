@@ -0,0 +1,142 @@
+package postgres
+
+import (
+	"context"
+	"encoding/json"
+
+	"github.com/psyb0t/telegram-logger/internal/pkg/storage"
+	"github.com/psyb0t/telegram-logger/internal/pkg/types"
+)
+
+// userRepositoryReader is a struct that implements the
+// storage.UserRepositoryReader interface using a postgresDB instance.
+type userRepositoryReader struct {
+	// db runs the statements on the underlying postgresDB
+	// instance, in a transaction if it holds one.
+	db txn
+}
+
+// newUserRepositoryReader creates and returns
+// a new userRepositoryReader instance.
+func newUserRepositoryReader(db txn) storage.UserRepositoryReader {
+	return userRepositoryReader{db: db}
+}
+
+// Get retrieves a user by ID.
+func (r userRepositoryReader) Get(ctx context.Context, id string) (types.User, error) {
+	user := types.User{}
+
+	if id == "" {
+		return user, storage.ErrEmptyID
+	}
+
+	val, err := r.db.get(ctx, `SELECT data FROM users WHERE id = $1`, id)
+	if err != nil {
+		return user, err
+	}
+
+	// Unmarshal the user data into the user struct.
+	if err := json.Unmarshal(val, &user); err != nil {
+		return user, err
+	}
+
+	return user, nil
+}
+
+// GetAll retrieves all users from the database.
+func (r userRepositoryReader) GetAll(ctx context.Context) ([]types.User, error) {
+	vals, err := r.db.getAll(ctx, `SELECT data FROM users ORDER BY id`)
+	if err != nil {
+		return nil, err
+	}
+
+	return unmarshalUsers(vals)
+}
+
+// GetPage retrieves the page of users, sorted by ID, selected by the given cursor.
+func (r userRepositoryReader) GetPage(ctx context.Context, cursor storage.Cursor) ([]types.User, error) {
+	if err := cursor.Validate(); err != nil {
+		return nil, err
+	}
+
+	var (
+		vals [][]byte
+		err  error
+	)
+
+	switch {
+	case !cursor.Backward:
+		vals, err = r.db.getAll(ctx, `SELECT data FROM users WHERE id >= $1 ORDER BY id LIMIT $2`,
+			cursor.StartKey, cursor.Limit)
+	case cursor.StartKey == "":
+		vals, err = r.db.getAll(ctx, `SELECT data FROM (
+			SELECT id, data FROM users ORDER BY id DESC LIMIT $1
+		) AS page ORDER BY id`, cursor.Limit)
+	default:
+		vals, err = r.db.getAll(ctx, `SELECT data FROM (
+			SELECT id, data FROM users WHERE id < $1 ORDER BY id DESC LIMIT $2
+		) AS page ORDER BY id`, cursor.StartKey, cursor.Limit)
+	}
+
+	if err != nil {
+		return nil, err
+	}
+
+	return unmarshalUsers(vals)
+}
+
+// Iterate calls fn for every user, sorted by ID, reading them a page at
+// a time. It stops at the first error returned by fn.
+func (r userRepositoryReader) Iterate(ctx context.Context, fn func(user types.User) error) error {
+	return storage.IterateUsers(ctx, r, fn)
+}
+
+// GetByTelegramChatID retrieves the first user found by its Telegram chat ID.
+func (r userRepositoryReader) GetByTelegramChatID(ctx context.Context, chatID int64) (types.User, error) {
+	user := types.User{}
+	if chatID == 0 {
+		return user, storage.ErrEmptyTelegramChatID
+	}
+
+	val, err := r.db.get(ctx, `SELECT data FROM users WHERE telegram_chat_id = $1 ORDER BY id LIMIT 1`, chatID)
+	if err != nil {
+		return user, err
+	}
+
+	// Unmarshal the user data into the user struct.
+	if err := json.Unmarshal(val, &user); err != nil {
+		return user, err
+	}
+
+	return user, nil
+}
+
+// GetAllByTelegramChatID retrieves all users matching the given Telegram chat ID.
+func (r userRepositoryReader) GetAllByTelegramChatID(ctx context.Context, chatID int64) ([]types.User, error) {
+	if chatID == 0 {
+		return []types.User{}, storage.ErrEmptyTelegramChatID
+	}
+
+	vals, err := r.db.getAll(ctx, `SELECT data FROM users WHERE telegram_chat_id = $1 ORDER BY id`, chatID)
+	if err != nil {
+		return nil, err
+	}
+
+	return unmarshalUsers(vals)
+}
+
+// unmarshalUsers unmarshals the given user data into user structs.
+func unmarshalUsers(vals [][]byte) ([]types.User, error) {
+	users := []types.User{}
+
+	for _, val := range vals {
+		var user types.User
+		if err := json.Unmarshal(val, &user); err != nil {
+			return users, err
+		}
+
+		users = append(users, user)
+	}
+
+	return users, nil
+}
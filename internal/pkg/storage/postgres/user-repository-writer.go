@@ -0,0 +1,95 @@
+package postgres
+
+import (
+	"context"
+	"encoding/json"
+
+	"github.com/psyb0t/telegram-logger/internal/pkg/storage"
+	"github.com/psyb0t/telegram-logger/internal/pkg/types"
+)
+
+// userRepositoryWriter is a struct that implements the
+// storage.UserRepositoryWriter interface using a postgresDB instance.
+type userRepositoryWriter struct {
+	// db runs the statements on the underlying postgresDB
+	// instance, in a transaction if it holds one.
+	db txn
+}
+
+// newUserRepositoryWriter creates and returns
+// a new userRepositoryWriter instance.
+func newUserRepositoryWriter(db txn) storage.UserRepositoryWriter {
+	return userRepositoryWriter{db: db}
+}
+
+// Create stores a new user in the database.
+//
+// user is the user to be stored. It must have a non-empty ID field.
+func (r userRepositoryWriter) Create(ctx context.Context, user types.User) error {
+	if user.ID == "" {
+		return storage.ErrEmptyID
+	}
+
+	// Convert the user struct to a byte slice.
+	val, err := json.Marshal(user)
+	if err != nil {
+		return err
+	}
+
+	// Create the user replacing the existing one with the same ID
+	return r.db.exec(ctx, `INSERT INTO users (id, telegram_chat_id, data) VALUES ($1, $2, $3)
+		ON CONFLICT (id) DO UPDATE SET telegram_chat_id = excluded.telegram_chat_id, data = excluded.data`,
+		user.ID, user.TelegramChatID, val)
+}
+
+// Update replaces an existing user in the database.
+//
+// user is the user to be stored. It must have a non-empty ID field.
+func (r userRepositoryWriter) Update(ctx context.Context, user types.User) error {
+	if user.ID == "" {
+		return storage.ErrEmptyID
+	}
+
+	// Convert the user struct to a byte slice.
+	val, err := json.Marshal(user)
+	if err != nil {
+		return err
+	}
+
+	// Update the user
+	return r.db.update(ctx, `UPDATE users SET telegram_chat_id = $1, data = $2 WHERE id = $3`,
+		user.TelegramChatID, val, user.ID)
+}
+
+// Delete removes a user from the database by ID.
+func (r userRepositoryWriter) Delete(ctx context.Context, id string) error {
+	if id == "" {
+		return storage.ErrEmptyID
+	}
+
+	return r.db.exec(ctx, `DELETE FROM users WHERE id = $1`, id)
+}
+
+// DeleteAllByTelegramChatID removes all users from the database
+// matching the given Telegram chat ID.
+func (r userRepositoryWriter) DeleteAllByTelegramChatID(ctx context.Context, chatID int64) error {
+	if chatID == 0 {
+		return storage.ErrEmptyTelegramChatID
+	}
+
+	return r.db.exec(ctx, `DELETE FROM users WHERE telegram_chat_id = $1`, chatID)
+}
+
+// UpdateTelegramChatID replaces the Telegram chat ID of all of the users
+// matching oldChatID with newChatID in a single transaction.
+func (r userRepositoryWriter) UpdateTelegramChatID(ctx context.Context, oldChatID, newChatID int64) error {
+	if oldChatID == 0 || newChatID == 0 {
+		return storage.ErrEmptyTelegramChatID
+	}
+
+	// the chat ID is also replaced in the stored user data
+	// so that both are changed in a single statement
+	return r.db.exec(ctx, `UPDATE users
+		SET telegram_chat_id = $2, data = jsonb_set(data, '{telegramChatID}', to_jsonb($2::BIGINT))
+		WHERE telegram_chat_id = $1`, oldChatID, newChatID)
+}
@@ -0,0 +1,7 @@
+package redisdb
+
+import "errors"
+
+// ErrTooManyTxRetries is returned when a transaction keeps failing
+// because the keys it watches are changed by someone else.
+var ErrTooManyTxRetries = errors.New("too many transaction retries")
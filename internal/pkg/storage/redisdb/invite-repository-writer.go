@@ -0,0 +1,79 @@
+package redisdb
+
+import (
+	"context"
+	"encoding/json"
+
+	"github.com/psyb0t/telegram-logger/internal/pkg/storage"
+	"github.com/psyb0t/telegram-logger/internal/pkg/types"
+	"github.com/redis/go-redis/v9"
+)
+
+// inviteRepositoryWriter is a struct that implements the
+// storage.InviteRepositoryWriter interface using a redisDB instance.
+type inviteRepositoryWriter struct {
+	// db runs the commands on the underlying redisDB
+	// instance, in a transaction if it holds one.
+	db txn
+}
+
+// newInviteRepositoryWriter creates and returns
+// a new inviteRepositoryWriter instance.
+func newInviteRepositoryWriter(db txn) storage.InviteRepositoryWriter {
+	return inviteRepositoryWriter{db: db}
+}
+
+// Create stores a new invite in the database.
+//
+// invite is the invite to be stored. It must have a non-empty ID field.
+func (r inviteRepositoryWriter) Create(ctx context.Context, invite types.Invite) error {
+	if invite.ID == "" {
+		return storage.ErrEmptyID
+	}
+
+	// Convert the invite struct to a byte slice.
+	val, err := json.Marshal(invite)
+	if err != nil {
+		return err
+	}
+
+	// Create the invite
+	return r.db.write(func(c redis.Cmdable) error {
+		return c.HSet(ctx, keyInvites, invite.ID, val).Err()
+	})
+}
+
+// Update replaces an existing invite in the database.
+//
+// invite is the invite to be stored. It must have a non-empty ID field.
+func (r inviteRepositoryWriter) Update(ctx context.Context, invite types.Invite) error {
+	if invite.ID == "" {
+		return storage.ErrEmptyID
+	}
+
+	// Convert the invite struct to a byte slice.
+	val, err := json.Marshal(invite)
+	if err != nil {
+		return err
+	}
+
+	// Update the invite making sure it exists
+	return r.db.watch(ctx, func(tx *redis.Tx) error {
+		exists, err := tx.HExists(ctx, keyInvites, invite.ID).Result()
+		if err != nil {
+			return err
+		}
+
+		if !exists {
+			return storage.ErrNotFound
+		}
+
+		err = r.db.txPipelined(ctx, tx, func(pipe redis.Pipeliner) error {
+			pipe.HSet(ctx, keyInvites, invite.ID, val)
+
+			return nil
+		})
+
+		return err
+	}, keyInvites)
+}
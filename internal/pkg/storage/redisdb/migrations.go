@@ -0,0 +1,48 @@
+package redisdb
+
+import (
+	"context"
+	"errors"
+
+	"github.com/psyb0t/telegram-logger/internal/pkg/storage"
+	"github.com/redis/go-redis/v9"
+)
+
+// getMigrations returns the migrations of the database in order.
+// Redis has no schema and the keys are created as they're written so
+// the first migration only marks the layout described in the README.
+func getMigrations() []storage.Migration[*redisDB] {
+	return []storage.Migration[*redisDB]{
+		{
+			Version: 1,
+			Name:    "initial key layout",
+			Apply: func(context.Context, *redisDB) error {
+				return nil
+			},
+		},
+	}
+}
+
+// GetSchemaVersion returns the version of the last applied
+// migration or 0 if no migration was applied yet.
+func (db *redisDB) GetSchemaVersion(ctx context.Context) (int, error) {
+	version, err := db.client.Get(ctx, keySchemaVersion).Int()
+	if err != nil {
+		if errors.Is(err, redis.Nil) {
+			return 0, nil
+		}
+
+		return 0, err
+	}
+
+	return version, nil
+}
+
+// ApplyMigration applies the given migration and records its version.
+func (db *redisDB) ApplyMigration(ctx context.Context, m storage.Migration[*redisDB]) error {
+	if err := m.Apply(ctx, db); err != nil {
+		return err
+	}
+
+	return db.client.Set(ctx, keySchemaVersion, m.Version, 0).Err()
+}
@@ -0,0 +1,29 @@
+package redisdb
+
+import (
+	"context"
+	"strconv"
+	"testing"
+
+	"github.com/psyb0t/telegram-logger/internal/pkg/storage"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestRedisDB_migrate(t *testing.T) {
+	ctx := context.Background()
+
+	db, server := newTestRedisDB(t)
+	require.NoError(t, db.Close())
+
+	version, err := server.Get(keySchemaVersion)
+	require.NoError(t, err)
+	assert.Equal(t, strconv.Itoa(len(getMigrations())), version)
+
+	// pretend a newer version of the app migrated the database
+	require.NoError(t, server.Set(keySchemaVersion, "1000"))
+
+	db, err = New(ctx)
+	require.NoError(t, err)
+	assert.ErrorIs(t, db.Open(ctx, "redis://"+server.Addr()), storage.ErrSchemaVersionTooNew)
+}
@@ -0,0 +1,74 @@
+package redisdb
+
+import (
+	"context"
+	"testing"
+
+	"github.com/alicebob/miniredis/v2"
+	"github.com/psyb0t/telegram-logger/internal/pkg/storage"
+	"github.com/psyb0t/telegram-logger/internal/pkg/storage/storagetest"
+	"github.com/psyb0t/telegram-logger/internal/pkg/types"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func newTestRedisDB(t *testing.T) (storage.Storage, *miniredis.Miniredis) {
+	ctx := context.Background()
+
+	t.Helper()
+
+	server := miniredis.RunT(t)
+
+	db, err := New(ctx)
+	require.NoError(t, err)
+	require.NoError(t, db.Open(ctx, "redis://"+server.Addr()))
+
+	return db, server
+}
+
+func TestRedisDB(t *testing.T) {
+	storagetest.Run(t, func(t *testing.T) storage.Storage {
+		db, _ := newTestRedisDB(t)
+
+		return db
+	})
+}
+
+func TestRedisDB_chatIndex(t *testing.T) {
+	ctx := context.Background()
+
+	db, server := newTestRedisDB(t)
+
+	defer db.Close()
+
+	writer := db.GetUserRepositoryWriter()
+
+	require.NoError(t, writer.Create(ctx, types.User{ID: "a", TelegramChatID: 1}))
+	require.NoError(t, writer.Create(ctx, types.User{ID: "b", TelegramChatID: 1}))
+	require.NoError(t, writer.Update(ctx, types.User{ID: "b", TelegramChatID: 2}))
+
+	members, err := server.SMembers(getChatKey(1))
+	require.NoError(t, err)
+	assert.Equal(t, []string{"a"}, members)
+
+	members, err = server.SMembers(getChatKey(2))
+	require.NoError(t, err)
+	assert.Equal(t, []string{"b"}, members)
+
+	require.NoError(t, writer.Delete(ctx, "b"))
+	assert.False(t, server.Exists(getChatKey(2)))
+	assert.False(t, server.Exists(getUserKey("b")))
+
+	require.NoError(t, writer.UpdateTelegramChatID(ctx, 1, 3))
+	assert.False(t, server.Exists(getChatKey(1)))
+
+	members, err = server.SMembers(getChatKey(3))
+	require.NoError(t, err)
+	assert.Equal(t, []string{"a"}, members)
+	assert.Equal(t, "3", server.HGet(getUserKey("a"), userFieldTelegramChatID))
+
+	require.NoError(t, writer.DeleteAllByTelegramChatID(ctx, 3))
+	assert.False(t, server.Exists(getChatKey(3)))
+	assert.False(t, server.Exists(getUserKey("a")))
+	assert.False(t, server.Exists(keyUsers))
+}
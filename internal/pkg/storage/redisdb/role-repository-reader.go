@@ -0,0 +1,67 @@
+package redisdb
+
+import (
+	"context"
+	"encoding/json"
+	"strconv"
+
+	"github.com/psyb0t/telegram-logger/internal/pkg/storage"
+	"github.com/psyb0t/telegram-logger/internal/pkg/types"
+)
+
+// roleRepositoryReader is a struct that implements the
+// storage.RoleRepositoryReader interface using a redisDB instance.
+type roleRepositoryReader struct {
+	// db runs the commands on the underlying redisDB
+	// instance, in a transaction if it holds one.
+	db txn
+}
+
+// newRoleRepositoryReader creates and returns
+// a new roleRepositoryReader instance.
+func newRoleRepositoryReader(db txn) storage.RoleRepositoryReader {
+	return roleRepositoryReader{db: db}
+}
+
+// Get retrieves the role of a Telegram chat ID.
+func (r roleRepositoryReader) Get(ctx context.Context, chatID int64) (types.ChatRole, error) {
+	chatRole := types.ChatRole{}
+
+	if chatID == 0 {
+		return chatRole, storage.ErrEmptyTelegramChatID
+	}
+
+	val, err := r.db.hget(ctx, keyRoles, strconv.FormatInt(chatID, 10))
+	if err != nil {
+		return chatRole, err
+	}
+
+	// Unmarshal the role data into the chat role struct.
+	if err := json.Unmarshal(val, &chatRole); err != nil {
+		return chatRole, err
+	}
+
+	return chatRole, nil
+}
+
+// GetAll retrieves all chat roles from the database.
+func (r roleRepositoryReader) GetAll(ctx context.Context) ([]types.ChatRole, error) {
+	chatRoles := []types.ChatRole{}
+
+	vals, err := r.db.hvals(ctx, keyRoles)
+	if err != nil {
+		return nil, err
+	}
+
+	for _, val := range vals {
+		// Unmarshal the role data into a chat role struct.
+		var chatRole types.ChatRole
+		if err := json.Unmarshal(val, &chatRole); err != nil {
+			return chatRoles, err
+		}
+
+		chatRoles = append(chatRoles, chatRole)
+	}
+
+	return chatRoles, nil
+}
@@ -0,0 +1,58 @@
+package redisdb
+
+import (
+	"context"
+	"encoding/json"
+	"strconv"
+
+	"github.com/psyb0t/telegram-logger/internal/pkg/storage"
+	"github.com/psyb0t/telegram-logger/internal/pkg/types"
+	"github.com/redis/go-redis/v9"
+)
+
+// roleRepositoryWriter is a struct that implements the
+// storage.RoleRepositoryWriter interface using a redisDB instance.
+type roleRepositoryWriter struct {
+	// db runs the commands on the underlying redisDB
+	// instance, in a transaction if it holds one.
+	db txn
+}
+
+// newRoleRepositoryWriter creates and returns
+// a new roleRepositoryWriter instance.
+func newRoleRepositoryWriter(db txn) storage.RoleRepositoryWriter {
+	return roleRepositoryWriter{db: db}
+}
+
+// Set stores the role of a Telegram chat ID replacing the existing one.
+func (r roleRepositoryWriter) Set(ctx context.Context, chatRole types.ChatRole) error {
+	if chatRole.TelegramChatID == 0 {
+		return storage.ErrEmptyTelegramChatID
+	}
+
+	if chatRole.Role == "" {
+		return storage.ErrEmptyRole
+	}
+
+	// Convert the chat role struct to a byte slice.
+	val, err := json.Marshal(chatRole)
+	if err != nil {
+		return err
+	}
+
+	// Store the role
+	return r.db.write(func(c redis.Cmdable) error {
+		return c.HSet(ctx, keyRoles, strconv.FormatInt(chatRole.TelegramChatID, 10), val).Err()
+	})
+}
+
+// Delete removes the role of a Telegram chat ID from the database.
+func (r roleRepositoryWriter) Delete(ctx context.Context, chatID int64) error {
+	if chatID == 0 {
+		return storage.ErrEmptyTelegramChatID
+	}
+
+	return r.db.write(func(c redis.Cmdable) error {
+		return c.HDel(ctx, keyRoles, strconv.FormatInt(chatID, 10)).Err()
+	})
+}
@@ -0,0 +1,45 @@
+package redisdb
+
+import (
+	"context"
+
+	"github.com/psyb0t/telegram-logger/internal/pkg/storage"
+)
+
+// settingRepositoryReader is a struct that implements the
+// storage.SettingRepositoryReader interface using a redisDB instance.
+type settingRepositoryReader struct {
+	// db runs the commands on the underlying redisDB
+	// instance, in a transaction if it holds one.
+	db txn
+}
+
+// newSettingRepositoryReader creates and returns
+// a new settingRepositoryReader instance.
+func newSettingRepositoryReader(db txn) storage.SettingRepositoryReader {
+	return settingRepositoryReader{db: db}
+}
+
+// Get retrieves the value of a setting by key.
+func (r settingRepositoryReader) Get(ctx context.Context, key string) (string, error) {
+	if key == "" {
+		return "", storage.ErrEmptyKey
+	}
+
+	val, err := r.db.hget(ctx, keySettings, key)
+	if err != nil {
+		return "", err
+	}
+
+	return string(val), nil
+}
+
+// GetAll retrieves all settings from the database by key.
+func (r settingRepositoryReader) GetAll(ctx context.Context) (map[string]string, error) {
+	c, err := r.db.reader(ctx, keySettings)
+	if err != nil {
+		return nil, err
+	}
+
+	return c.HGetAll(ctx, keySettings).Result()
+}
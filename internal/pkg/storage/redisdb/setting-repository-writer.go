@@ -0,0 +1,33 @@
+package redisdb
+
+import (
+	"context"
+	"github.com/psyb0t/telegram-logger/internal/pkg/storage"
+	"github.com/redis/go-redis/v9"
+)
+
+// settingRepositoryWriter is a struct that implements the
+// storage.SettingRepositoryWriter interface using a redisDB instance.
+type settingRepositoryWriter struct {
+	// db runs the commands on the underlying redisDB
+	// instance, in a transaction if it holds one.
+	db txn
+}
+
+// newSettingRepositoryWriter creates and returns
+// a new settingRepositoryWriter instance.
+func newSettingRepositoryWriter(db txn) storage.SettingRepositoryWriter {
+	return settingRepositoryWriter{db: db}
+}
+
+// Set stores the value of a setting replacing the existing one.
+func (r settingRepositoryWriter) Set(ctx context.Context, key, value string) error {
+	if key == "" {
+		return storage.ErrEmptyKey
+	}
+
+	// Store the setting
+	return r.db.write(func(c redis.Cmdable) error {
+		return c.HSet(ctx, keySettings, key, value).Err()
+	})
+}
@@ -0,0 +1,237 @@
+package redisdb
+
+import (
+	"context"
+	"errors"
+	"sort"
+
+	"github.com/psyb0t/telegram-logger/internal/pkg/storage"
+	"github.com/redis/go-redis/v9"
+)
+
+// txn is what the repositories run their commands through. If it holds
+// a transaction, the keys that are read are watched and the writes are
+// queued in its pipeline. Otherwise each operation runs on its own.
+type txn struct {
+	db   *redisDB
+	tx   *redis.Tx
+	pipe redis.Pipeliner
+}
+
+// reader returns the connection to read from, watching the
+// given keys first if there's a transaction.
+func (t txn) reader(ctx context.Context, keys ...string) (redis.Cmdable, error) {
+	if t.tx == nil {
+		return t.db.client, nil
+	}
+
+	if err := t.tx.Watch(ctx, keys...).Err(); err != nil {
+		return nil, err
+	}
+
+	return t.tx, nil
+}
+
+// watch runs the given function in the transaction, watching the given
+// keys too, or in a new optimistic transaction watching them.
+func (t txn) watch(ctx context.Context, fn func(tx *redis.Tx) error, keys ...string) error {
+	if t.tx == nil {
+		return t.db.watch(ctx, fn, keys...)
+	}
+
+	if err := t.tx.Watch(ctx, keys...).Err(); err != nil {
+		return err
+	}
+
+	return fn(t.tx)
+}
+
+// txPipelined queues the writes of the given function in the pipeline
+// of the transaction or runs them in a MULTI/EXEC of their own on c.
+func (t txn) txPipelined(ctx context.Context, c redis.Cmdable, fn func(pipe redis.Pipeliner) error) error {
+	if t.pipe != nil {
+		return fn(t.pipe)
+	}
+
+	_, err := c.TxPipelined(ctx, fn)
+
+	return err
+}
+
+// write runs the single write of the given function on the connection
+// or queues it in the pipeline of the transaction.
+func (t txn) write(fn func(c redis.Cmdable) error) error {
+	if t.pipe != nil {
+		return fn(t.pipe)
+	}
+
+	return fn(t.db.client)
+}
+
+// hget retrieves the value of a field of a hash.
+func (t txn) hget(ctx context.Context, key, field string) ([]byte, error) {
+	c, err := t.reader(ctx, key)
+	if err != nil {
+		return nil, err
+	}
+
+	val, err := c.HGet(ctx, key, field).Bytes()
+	if err != nil {
+		// If the key or the field is not found, return an error.
+		if errors.Is(err, redis.Nil) {
+			return nil, storage.ErrNotFound
+		}
+
+		return nil, err
+	}
+
+	return val, nil
+}
+
+// hvals retrieves the values of all of the fields of a hash
+// sorted by field so that the order is stable.
+func (t txn) hvals(ctx context.Context, key string) ([][]byte, error) {
+	c, err := t.reader(ctx, key)
+	if err != nil {
+		return nil, err
+	}
+
+	fieldVals, err := c.HGetAll(ctx, key).Result()
+	if err != nil {
+		return nil, err
+	}
+
+	fields := make([]string, 0, len(fieldVals))
+	for field := range fieldVals {
+		fields = append(fields, field)
+	}
+
+	sort.Strings(fields)
+
+	result := make([][]byte, 0, len(fields))
+	for _, field := range fields {
+		result = append(result, []byte(fieldVals[field]))
+	}
+
+	return result, nil
+}
+
+// getUsersData retrieves the JSON of the users whose IDs are members of
+// the given set, sorted by ID. Users that are gone are skipped.
+func (t txn) getUsersData(ctx context.Context, setKey string) ([][]byte, error) {
+	ids, err := t.getSortedMembers(ctx, setKey)
+	if err != nil {
+		return nil, err
+	}
+
+	return t.getUsersDataByIDs(ctx, ids)
+}
+
+// getSortedMembers retrieves the members of the given set sorted.
+func (t txn) getSortedMembers(ctx context.Context, setKey string) ([]string, error) {
+	c, err := t.reader(ctx, setKey)
+	if err != nil {
+		return nil, err
+	}
+
+	ids, err := c.SMembers(ctx, setKey).Result()
+	if err != nil {
+		return nil, err
+	}
+
+	sort.Strings(ids)
+
+	return ids, nil
+}
+
+// getUsersDataByIDs retrieves the JSON of the users with the
+// given IDs in the same order. Users that are gone are skipped.
+func (t txn) getUsersDataByIDs(ctx context.Context, ids []string) ([][]byte, error) {
+	if len(ids) == 0 {
+		return [][]byte{}, nil
+	}
+
+	keys := make([]string, 0, len(ids))
+	for _, id := range ids {
+		keys = append(keys, getUserKey(id))
+	}
+
+	c, err := t.reader(ctx, keys...)
+	if err != nil {
+		return nil, err
+	}
+
+	cmds := make([]*redis.StringCmd, 0, len(keys))
+
+	if _, err := c.Pipelined(ctx, func(pipe redis.Pipeliner) error {
+		for _, key := range keys {
+			cmds = append(cmds, pipe.HGet(ctx, key, userFieldData))
+		}
+
+		return nil
+	}); err != nil && !errors.Is(err, redis.Nil) {
+		return nil, err
+	}
+
+	result := make([][]byte, 0, len(cmds))
+
+	for _, cmd := range cmds {
+		val, err := cmd.Bytes()
+		if err != nil {
+			if errors.Is(err, redis.Nil) {
+				continue
+			}
+
+			return nil, err
+		}
+
+		result = append(result, val)
+	}
+
+	return result, nil
+}
+
+// txRepositories hands out the repositories of a transaction.
+type txRepositories struct {
+	db txn
+}
+
+// GetUserRepositoryReader returns a repository for reading user data in the transaction.
+func (r txRepositories) GetUserRepositoryReader() storage.UserRepositoryReader {
+	return newUserRepositoryReader(r.db)
+}
+
+// GetUserRepositoryWriter returns a repository for writing user data in the transaction.
+func (r txRepositories) GetUserRepositoryWriter() storage.UserRepositoryWriter {
+	return newUserRepositoryWriter(r.db)
+}
+
+// GetRoleRepositoryReader returns a repository for reading chat role data in the transaction.
+func (r txRepositories) GetRoleRepositoryReader() storage.RoleRepositoryReader {
+	return newRoleRepositoryReader(r.db)
+}
+
+// GetRoleRepositoryWriter returns a repository for writing chat role data in the transaction.
+func (r txRepositories) GetRoleRepositoryWriter() storage.RoleRepositoryWriter {
+	return newRoleRepositoryWriter(r.db)
+}
+
+// GetInviteRepositoryReader returns a repository for reading invite data in the transaction.
+func (r txRepositories) GetInviteRepositoryReader() storage.InviteRepositoryReader {
+	return newInviteRepositoryReader(r.db)
+}
+
+// GetInviteRepositoryWriter returns a repository for writing invite data in the transaction.
+func (r txRepositories) GetInviteRepositoryWriter() storage.InviteRepositoryWriter {
+	return newInviteRepositoryWriter(r.db)
+}
+
+// GetSettingRepositoryReader returns a repository for reading settings in the transaction.
+func (r txRepositories) GetSettingRepositoryReader() storage.SettingRepositoryReader {
+	return newSettingRepositoryReader(r.db)
+}
+
+// GetSettingRepositoryWriter returns a repository for writing settings in the transaction.
+func (r txRepositories) GetSettingRepositoryWriter() storage.SettingRepositoryWriter {
+	return newSettingRepositoryWriter(r.db)
+}
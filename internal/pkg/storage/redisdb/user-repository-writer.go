@@ -0,0 +1,212 @@
+package redisdb
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+
+	"github.com/psyb0t/telegram-logger/internal/pkg/storage"
+	"github.com/psyb0t/telegram-logger/internal/pkg/types"
+	"github.com/redis/go-redis/v9"
+)
+
+// userRepositoryWriter is a struct that implements the
+// storage.UserRepositoryWriter interface using a redisDB instance.
+type userRepositoryWriter struct {
+	// db runs the commands on the underlying redisDB
+	// instance, in a transaction if it holds one.
+	db txn
+}
+
+// newUserRepositoryWriter creates and returns
+// a new userRepositoryWriter instance.
+func newUserRepositoryWriter(db txn) storage.UserRepositoryWriter {
+	return userRepositoryWriter{db: db}
+}
+
+// Create stores a new user in the database.
+//
+// user is the user to be stored. It must have a non-empty ID field.
+func (r userRepositoryWriter) Create(ctx context.Context, user types.User) error {
+	if user.ID == "" {
+		return storage.ErrEmptyID
+	}
+
+	return r.set(ctx, user, false)
+}
+
+// Update replaces an existing user in the database.
+//
+// user is the user to be stored. It must have a non-empty ID field.
+func (r userRepositoryWriter) Update(ctx context.Context, user types.User) error {
+	if user.ID == "" {
+		return storage.ErrEmptyID
+	}
+
+	return r.set(ctx, user, true)
+}
+
+// set stores the given user and moves its ID to the set of its Telegram
+// chat if the chat changed. If mustExist is true and the user
+// doesn't exist, storage.ErrNotFound is returned.
+func (r userRepositoryWriter) set(ctx context.Context, user types.User, mustExist bool) error {
+	// Convert the user struct to a byte slice.
+	val, err := json.Marshal(user)
+	if err != nil {
+		return err
+	}
+
+	key := getUserKey(user.ID)
+
+	return r.db.watch(ctx, func(tx *redis.Tx) error {
+		oldChatID, err := tx.HGet(ctx, key, userFieldTelegramChatID).Int64()
+		if err != nil {
+			if !errors.Is(err, redis.Nil) {
+				return err
+			}
+
+			if mustExist {
+				return storage.ErrNotFound
+			}
+		}
+
+		err = r.db.txPipelined(ctx, tx, func(pipe redis.Pipeliner) error {
+			pipe.HSet(ctx, key, userFieldTelegramChatID, user.TelegramChatID, userFieldData, val)
+			pipe.SAdd(ctx, keyUsers, user.ID)
+
+			if oldChatID != 0 && oldChatID != user.TelegramChatID {
+				pipe.SRem(ctx, getChatKey(oldChatID), user.ID)
+			}
+
+			pipe.SAdd(ctx, getChatKey(user.TelegramChatID), user.ID)
+
+			return nil
+		})
+
+		return err
+	}, key)
+}
+
+// Delete removes a user from the database by ID.
+func (r userRepositoryWriter) Delete(ctx context.Context, id string) error {
+	if id == "" {
+		return storage.ErrEmptyID
+	}
+
+	key := getUserKey(id)
+
+	return r.db.watch(ctx, func(tx *redis.Tx) error {
+		chatID, err := tx.HGet(ctx, key, userFieldTelegramChatID).Int64()
+		if err != nil && !errors.Is(err, redis.Nil) {
+			return err
+		}
+
+		err = r.db.txPipelined(ctx, tx, func(pipe redis.Pipeliner) error {
+			pipe.Del(ctx, key)
+			pipe.SRem(ctx, keyUsers, id)
+
+			if chatID != 0 {
+				pipe.SRem(ctx, getChatKey(chatID), id)
+			}
+
+			return nil
+		})
+
+		return err
+	}, key)
+}
+
+// DeleteAllByTelegramChatID removes all users from the database
+// matching the given Telegram chat ID.
+func (r userRepositoryWriter) DeleteAllByTelegramChatID(ctx context.Context, chatID int64) error {
+	if chatID == 0 {
+		return storage.ErrEmptyTelegramChatID
+	}
+
+	chatKey := getChatKey(chatID)
+
+	return r.db.watch(ctx, func(tx *redis.Tx) error {
+		ids, err := tx.SMembers(ctx, chatKey).Result()
+		if err != nil {
+			return err
+		}
+
+		err = r.db.txPipelined(ctx, tx, func(pipe redis.Pipeliner) error {
+			for _, id := range ids {
+				pipe.Del(ctx, getUserKey(id))
+				pipe.SRem(ctx, keyUsers, id)
+			}
+
+			pipe.Del(ctx, chatKey)
+
+			return nil
+		})
+
+		return err
+	}, chatKey)
+}
+
+// UpdateTelegramChatID replaces the Telegram chat ID of all of the users
+// matching oldChatID with newChatID in a single transaction.
+func (r userRepositoryWriter) UpdateTelegramChatID(ctx context.Context, oldChatID, newChatID int64) error {
+	if oldChatID == 0 || newChatID == 0 {
+		return storage.ErrEmptyTelegramChatID
+	}
+
+	oldChatKey := getChatKey(oldChatID)
+	newChatKey := getChatKey(newChatID)
+
+	return r.db.watch(ctx, func(tx *redis.Tx) error {
+		ids, err := tx.SMembers(ctx, oldChatKey).Result()
+		if err != nil {
+			return err
+		}
+
+		users := make([]types.User, 0, len(ids))
+
+		for _, id := range ids {
+			key := getUserKey(id)
+
+			// the users are changed as well so they're watched too
+			if err := tx.Watch(ctx, key).Err(); err != nil {
+				return err
+			}
+
+			val, err := tx.HGet(ctx, key, userFieldData).Bytes()
+			if err != nil {
+				if errors.Is(err, redis.Nil) {
+					continue
+				}
+
+				return err
+			}
+
+			var user types.User
+			if err := json.Unmarshal(val, &user); err != nil {
+				return err
+			}
+
+			users = append(users, user)
+		}
+
+		err = r.db.txPipelined(ctx, tx, func(pipe redis.Pipeliner) error {
+			for _, user := range users {
+				user.TelegramChatID = newChatID
+
+				val, err := json.Marshal(user)
+				if err != nil {
+					return err
+				}
+
+				pipe.HSet(ctx, getUserKey(user.ID), userFieldTelegramChatID, newChatID, userFieldData, val)
+				pipe.SAdd(ctx, newChatKey, user.ID)
+			}
+
+			pipe.Del(ctx, oldChatKey)
+
+			return nil
+		})
+
+		return err
+	}, oldChatKey, newChatKey)
+}
@@ -0,0 +1,11 @@
+package redisdb
+
+import "strconv"
+
+func getUserKey(userID string) string {
+	return prefixUserKey + userID
+}
+
+func getChatKey(chatID int64) string {
+	return prefixChatKey + strconv.FormatInt(chatID, 10) + ":users"
+}
@@ -0,0 +1,15 @@
+package redisdb
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestGetUserKey(t *testing.T) {
+	assert.Equal(t, "telegram-logger:user:abcdef", getUserKey("abcdef"))
+}
+
+func TestGetChatKey(t *testing.T) {
+	assert.Equal(t, "telegram-logger:chat:-100123:users", getChatKey(-100123))
+}
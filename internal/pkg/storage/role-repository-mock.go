@@ -0,0 +1,42 @@
+package storage
+
+import (
+	"context"
+
+	"github.com/psyb0t/telegram-logger/internal/pkg/types"
+	"github.com/stretchr/testify/mock"
+)
+
+// RoleRepositoryReaderMock is a mock implementation of RoleRepositoryReader.
+type RoleRepositoryReaderMock struct {
+	mock.Mock
+}
+
+// Get retrieves the role of a Telegram chat ID.
+func (r *RoleRepositoryReaderMock) Get(_ context.Context, chatID int64) (types.ChatRole, error) {
+	args := r.Called(chatID)
+	return args.Get(0).(types.ChatRole), args.Error(1)
+}
+
+// GetAll retrieves all chat roles from the database.
+func (r *RoleRepositoryReaderMock) GetAll(_ context.Context) ([]types.ChatRole, error) {
+	args := r.Called()
+	return args.Get(0).([]types.ChatRole), args.Error(1)
+}
+
+// RoleRepositoryWriterMock is a mock implementation of RoleRepositoryWriter.
+type RoleRepositoryWriterMock struct {
+	mock.Mock
+}
+
+// Set stores the role of a Telegram chat ID replacing the existing one.
+func (r *RoleRepositoryWriterMock) Set(_ context.Context, chatRole types.ChatRole) error {
+	args := r.Called(chatRole)
+	return args.Error(0)
+}
+
+// Delete removes the role of a Telegram chat ID from the database.
+func (r *RoleRepositoryWriterMock) Delete(_ context.Context, chatID int64) error {
+	args := r.Called(chatID)
+	return args.Error(0)
+}
@@ -0,0 +1,27 @@
+package storage
+
+import (
+	"context"
+
+	"github.com/psyb0t/telegram-logger/internal/pkg/types"
+)
+
+// RoleRepositoryReader is an interface for reading
+// chat role data stored in the database.
+type RoleRepositoryReader interface {
+	// Get retrieves the role of a Telegram chat ID.
+	Get(ctx context.Context, chatID int64) (types.ChatRole, error)
+
+	// GetAll retrieves all chat roles from the database.
+	GetAll(ctx context.Context) ([]types.ChatRole, error)
+}
+
+// RoleRepositoryWriter is an interface for writing
+// chat role data stored in the database.
+type RoleRepositoryWriter interface {
+	// Set stores the role of a Telegram chat ID replacing the existing one.
+	Set(ctx context.Context, chatRole types.ChatRole) error
+
+	// Delete removes the role of a Telegram chat ID from the database.
+	Delete(ctx context.Context, chatID int64) error
+}
@@ -0,0 +1,35 @@
+package storage
+
+import (
+	"context"
+
+	"github.com/stretchr/testify/mock"
+)
+
+// SettingRepositoryReaderMock is a mock implementation of SettingRepositoryReader.
+type SettingRepositoryReaderMock struct {
+	mock.Mock
+}
+
+// Get retrieves the value of a setting by key.
+func (r *SettingRepositoryReaderMock) Get(_ context.Context, key string) (string, error) {
+	args := r.Called(key)
+	return args.String(0), args.Error(1)
+}
+
+// GetAll retrieves all settings from the database by key.
+func (r *SettingRepositoryReaderMock) GetAll(_ context.Context) (map[string]string, error) {
+	args := r.Called()
+	return args.Get(0).(map[string]string), args.Error(1)
+}
+
+// SettingRepositoryWriterMock is a mock implementation of SettingRepositoryWriter.
+type SettingRepositoryWriterMock struct {
+	mock.Mock
+}
+
+// Set stores the value of a setting replacing the existing one.
+func (r *SettingRepositoryWriterMock) Set(_ context.Context, key, value string) error {
+	args := r.Called(key, value)
+	return args.Error(0)
+}
@@ -0,0 +1,20 @@
+package storage
+
+import "context"
+
+// SettingRepositoryReader is an interface for reading
+// key/value settings stored in the database.
+type SettingRepositoryReader interface {
+	// Get retrieves the value of a setting by key.
+	Get(ctx context.Context, key string) (string, error)
+
+	// GetAll retrieves all settings from the database by key.
+	GetAll(ctx context.Context) (map[string]string, error)
+}
+
+// SettingRepositoryWriter is an interface for writing
+// key/value settings stored in the database.
+type SettingRepositoryWriter interface {
+	// Set stores the value of a setting replacing the existing one.
+	Set(ctx context.Context, key, value string) error
+}
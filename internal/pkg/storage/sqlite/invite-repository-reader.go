@@ -0,0 +1,66 @@
+package sqlite
+
+import (
+	"context"
+	"encoding/json"
+
+	"github.com/psyb0t/telegram-logger/internal/pkg/storage"
+	"github.com/psyb0t/telegram-logger/internal/pkg/types"
+)
+
+// inviteRepositoryReader is a struct that implements the
+// storage.InviteRepositoryReader interface using a sqliteDB instance.
+type inviteRepositoryReader struct {
+	// db runs the statements on the underlying sqliteDB
+	// instance, in a transaction if it holds one.
+	db txn
+}
+
+// newInviteRepositoryReader creates and returns
+// a new inviteRepositoryReader instance.
+func newInviteRepositoryReader(db txn) storage.InviteRepositoryReader {
+	return inviteRepositoryReader{db: db}
+}
+
+// Get retrieves an invite by ID.
+func (r inviteRepositoryReader) Get(ctx context.Context, id string) (types.Invite, error) {
+	invite := types.Invite{}
+
+	if id == "" {
+		return invite, storage.ErrEmptyID
+	}
+
+	val, err := r.db.get(ctx, `SELECT data FROM invites WHERE id = ?`, id)
+	if err != nil {
+		return invite, err
+	}
+
+	// Unmarshal the invite data into the invite struct.
+	if err := json.Unmarshal(val, &invite); err != nil {
+		return invite, err
+	}
+
+	return invite, nil
+}
+
+// GetAll retrieves all invites from the database.
+func (r inviteRepositoryReader) GetAll(ctx context.Context) ([]types.Invite, error) {
+	invites := []types.Invite{}
+
+	vals, err := r.db.getAll(ctx, `SELECT data FROM invites ORDER BY id`)
+	if err != nil {
+		return nil, err
+	}
+
+	for _, val := range vals {
+		// Unmarshal the invite data into an invite struct.
+		var invite types.Invite
+		if err := json.Unmarshal(val, &invite); err != nil {
+			return invites, err
+		}
+
+		invites = append(invites, invite)
+	}
+
+	return invites, nil
+}
@@ -0,0 +1,59 @@
+package sqlite
+
+import (
+	"context"
+	"encoding/json"
+
+	"github.com/psyb0t/telegram-logger/internal/pkg/storage"
+	"github.com/psyb0t/telegram-logger/internal/pkg/types"
+)
+
+// inviteRepositoryWriter is a struct that implements the
+// storage.InviteRepositoryWriter interface using a sqliteDB instance.
+type inviteRepositoryWriter struct {
+	// db runs the statements on the underlying sqliteDB
+	// instance, in a transaction if it holds one.
+	db txn
+}
+
+// newInviteRepositoryWriter creates and returns
+// a new inviteRepositoryWriter instance.
+func newInviteRepositoryWriter(db txn) storage.InviteRepositoryWriter {
+	return inviteRepositoryWriter{db: db}
+}
+
+// Create stores a new invite in the database.
+//
+// invite is the invite to be stored. It must have a non-empty ID field.
+func (r inviteRepositoryWriter) Create(ctx context.Context, invite types.Invite) error {
+	if invite.ID == "" {
+		return storage.ErrEmptyID
+	}
+
+	// Convert the invite struct to a byte slice.
+	val, err := json.Marshal(invite)
+	if err != nil {
+		return err
+	}
+
+	// Create the invite
+	return r.db.exec(ctx, `INSERT OR REPLACE INTO invites (id, data) VALUES (?, ?)`, invite.ID, val)
+}
+
+// Update replaces an existing invite in the database.
+//
+// invite is the invite to be stored. It must have a non-empty ID field.
+func (r inviteRepositoryWriter) Update(ctx context.Context, invite types.Invite) error {
+	if invite.ID == "" {
+		return storage.ErrEmptyID
+	}
+
+	// Convert the invite struct to a byte slice.
+	val, err := json.Marshal(invite)
+	if err != nil {
+		return err
+	}
+
+	// Update the invite
+	return r.db.update(ctx, `UPDATE invites SET data = ? WHERE id = ?`, val, invite.ID)
+}
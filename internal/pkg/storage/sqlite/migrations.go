@@ -0,0 +1,82 @@
+package sqlite
+
+import (
+	"context"
+	"database/sql"
+	"fmt"
+
+	"github.com/psyb0t/telegram-logger/internal/pkg/storage"
+)
+
+// getMigrations returns the migrations of the database in order.
+func getMigrations() []storage.Migration[*sql.Tx] {
+	return []storage.Migration[*sql.Tx]{
+		{
+			Version: 1,
+			Name:    "create tables",
+			// The entities are stored as JSON in the data column so that
+			// adding fields doesn't need a migration. The columns that
+			// are looked up by are stored next to it. The tables may
+			// exist already since they used to be created on every Open.
+			Apply: execAll(
+				`CREATE TABLE IF NOT EXISTS users (
+					id TEXT PRIMARY KEY,
+					telegram_chat_id INTEGER NOT NULL,
+					data TEXT NOT NULL
+				)`,
+				`CREATE INDEX IF NOT EXISTS users_telegram_chat_id ON users (telegram_chat_id)`,
+				`CREATE TABLE IF NOT EXISTS roles (
+					telegram_chat_id INTEGER PRIMARY KEY,
+					data TEXT NOT NULL
+				)`,
+				`CREATE TABLE IF NOT EXISTS invites (
+					id TEXT PRIMARY KEY,
+					data TEXT NOT NULL
+				)`,
+				`CREATE TABLE IF NOT EXISTS settings (
+					key TEXT PRIMARY KEY,
+					value TEXT NOT NULL
+				)`,
+			),
+		},
+	}
+}
+
+// execAll returns a migration function running the given statements.
+func execAll(queries ...string) func(ctx context.Context, tx *sql.Tx) error {
+	return func(ctx context.Context, tx *sql.Tx) error {
+		for _, query := range queries {
+			if _, err := tx.ExecContext(ctx, query); err != nil {
+				return err
+			}
+		}
+
+		return nil
+	}
+}
+
+// GetSchemaVersion returns the version of the last applied
+// migration or 0 if no migration was applied yet. It's kept
+// in the user_version field of the database header.
+func (db *sqliteDB) GetSchemaVersion(ctx context.Context) (int, error) {
+	var version int
+
+	err := db.db.QueryRowContext(ctx, `PRAGMA user_version`).Scan(&version)
+
+	return version, err
+}
+
+// ApplyMigration applies the given migration and records
+// its version in the same transaction.
+func (db *sqliteDB) ApplyMigration(ctx context.Context, m storage.Migration[*sql.Tx]) error {
+	return db.inTx(ctx, func(tx *sql.Tx) error {
+		if err := m.Apply(ctx, tx); err != nil {
+			return err
+		}
+
+		// pragmas can't take parameters
+		_, err := tx.ExecContext(ctx, fmt.Sprintf(`PRAGMA user_version = %d`, m.Version))
+
+		return err
+	})
+}
@@ -0,0 +1,35 @@
+package sqlite
+
+import (
+	"context"
+	"path/filepath"
+	"testing"
+
+	"github.com/psyb0t/telegram-logger/internal/pkg/storage"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestSQLite_migrate(t *testing.T) {
+	ctx := context.Background()
+
+	dsn := filepath.Join(t.TempDir(), "db.sqlite")
+
+	db, err := New(ctx)
+	require.NoError(t, err)
+	require.NoError(t, db.Open(ctx, dsn))
+
+	sdb := db.(*sqliteDB) //nolint:forcetypeassert
+
+	version, err := sdb.GetSchemaVersion(ctx)
+	require.NoError(t, err)
+	assert.Equal(t, len(getMigrations()), version)
+
+	// pretend a newer version of the app migrated the database
+	require.NoError(t, txn{db: sdb}.exec(ctx, `PRAGMA user_version = 1000`))
+	require.NoError(t, db.Close())
+
+	db, err = New(ctx)
+	require.NoError(t, err)
+	assert.ErrorIs(t, db.Open(ctx, dsn), storage.ErrSchemaVersionTooNew)
+}
@@ -0,0 +1,53 @@
+package sqlite
+
+import (
+	"context"
+	"encoding/json"
+
+	"github.com/psyb0t/telegram-logger/internal/pkg/storage"
+	"github.com/psyb0t/telegram-logger/internal/pkg/types"
+)
+
+// roleRepositoryWriter is a struct that implements the
+// storage.RoleRepositoryWriter interface using a sqliteDB instance.
+type roleRepositoryWriter struct {
+	// db runs the statements on the underlying sqliteDB
+	// instance, in a transaction if it holds one.
+	db txn
+}
+
+// newRoleRepositoryWriter creates and returns
+// a new roleRepositoryWriter instance.
+func newRoleRepositoryWriter(db txn) storage.RoleRepositoryWriter {
+	return roleRepositoryWriter{db: db}
+}
+
+// Set stores the role of a Telegram chat ID replacing the existing one.
+func (r roleRepositoryWriter) Set(ctx context.Context, chatRole types.ChatRole) error {
+	if chatRole.TelegramChatID == 0 {
+		return storage.ErrEmptyTelegramChatID
+	}
+
+	if chatRole.Role == "" {
+		return storage.ErrEmptyRole
+	}
+
+	// Convert the chat role struct to a byte slice.
+	val, err := json.Marshal(chatRole)
+	if err != nil {
+		return err
+	}
+
+	// Store the role
+	return r.db.exec(ctx, `INSERT OR REPLACE INTO roles (telegram_chat_id, data) VALUES (?, ?)`,
+		chatRole.TelegramChatID, val)
+}
+
+// Delete removes the role of a Telegram chat ID from the database.
+func (r roleRepositoryWriter) Delete(ctx context.Context, chatID int64) error {
+	if chatID == 0 {
+		return storage.ErrEmptyTelegramChatID
+	}
+
+	return r.db.exec(ctx, `DELETE FROM roles WHERE telegram_chat_id = ?`, chatID)
+}
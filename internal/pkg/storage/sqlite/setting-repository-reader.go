@@ -0,0 +1,57 @@
+package sqlite
+
+import (
+	"context"
+
+	"github.com/psyb0t/telegram-logger/internal/pkg/storage"
+)
+
+// settingRepositoryReader is a struct that implements the
+// storage.SettingRepositoryReader interface using a sqliteDB instance.
+type settingRepositoryReader struct {
+	// db runs the statements on the underlying sqliteDB
+	// instance, in a transaction if it holds one.
+	db txn
+}
+
+// newSettingRepositoryReader creates and returns
+// a new settingRepositoryReader instance.
+func newSettingRepositoryReader(db txn) storage.SettingRepositoryReader {
+	return settingRepositoryReader{db: db}
+}
+
+// Get retrieves the value of a setting by key.
+func (r settingRepositoryReader) Get(ctx context.Context, key string) (string, error) {
+	if key == "" {
+		return "", storage.ErrEmptyKey
+	}
+
+	val, err := r.db.get(ctx, `SELECT value FROM settings WHERE key = ?`, key)
+	if err != nil {
+		return "", err
+	}
+
+	return string(val), nil
+}
+
+// GetAll retrieves all settings from the database by key.
+func (r settingRepositoryReader) GetAll(ctx context.Context) (map[string]string, error) {
+	settings := map[string]string{}
+
+	rows, err := r.db.querier().QueryContext(ctx, `SELECT key, value FROM settings`)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	for rows.Next() {
+		var key, value string
+		if err := rows.Scan(&key, &value); err != nil {
+			return nil, err
+		}
+
+		settings[key] = value
+	}
+
+	return settings, rows.Err()
+}
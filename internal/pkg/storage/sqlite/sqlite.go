@@ -0,0 +1,174 @@
+// Package sqlite provides a SQLite-backed implementation of the storage.Storage interface.
+package sqlite
+
+import (
+	"context"
+	"database/sql"
+
+	"github.com/psyb0t/telegram-logger/internal/pkg/storage"
+
+	// register the pure-Go sqlite driver
+	_ "modernc.org/sqlite"
+)
+
+const driverName = "sqlite"
+
+// pragmas are run on the connection when the database is opened.
+var pragmas = []string{
+	`PRAGMA journal_mode = WAL`,
+	`PRAGMA busy_timeout = 5000`,
+}
+
+// sqliteDB is a struct that implements the Storage interface using a SQLite database.
+type sqliteDB struct {
+	ctx            context.Context //nolint:containedctx
+	cancelFunc     context.CancelFunc
+	db             *sql.DB
+	userRepository struct {
+		reader storage.UserRepositoryReader
+		writer storage.UserRepositoryWriter
+	}
+	roleRepository struct {
+		reader storage.RoleRepositoryReader
+		writer storage.RoleRepositoryWriter
+	}
+	inviteRepository struct {
+		reader storage.InviteRepositoryReader
+		writer storage.InviteRepositoryWriter
+	}
+	settingRepository struct {
+		reader storage.SettingRepositoryReader
+		writer storage.SettingRepositoryWriter
+	}
+}
+
+// New creates and returns a new sqliteDB instance.
+func New(parentCtx context.Context) (storage.Storage, error) {
+	db := &sqliteDB{}
+	db.ctx, db.cancelFunc = context.WithCancel(parentCtx)
+
+	// the repositories run each statement on its own
+	t := txn{db: db}
+
+	db.userRepository.reader = newUserRepositoryReader(t)
+	db.userRepository.writer = newUserRepositoryWriter(t)
+	db.roleRepository.reader = newRoleRepositoryReader(t)
+	db.roleRepository.writer = newRoleRepositoryWriter(t)
+	db.inviteRepository.reader = newInviteRepositoryReader(t)
+	db.inviteRepository.writer = newInviteRepositoryWriter(t)
+	db.settingRepository.reader = newSettingRepositoryReader(t)
+	db.settingRepository.writer = newSettingRepositoryWriter(t)
+
+	return db, nil
+}
+
+// Open opens a connection to a SQLite database and applies the schema
+// migrations. The provided DSN (Data Source Name) is the path of the
+// database file.
+func (db *sqliteDB) Open(ctx context.Context, dsn string) error {
+	var err error
+
+	db.db, err = sql.Open(driverName, dsn)
+	if err != nil {
+		return err
+	}
+
+	// SQLite allows a single writer at a time so a single connection
+	// avoids "database is locked" errors between the pool's connections.
+	db.db.SetMaxOpenConns(1)
+
+	for _, query := range pragmas {
+		if _, err := db.db.ExecContext(ctx, query); err != nil {
+			db.db.Close()
+
+			return err
+		}
+	}
+
+	if err := storage.Migrate[*sql.Tx](ctx, db, getMigrations()); err != nil {
+		db.db.Close()
+
+		return err
+	}
+
+	return nil
+}
+
+// Close closes the connection to the SQLite database.
+func (db *sqliteDB) Close() error {
+	err := db.db.Close()
+	db.cancelFunc()
+
+	return err
+}
+
+// Ping checks if the database is reachable and responding.
+func (db *sqliteDB) Ping(ctx context.Context) error {
+	return db.db.PingContext(ctx)
+}
+
+// InTx runs fn in a transaction which is committed if fn returns nil
+// and rolled back otherwise. There's a single connection so the
+// transactions run one at a time and never conflict.
+func (db *sqliteDB) InTx(ctx context.Context, fn func(tx storage.Repositories) error) error {
+	return db.inTx(ctx, func(tx *sql.Tx) error {
+		return fn(txRepositories{db: txn{db: db, tx: tx}})
+	})
+}
+
+// GetUserRepositoryReader returns a repository for reading user data from the database.
+func (db *sqliteDB) GetUserRepositoryReader() storage.UserRepositoryReader {
+	return db.userRepository.reader
+}
+
+// GetUserRepositoryWriter returns a repository for writing user data from the database.
+func (db *sqliteDB) GetUserRepositoryWriter() storage.UserRepositoryWriter {
+	return db.userRepository.writer
+}
+
+// GetRoleRepositoryReader returns a repository for reading chat role data from the database.
+func (db *sqliteDB) GetRoleRepositoryReader() storage.RoleRepositoryReader {
+	return db.roleRepository.reader
+}
+
+// GetRoleRepositoryWriter returns a repository for writing chat role data to the database.
+func (db *sqliteDB) GetRoleRepositoryWriter() storage.RoleRepositoryWriter {
+	return db.roleRepository.writer
+}
+
+// GetInviteRepositoryReader returns a repository for reading invite data from the database.
+func (db *sqliteDB) GetInviteRepositoryReader() storage.InviteRepositoryReader {
+	return db.inviteRepository.reader
+}
+
+// GetInviteRepositoryWriter returns a repository for writing invite data to the database.
+func (db *sqliteDB) GetInviteRepositoryWriter() storage.InviteRepositoryWriter {
+	return db.inviteRepository.writer
+}
+
+// GetSettingRepositoryReader returns a repository for reading settings from the database.
+func (db *sqliteDB) GetSettingRepositoryReader() storage.SettingRepositoryReader {
+	return db.settingRepository.reader
+}
+
+// GetSettingRepositoryWriter returns a repository for writing settings to the database.
+func (db *sqliteDB) GetSettingRepositoryWriter() storage.SettingRepositoryWriter {
+	return db.settingRepository.writer
+}
+
+// inTx runs the given function in a transaction which is
+// committed if the function returns no error and rolled back otherwise.
+func (db *sqliteDB) inTx(ctx context.Context, fn func(tx *sql.Tx) error) error {
+	tx, err := db.db.BeginTx(ctx, nil)
+	if err != nil {
+		return err
+	}
+
+	if err := fn(tx); err != nil {
+		_ = tx.Rollback()
+
+		return err
+	}
+
+	return tx.Commit()
+}
@@ -0,0 +1,23 @@
+package sqlite
+
+import (
+	"context"
+	"path/filepath"
+	"testing"
+
+	"github.com/psyb0t/telegram-logger/internal/pkg/storage"
+	"github.com/psyb0t/telegram-logger/internal/pkg/storage/storagetest"
+	"github.com/stretchr/testify/require"
+)
+
+func TestSQLite(t *testing.T) {
+	ctx := context.Background()
+
+	storagetest.Run(t, func(t *testing.T) storage.Storage {
+		db, err := New(ctx)
+		require.NoError(t, err)
+		require.NoError(t, db.Open(ctx, filepath.Join(t.TempDir(), "db.sqlite")))
+
+		return db
+	})
+}
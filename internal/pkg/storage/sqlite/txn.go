@@ -0,0 +1,155 @@
+package sqlite
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+
+	"github.com/psyb0t/telegram-logger/internal/pkg/storage"
+)
+
+// querier is implemented by both *sql.DB and *sql.Tx.
+type querier interface {
+	ExecContext(ctx context.Context, query string, args ...interface{}) (sql.Result, error)
+	QueryContext(ctx context.Context, query string, args ...interface{}) (*sql.Rows, error)
+	QueryRowContext(ctx context.Context, query string, args ...interface{}) *sql.Row
+}
+
+// txn is what the repositories run their statements through. If it
+// holds a transaction, every statement runs in it. Otherwise each
+// statement runs on its own.
+type txn struct {
+	db *sqliteDB
+	tx *sql.Tx
+}
+
+// querier returns the transaction or, if there's none, the database.
+func (t txn) querier() querier {
+	if t.tx != nil {
+		return t.tx
+	}
+
+	return t.db.db
+}
+
+// get retrieves the single column of the first row returned by the given query.
+func (t txn) get(ctx context.Context, query string, args ...interface{}) ([]byte, error) {
+	var val []byte
+
+	err := t.querier().QueryRowContext(ctx, query, args...).Scan(&val)
+	if err != nil {
+		// If there's no row, return an error.
+		if errors.Is(err, sql.ErrNoRows) {
+			return nil, storage.ErrNotFound
+		}
+
+		return nil, err
+	}
+
+	return val, nil
+}
+
+// getAll retrieves the single column of all of the rows returned by the given query.
+func (t txn) getAll(ctx context.Context, query string, args ...interface{}) ([][]byte, error) {
+	result := [][]byte{}
+
+	rows, err := t.querier().QueryContext(ctx, query, args...)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	for rows.Next() {
+		var val []byte
+		if err := rows.Scan(&val); err != nil {
+			return nil, err
+		}
+
+		result = append(result, val)
+	}
+
+	return result, rows.Err()
+}
+
+// exec runs the given statement.
+func (t txn) exec(ctx context.Context, query string, args ...interface{}) error {
+	_, err := t.querier().ExecContext(ctx, query, args...)
+
+	return err
+}
+
+// update runs the given statement and returns storage.ErrNotFound
+// if it didn't change any row.
+func (t txn) update(ctx context.Context, query string, args ...interface{}) error {
+	result, err := t.querier().ExecContext(ctx, query, args...)
+	if err != nil {
+		return err
+	}
+
+	n, err := result.RowsAffected()
+	if err != nil {
+		return err
+	}
+
+	if n == 0 {
+		return storage.ErrNotFound
+	}
+
+	return nil
+}
+
+// inTx runs the given function in the transaction or, if there's none,
+// in a new one which is committed if the function returns no error
+// and rolled back otherwise.
+func (t txn) inTx(ctx context.Context, fn func(tx *sql.Tx) error) error {
+	if t.tx != nil {
+		return fn(t.tx)
+	}
+
+	return t.db.inTx(ctx, fn)
+}
+
+// txRepositories hands out the repositories of a transaction.
+type txRepositories struct {
+	db txn
+}
+
+// GetUserRepositoryReader returns a repository for reading user data in the transaction.
+func (r txRepositories) GetUserRepositoryReader() storage.UserRepositoryReader {
+	return newUserRepositoryReader(r.db)
+}
+
+// GetUserRepositoryWriter returns a repository for writing user data in the transaction.
+func (r txRepositories) GetUserRepositoryWriter() storage.UserRepositoryWriter {
+	return newUserRepositoryWriter(r.db)
+}
+
+// GetRoleRepositoryReader returns a repository for reading chat role data in the transaction.
+func (r txRepositories) GetRoleRepositoryReader() storage.RoleRepositoryReader {
+	return newRoleRepositoryReader(r.db)
+}
+
+// GetRoleRepositoryWriter returns a repository for writing chat role data in the transaction.
+func (r txRepositories) GetRoleRepositoryWriter() storage.RoleRepositoryWriter {
+	return newRoleRepositoryWriter(r.db)
+}
+
+// GetInviteRepositoryReader returns a repository for reading invite data in the transaction.
+func (r txRepositories) GetInviteRepositoryReader() storage.InviteRepositoryReader {
+	return newInviteRepositoryReader(r.db)
+}
+
+// GetInviteRepositoryWriter returns a repository for writing invite data in the transaction.
+func (r txRepositories) GetInviteRepositoryWriter() storage.InviteRepositoryWriter {
+	return newInviteRepositoryWriter(r.db)
+}
+
+// GetSettingRepositoryReader returns a repository for reading settings in the transaction.
+func (r txRepositories) GetSettingRepositoryReader() storage.SettingRepositoryReader {
+	return newSettingRepositoryReader(r.db)
+}
+
+// GetSettingRepositoryWriter returns a repository for writing settings in the transaction.
+func (r txRepositories) GetSettingRepositoryWriter() storage.SettingRepositoryWriter {
+	return newSettingRepositoryWriter(r.db)
+}
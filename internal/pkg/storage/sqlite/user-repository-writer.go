@@ -0,0 +1,140 @@
+package sqlite
+
+import (
+	"context"
+	"database/sql"
+	"encoding/json"
+
+	"github.com/psyb0t/telegram-logger/internal/pkg/storage"
+	"github.com/psyb0t/telegram-logger/internal/pkg/types"
+)
+
+// userRepositoryWriter is a struct that implements the
+// storage.UserRepositoryWriter interface using a sqliteDB instance.
+type userRepositoryWriter struct {
+	// db runs the statements on the underlying sqliteDB
+	// instance, in a transaction if it holds one.
+	db txn
+}
+
+// newUserRepositoryWriter creates and returns
+// a new userRepositoryWriter instance.
+func newUserRepositoryWriter(db txn) storage.UserRepositoryWriter {
+	return userRepositoryWriter{db: db}
+}
+
+// Create stores a new user in the database.
+//
+// user is the user to be stored. It must have a non-empty ID field.
+func (r userRepositoryWriter) Create(ctx context.Context, user types.User) error {
+	if user.ID == "" {
+		return storage.ErrEmptyID
+	}
+
+	// Convert the user struct to a byte slice.
+	val, err := json.Marshal(user)
+	if err != nil {
+		return err
+	}
+
+	// Create the user replacing the existing one with the same ID
+	return r.db.exec(ctx, `INSERT OR REPLACE INTO users (id, telegram_chat_id, data) VALUES (?, ?, ?)`,
+		user.ID, user.TelegramChatID, val)
+}
+
+// Update replaces an existing user in the database.
+//
+// user is the user to be stored. It must have a non-empty ID field.
+func (r userRepositoryWriter) Update(ctx context.Context, user types.User) error {
+	if user.ID == "" {
+		return storage.ErrEmptyID
+	}
+
+	// Convert the user struct to a byte slice.
+	val, err := json.Marshal(user)
+	if err != nil {
+		return err
+	}
+
+	// Update the user
+	return r.db.update(ctx, `UPDATE users SET telegram_chat_id = ?, data = ? WHERE id = ?`,
+		user.TelegramChatID, val, user.ID)
+}
+
+// Delete removes a user from the database by ID.
+func (r userRepositoryWriter) Delete(ctx context.Context, id string) error {
+	if id == "" {
+		return storage.ErrEmptyID
+	}
+
+	return r.db.exec(ctx, `DELETE FROM users WHERE id = ?`, id)
+}
+
+// DeleteAllByTelegramChatID removes all users from the database
+// matching the given Telegram chat ID.
+func (r userRepositoryWriter) DeleteAllByTelegramChatID(ctx context.Context, chatID int64) error {
+	if chatID == 0 {
+		return storage.ErrEmptyTelegramChatID
+	}
+
+	return r.db.exec(ctx, `DELETE FROM users WHERE telegram_chat_id = ?`, chatID)
+}
+
+// UpdateTelegramChatID replaces the Telegram chat ID of all of the users
+// matching oldChatID with newChatID in a single transaction.
+func (r userRepositoryWriter) UpdateTelegramChatID(ctx context.Context, oldChatID, newChatID int64) error {
+	if oldChatID == 0 || newChatID == 0 {
+		return storage.ErrEmptyTelegramChatID
+	}
+
+	return r.db.inTx(ctx, func(tx *sql.Tx) error {
+		rows, err := tx.QueryContext(ctx, `SELECT data FROM users WHERE telegram_chat_id = ?`, oldChatID)
+		if err != nil {
+			return err
+		}
+
+		// Collect the users first since the connection
+		// can't be written to while reading the rows.
+		users := []types.User{}
+
+		for rows.Next() {
+			var val []byte
+			if err := rows.Scan(&val); err != nil {
+				rows.Close()
+
+				return err
+			}
+
+			var user types.User
+			if err := json.Unmarshal(val, &user); err != nil {
+				rows.Close()
+
+				return err
+			}
+
+			users = append(users, user)
+		}
+
+		rows.Close()
+
+		if err := rows.Err(); err != nil {
+			return err
+		}
+
+		for _, user := range users {
+			user.TelegramChatID = newChatID
+
+			val, err := json.Marshal(user)
+			if err != nil {
+				return err
+			}
+
+			if _, err := tx.ExecContext(ctx, `UPDATE users SET telegram_chat_id = ?, data = ? WHERE id = ?`,
+				user.TelegramChatID, val, user.ID); err != nil {
+				return err
+			}
+		}
+
+		return nil
+	})
+}
@@ -0,0 +1,18 @@
+package storage
+
+import "context"
+
+// Stat is a named statistic of a storage such as its size on disk.
+type Stat struct {
+	Name  string
+	Value string
+}
+
+// StatsReporter is implemented by the storages that keep statistics
+// about themselves, such as badgerDB which reports the size of its
+// files and the maintenance it runs in the background.
+type StatsReporter interface {
+	// Stats returns the statistics of the storage in the
+	// order they should be shown in.
+	Stats(ctx context.Context) ([]Stat, error)
+}
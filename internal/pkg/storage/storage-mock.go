@@ -1,25 +1,41 @@
 package storage
 
-import "github.com/stretchr/testify/mock"
+import (
+	"context"
+
+	"github.com/stretchr/testify/mock"
+)
 
 // Mock is a mock implementation of Storage.
 type Mock struct {
 	mock.Mock
 
-	userRepositoryReader UserRepositoryReader
-	userRepositoryWriter UserRepositoryWriter
+	userRepositoryReader    UserRepositoryReader
+	userRepositoryWriter    UserRepositoryWriter
+	roleRepositoryReader    RoleRepositoryReader
+	roleRepositoryWriter    RoleRepositoryWriter
+	inviteRepositoryReader  InviteRepositoryReader
+	inviteRepositoryWriter  InviteRepositoryWriter
+	settingRepositoryReader SettingRepositoryReader
+	settingRepositoryWriter SettingRepositoryWriter
 }
 
 // NewMock returns a new instance of Mock.
 func NewMock() *Mock {
 	return &Mock{
-		userRepositoryReader: &UserRepositoryReaderMock{},
-		userRepositoryWriter: &UserRepositoryWriterMock{},
+		userRepositoryReader:    &UserRepositoryReaderMock{},
+		userRepositoryWriter:    &UserRepositoryWriterMock{},
+		roleRepositoryReader:    &RoleRepositoryReaderMock{},
+		roleRepositoryWriter:    &RoleRepositoryWriterMock{},
+		inviteRepositoryReader:  &InviteRepositoryReaderMock{},
+		inviteRepositoryWriter:  &InviteRepositoryWriterMock{},
+		settingRepositoryReader: &SettingRepositoryReaderMock{},
+		settingRepositoryWriter: &SettingRepositoryWriterMock{},
 	}
 }
 
 // Open establishes a connection to the storage system using the given DSN.
-func (db *Mock) Open(dsn string) error {
+func (db *Mock) Open(_ context.Context, dsn string) error {
 	args := db.Called(dsn)
 	return args.Error(0)
 }
@@ -32,11 +48,17 @@ func (db *Mock) Close() error {
 
 // Ping checks if the database is reachable and responding.
 // It returns nil if the database is reachable and responding, or an error otherwise.
-func (db *Mock) Ping() error {
+func (db *Mock) Ping(_ context.Context) error {
 	args := db.Called()
 	return args.Error(0)
 }
 
+// InTx runs fn with the mock's own repositories so the
+// calls made through them can be set up as usual.
+func (db *Mock) InTx(_ context.Context, fn func(tx Repositories) error) error {
+	return fn(db)
+}
+
 // GetUserRepositoryReader returns a repository for reading user data from the database
 func (db *Mock) GetUserRepositoryReader() UserRepositoryReader {
 	return db.userRepositoryReader
@@ -46,3 +68,33 @@ func (db *Mock) GetUserRepositoryReader() UserRepositoryReader {
 func (db *Mock) GetUserRepositoryWriter() UserRepositoryWriter {
 	return db.userRepositoryWriter
 }
+
+// GetRoleRepositoryReader returns a repository for reading chat role data from the database
+func (db *Mock) GetRoleRepositoryReader() RoleRepositoryReader {
+	return db.roleRepositoryReader
+}
+
+// GetRoleRepositoryWriter returns a repository for writing chat role data to the database
+func (db *Mock) GetRoleRepositoryWriter() RoleRepositoryWriter {
+	return db.roleRepositoryWriter
+}
+
+// GetInviteRepositoryReader returns a repository for reading invite data from the database
+func (db *Mock) GetInviteRepositoryReader() InviteRepositoryReader {
+	return db.inviteRepositoryReader
+}
+
+// GetInviteRepositoryWriter returns a repository for writing invite data to the database
+func (db *Mock) GetInviteRepositoryWriter() InviteRepositoryWriter {
+	return db.inviteRepositoryWriter
+}
+
+// GetSettingRepositoryReader returns a repository for reading settings from the database
+func (db *Mock) GetSettingRepositoryReader() SettingRepositoryReader {
+	return db.settingRepositoryReader
+}
+
+// GetSettingRepositoryWriter returns a repository for writing settings to the database
+func (db *Mock) GetSettingRepositoryWriter() SettingRepositoryWriter {
+	return db.settingRepositoryWriter
+}
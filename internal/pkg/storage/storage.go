@@ -1,20 +1,60 @@
 package storage
 
+import "context"
+
 // Storage is an interface for interacting with a storage system.
+//
+// All of the methods talking to the storage system, including the ones
+// of the repositories, take a context which bounds the call. Once it's
+// done, the call is interrupted and the error of the context is returned.
 type Storage interface {
 	// Open establishes a connection to the storage system using the given DSN.
-	Open(dsn string) error
+	Open(ctx context.Context, dsn string) error
 
 	// Close closes the connection to the storage system.
 	Close() error
 
 	// Ping checks if the database is reachable and responding.
 	// It returns nil if the database is reachable and responding, or an error otherwise.
-	Ping() error
+	Ping(ctx context.Context) error
+
+	// InTx runs fn in a transaction which is committed if fn returns nil
+	// and rolled back otherwise. The repositories handed to fn read and
+	// write through the transaction so either all or none of the writes
+	// are stored. fn may be called more than once if the transaction
+	// conflicts with another one so it must not have other side effects
+	// and it must not use the storage's own repositories. fn must not
+	// rely on reading its own writes since not every storage supports
+	// it. Redis, for example, only queues the writes until the commit.
+	InTx(ctx context.Context, fn func(tx Repositories) error) error
 
+	Repositories
+}
+
+// Repositories hands out the repositories for reading and writing
+// the data of a storage or of a transaction.
+type Repositories interface {
 	// GetUserRepositoryReader returns a repository for reading user data from the database
 	GetUserRepositoryReader() UserRepositoryReader
 
 	// GetUserRepositoryWriter returns a repository for writing user data from the database
 	GetUserRepositoryWriter() UserRepositoryWriter
+
+	// GetRoleRepositoryReader returns a repository for reading chat role data from the database
+	GetRoleRepositoryReader() RoleRepositoryReader
+
+	// GetRoleRepositoryWriter returns a repository for writing chat role data to the database
+	GetRoleRepositoryWriter() RoleRepositoryWriter
+
+	// GetInviteRepositoryReader returns a repository for reading invite data from the database
+	GetInviteRepositoryReader() InviteRepositoryReader
+
+	// GetInviteRepositoryWriter returns a repository for writing invite data to the database
+	GetInviteRepositoryWriter() InviteRepositoryWriter
+
+	// GetSettingRepositoryReader returns a repository for reading settings from the database
+	GetSettingRepositoryReader() SettingRepositoryReader
+
+	// GetSettingRepositoryWriter returns a repository for writing settings to the database
+	GetSettingRepositoryWriter() SettingRepositoryWriter
 }
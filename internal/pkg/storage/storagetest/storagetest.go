@@ -0,0 +1,512 @@
+// Package storagetest provides the tests every storage.Storage
+// implementation has to pass so that the backends behave the same.
+package storagetest
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"strconv"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/psyb0t/telegram-logger/internal/pkg/storage"
+	"github.com/psyb0t/telegram-logger/internal/pkg/types"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// NewStorageFunc returns a new, empty and open storage.Storage. It's
+// called once per test and the storage is closed when the test is done.
+type NewStorageFunc func(t *testing.T) storage.Storage
+
+// Run runs all of the storage tests against the storages
+// returned by newStorage.
+func Run(t *testing.T, newStorage NewStorageFunc) {
+	t.Helper()
+
+	tests := map[string]func(*testing.T, storage.Storage){
+		"Ping":                            testPing,
+		"UserRepository":                  testUserRepository,
+		"UserRepository_ByTelegramChatID": testUserRepositoryByTelegramChatID,
+		"UserRepository_UpdateChatID":     testUserRepositoryUpdateTelegramChatID,
+		"UserRepository_GetPage":          testUserRepositoryGetPage,
+		"UserRepository_Iterate":          testUserRepositoryIterate,
+		"RoleRepository":                  testRoleRepository,
+		"InviteRepository":                testInviteRepository,
+		"SettingRepository":               testSettingRepository,
+		"InTx":                            testInTx,
+		"InTx_concurrent":                 testInTxConcurrent,
+		"CanceledContext":                 testCanceledContext,
+	}
+
+	for name, test := range tests {
+		test := test
+
+		t.Run(name, func(t *testing.T) {
+			db := newStorage(t)
+			t.Cleanup(func() {
+				assert.NoError(t, db.Close())
+			})
+
+			test(t, db)
+		})
+	}
+}
+
+func testPing(t *testing.T, db storage.Storage) {
+	assert.NoError(t, db.Ping(context.Background()))
+}
+
+func testUserRepository(t *testing.T, db storage.Storage) {
+	ctx := context.Background()
+
+	reader := db.GetUserRepositoryReader()
+	writer := db.GetUserRepositoryWriter()
+
+	expiresAt := time.Date(2030, 1, 2, 3, 4, 5, 0, time.UTC)
+	user := types.User{
+		ID:             "a",
+		TelegramChatID: 1,
+		ChatTitle:      "logs",
+		ExpiresAt:      &expiresAt,
+		AllowedCIDRs:   []string{"10.0.0.0/8"},
+	}
+
+	_, err := reader.Get(ctx, "")
+	assert.ErrorIs(t, err, storage.ErrEmptyID)
+
+	_, err = reader.Get(ctx, "a")
+	assert.ErrorIs(t, err, storage.ErrNotFound)
+
+	assert.ErrorIs(t, writer.Create(ctx, types.User{}), storage.ErrEmptyID)
+	assert.ErrorIs(t, writer.Update(ctx, user), storage.ErrNotFound)
+
+	require.NoError(t, writer.Create(ctx, user))
+
+	found, err := reader.Get(ctx, "a")
+	require.NoError(t, err)
+	assert.Equal(t, user, found)
+
+	user.Status = types.UserStatusInactive
+	require.NoError(t, writer.Update(ctx, user))
+
+	found, err = reader.Get(ctx, "a")
+	require.NoError(t, err)
+	assert.Equal(t, user, found)
+
+	// creating an existing user replaces it
+	user.Status = types.UserStatusActive
+	require.NoError(t, writer.Create(ctx, user))
+
+	require.NoError(t, writer.Create(ctx, types.User{ID: "b", TelegramChatID: 2}))
+
+	all, err := reader.GetAll(ctx)
+	require.NoError(t, err)
+	assert.ElementsMatch(t, []types.User{user, {ID: "b", TelegramChatID: 2}}, all)
+
+	assert.ErrorIs(t, writer.Delete(ctx, ""), storage.ErrEmptyID)
+	require.NoError(t, writer.Delete(ctx, "a"))
+
+	_, err = reader.Get(ctx, "a")
+	assert.ErrorIs(t, err, storage.ErrNotFound)
+
+	all, err = reader.GetAll(ctx)
+	require.NoError(t, err)
+	assert.Equal(t, []types.User{{ID: "b", TelegramChatID: 2}}, all)
+}
+
+func testUserRepositoryByTelegramChatID(t *testing.T, db storage.Storage) {
+	ctx := context.Background()
+
+	reader := db.GetUserRepositoryReader()
+	writer := db.GetUserRepositoryWriter()
+
+	users := []types.User{
+		{ID: "a", TelegramChatID: 1},
+		{ID: "b", TelegramChatID: 1},
+		{ID: "c", TelegramChatID: 2},
+	}
+
+	for _, user := range users {
+		require.NoError(t, writer.Create(ctx, user))
+	}
+
+	_, err := reader.GetByTelegramChatID(ctx, 0)
+	assert.ErrorIs(t, err, storage.ErrEmptyTelegramChatID)
+
+	found, err := reader.GetByTelegramChatID(ctx, 2)
+	require.NoError(t, err)
+	assert.Equal(t, users[2], found)
+
+	_, err = reader.GetByTelegramChatID(ctx, 3)
+	assert.ErrorIs(t, err, storage.ErrNotFound)
+
+	_, err = reader.GetAllByTelegramChatID(ctx, 0)
+	assert.ErrorIs(t, err, storage.ErrEmptyTelegramChatID)
+
+	all, err := reader.GetAllByTelegramChatID(ctx, 1)
+	require.NoError(t, err)
+	assert.ElementsMatch(t, users[:2], all)
+
+	all, err = reader.GetAllByTelegramChatID(ctx, 3)
+	require.NoError(t, err)
+	assert.Empty(t, all)
+
+	assert.ErrorIs(t, writer.DeleteAllByTelegramChatID(ctx, 0), storage.ErrEmptyTelegramChatID)
+	require.NoError(t, writer.DeleteAllByTelegramChatID(ctx, 1))
+
+	all, err = reader.GetAll(ctx)
+	require.NoError(t, err)
+	assert.Equal(t, users[2:], all)
+}
+
+func testUserRepositoryUpdateTelegramChatID(t *testing.T, db storage.Storage) {
+	ctx := context.Background()
+
+	reader := db.GetUserRepositoryReader()
+	writer := db.GetUserRepositoryWriter()
+
+	users := []types.User{
+		{ID: "a", TelegramChatID: 1},
+		{ID: "b", TelegramChatID: 1},
+		{ID: "c", TelegramChatID: 2},
+	}
+
+	for _, user := range users {
+		require.NoError(t, writer.Create(ctx, user))
+	}
+
+	assert.ErrorIs(t, writer.UpdateTelegramChatID(ctx, 0, 3), storage.ErrEmptyTelegramChatID)
+	assert.ErrorIs(t, writer.UpdateTelegramChatID(ctx, 1, 0), storage.ErrEmptyTelegramChatID)
+	require.NoError(t, writer.UpdateTelegramChatID(ctx, 1, 3))
+
+	expected := map[string]int64{"a": 3, "b": 3, "c": 2}
+	for id, chatID := range expected {
+		user, err := reader.Get(ctx, id)
+		require.NoError(t, err)
+		assert.Equal(t, chatID, user.TelegramChatID)
+	}
+
+	all, err := reader.GetAllByTelegramChatID(ctx, 1)
+	require.NoError(t, err)
+	assert.Empty(t, all)
+}
+
+func testUserRepositoryGetPage(t *testing.T, db storage.Storage) {
+	ctx := context.Background()
+
+	reader := db.GetUserRepositoryReader()
+	writer := db.GetUserRepositoryWriter()
+
+	for _, id := range []string{"c", "a", "e", "b", "d"} {
+		require.NoError(t, writer.Create(ctx, types.User{ID: id, TelegramChatID: 1}))
+	}
+
+	_, err := reader.GetPage(ctx, storage.Cursor{})
+	assert.ErrorIs(t, err, storage.ErrInvalidLimit)
+
+	tests := []struct {
+		cursor   storage.Cursor
+		expected []string
+	}{
+		{cursor: storage.Cursor{Limit: 2}, expected: []string{"a", "b"}},
+		{cursor: storage.Cursor{StartKey: "c", Limit: 2}, expected: []string{"c", "d"}},
+		{cursor: storage.Cursor{StartKey: "bb", Limit: 2}, expected: []string{"c", "d"}},
+		{cursor: storage.Cursor{StartKey: "e", Limit: 2}, expected: []string{"e"}},
+		{cursor: storage.Cursor{StartKey: "f", Limit: 2}, expected: []string{}},
+		{cursor: storage.Cursor{Limit: 2, Backward: true}, expected: []string{"d", "e"}},
+		{cursor: storage.Cursor{StartKey: "c", Limit: 2, Backward: true}, expected: []string{"a", "b"}},
+		{cursor: storage.Cursor{StartKey: "bb", Limit: 5, Backward: true}, expected: []string{"a", "b"}},
+		{cursor: storage.Cursor{StartKey: "a", Limit: 2, Backward: true}, expected: []string{}},
+	}
+
+	for _, test := range tests {
+		users, err := reader.GetPage(ctx, test.cursor)
+		require.NoError(t, err)
+
+		ids := []string{}
+		for _, user := range users {
+			ids = append(ids, user.ID)
+		}
+
+		assert.Equal(t, test.expected, ids, "%+v", test.cursor)
+	}
+
+	// the transactions see the same pages
+	require.NoError(t, db.InTx(ctx, func(tx storage.Repositories) error {
+		users, err := tx.GetUserRepositoryReader().GetPage(ctx, storage.Cursor{StartKey: "d", Limit: 5})
+		require.NoError(t, err)
+		assert.Equal(t, []types.User{{ID: "d", TelegramChatID: 1}, {ID: "e", TelegramChatID: 1}}, users)
+
+		return nil
+	}))
+}
+
+func testUserRepositoryIterate(t *testing.T, db storage.Storage) {
+	ctx := context.Background()
+
+	reader := db.GetUserRepositoryReader()
+	writer := db.GetUserRepositoryWriter()
+
+	// more than a page of users
+	expected := []string{}
+
+	for i := 0; i < storage.IterateBatchSize+5; i++ {
+		id := fmt.Sprintf("user-%04d", i)
+		expected = append(expected, id)
+
+		require.NoError(t, writer.Create(ctx, types.User{ID: id, TelegramChatID: 1}))
+	}
+
+	errStop := errors.New("stop")
+	visited := []string{}
+
+	err := reader.Iterate(ctx, func(user types.User) error {
+		visited = append(visited, user.ID)
+		if len(visited) == 3 {
+			return errStop
+		}
+
+		return nil
+	})
+	assert.ErrorIs(t, err, errStop)
+	assert.Equal(t, expected[:3], visited)
+
+	// fn can use the storage while iterating
+	visited = []string{}
+
+	require.NoError(t, reader.Iterate(ctx, func(user types.User) error {
+		visited = append(visited, user.ID)
+
+		return writer.Delete(ctx, user.ID)
+	}))
+	assert.Equal(t, expected, visited)
+
+	all, err := reader.GetAll(ctx)
+	require.NoError(t, err)
+	assert.Empty(t, all)
+}
+
+func testRoleRepository(t *testing.T, db storage.Storage) {
+	ctx := context.Background()
+
+	reader := db.GetRoleRepositoryReader()
+	writer := db.GetRoleRepositoryWriter()
+
+	_, err := reader.Get(ctx, 0)
+	assert.ErrorIs(t, err, storage.ErrEmptyTelegramChatID)
+
+	_, err = reader.Get(ctx, 1)
+	assert.ErrorIs(t, err, storage.ErrNotFound)
+
+	assert.ErrorIs(t, writer.Set(ctx, types.ChatRole{Role: types.RoleAdmin}), storage.ErrEmptyTelegramChatID)
+	assert.ErrorIs(t, writer.Set(ctx, types.ChatRole{TelegramChatID: 1}), storage.ErrEmptyRole)
+
+	require.NoError(t, writer.Set(ctx, types.ChatRole{TelegramChatID: 1, Role: types.RoleMember}))
+	require.NoError(t, writer.Set(ctx, types.ChatRole{TelegramChatID: 1, Role: types.RoleAdmin}))
+	require.NoError(t, writer.Set(ctx, types.ChatRole{TelegramChatID: 2, Role: types.RoleAdmin}))
+
+	chatRole, err := reader.Get(ctx, 1)
+	require.NoError(t, err)
+	assert.Equal(t, types.ChatRole{TelegramChatID: 1, Role: types.RoleAdmin}, chatRole)
+
+	all, err := reader.GetAll(ctx)
+	require.NoError(t, err)
+	assert.ElementsMatch(t, []types.ChatRole{
+		{TelegramChatID: 1, Role: types.RoleAdmin},
+		{TelegramChatID: 2, Role: types.RoleAdmin},
+	}, all)
+
+	assert.ErrorIs(t, writer.Delete(ctx, 0), storage.ErrEmptyTelegramChatID)
+	require.NoError(t, writer.Delete(ctx, 1))
+
+	_, err = reader.Get(ctx, 1)
+	assert.ErrorIs(t, err, storage.ErrNotFound)
+}
+
+func testInviteRepository(t *testing.T, db storage.Storage) {
+	ctx := context.Background()
+
+	reader := db.GetInviteRepositoryReader()
+	writer := db.GetInviteRepositoryWriter()
+
+	createdAt := time.Date(2030, 1, 2, 3, 4, 5, 0, time.UTC)
+	invite := types.Invite{
+		ID:          "a",
+		CreatedBy:   1,
+		CreatedAt:   createdAt,
+		MaxUses:     2,
+		UserOptions: []string{"ttl=30d"},
+	}
+
+	_, err := reader.Get(ctx, "")
+	assert.ErrorIs(t, err, storage.ErrEmptyID)
+
+	_, err = reader.Get(ctx, "a")
+	assert.ErrorIs(t, err, storage.ErrNotFound)
+
+	assert.ErrorIs(t, writer.Create(ctx, types.Invite{}), storage.ErrEmptyID)
+	assert.ErrorIs(t, writer.Update(ctx, types.Invite{}), storage.ErrEmptyID)
+	assert.ErrorIs(t, writer.Update(ctx, invite), storage.ErrNotFound)
+
+	require.NoError(t, writer.Create(ctx, invite))
+
+	invite.Uses = []types.InviteUse{{TelegramChatID: 2, UsedAt: createdAt}}
+	require.NoError(t, writer.Update(ctx, invite))
+
+	found, err := reader.Get(ctx, "a")
+	require.NoError(t, err)
+	assert.Equal(t, invite, found)
+
+	all, err := reader.GetAll(ctx)
+	require.NoError(t, err)
+	assert.Equal(t, []types.Invite{invite}, all)
+}
+
+func testSettingRepository(t *testing.T, db storage.Storage) {
+	ctx := context.Background()
+
+	reader := db.GetSettingRepositoryReader()
+	writer := db.GetSettingRepositoryWriter()
+
+	_, err := reader.Get(ctx, "")
+	assert.ErrorIs(t, err, storage.ErrEmptyKey)
+
+	_, err = reader.Get(ctx, "a")
+	assert.ErrorIs(t, err, storage.ErrNotFound)
+
+	assert.ErrorIs(t, writer.Set(ctx, "", "1"), storage.ErrEmptyKey)
+
+	require.NoError(t, writer.Set(ctx, "a", "1"))
+	require.NoError(t, writer.Set(ctx, "a", "2"))
+
+	value, err := reader.Get(ctx, "a")
+	require.NoError(t, err)
+	assert.Equal(t, "2", value)
+
+	require.NoError(t, writer.Set(ctx, "b", "3"))
+
+	settings, err := reader.GetAll(ctx)
+	require.NoError(t, err)
+	assert.Equal(t, map[string]string{"a": "2", "b": "3"}, settings)
+}
+
+func testInTx(t *testing.T, db storage.Storage) {
+	ctx := context.Background()
+
+	errTest := errors.New("test")
+
+	require.NoError(t, db.GetUserRepositoryWriter().Create(ctx, types.User{ID: "a", TelegramChatID: 1}))
+
+	// the writes of a failed transaction are rolled back
+	err := db.InTx(ctx, func(tx storage.Repositories) error {
+		if err := tx.GetUserRepositoryWriter().DeleteAllByTelegramChatID(ctx, 1); err != nil {
+			return err
+		}
+
+		if err := tx.GetUserRepositoryWriter().Create(ctx, types.User{ID: "b", TelegramChatID: 1}); err != nil {
+			return err
+		}
+
+		return errTest
+	})
+	assert.ErrorIs(t, err, errTest)
+
+	all, err := db.GetUserRepositoryReader().GetAll(ctx)
+	require.NoError(t, err)
+	assert.Equal(t, []types.User{{ID: "a", TelegramChatID: 1}}, all)
+
+	// the writes of a successful transaction are all stored
+	err = db.InTx(ctx, func(tx storage.Repositories) error {
+		user, err := tx.GetUserRepositoryReader().GetByTelegramChatID(ctx, 1)
+		if err != nil {
+			return err
+		}
+
+		if err := tx.GetUserRepositoryWriter().DeleteAllByTelegramChatID(ctx, user.TelegramChatID); err != nil {
+			return err
+		}
+
+		if err := tx.GetUserRepositoryWriter().Create(ctx, types.User{ID: "b", TelegramChatID: 1}); err != nil {
+			return err
+		}
+
+		return tx.GetRoleRepositoryWriter().Set(ctx, types.ChatRole{TelegramChatID: 1, Role: types.RoleAdmin})
+	})
+	require.NoError(t, err)
+
+	all, err = db.GetUserRepositoryReader().GetAll(ctx)
+	require.NoError(t, err)
+	assert.Equal(t, []types.User{{ID: "b", TelegramChatID: 1}}, all)
+
+	chatRole, err := db.GetRoleRepositoryReader().Get(ctx, 1)
+	require.NoError(t, err)
+	assert.Equal(t, types.RoleAdmin, chatRole.Role)
+}
+
+func testInTxConcurrent(t *testing.T, db storage.Storage) {
+	ctx := context.Background()
+
+	const n = 5
+
+	require.NoError(t, db.GetSettingRepositoryWriter().Set(ctx, "counter", "0"))
+
+	var wg sync.WaitGroup
+
+	for i := 0; i < n; i++ {
+		wg.Add(1)
+
+		go func() {
+			defer wg.Done()
+
+			// increments are lost unless the transactions are isolated
+			assert.NoError(t, db.InTx(ctx, func(tx storage.Repositories) error {
+				value, err := tx.GetSettingRepositoryReader().Get(ctx, "counter")
+				if err != nil {
+					return err
+				}
+
+				counter, err := strconv.Atoi(value)
+				if err != nil {
+					return err
+				}
+
+				return tx.GetSettingRepositoryWriter().Set(ctx, "counter", strconv.Itoa(counter+1))
+			}))
+		}()
+	}
+
+	wg.Wait()
+
+	value, err := db.GetSettingRepositoryReader().Get(ctx, "counter")
+	require.NoError(t, err)
+	assert.Equal(t, strconv.Itoa(n), value)
+}
+
+func testCanceledContext(t *testing.T, db storage.Storage) {
+	require.NoError(t, db.GetUserRepositoryWriter().Create(context.Background(), types.User{ID: "a", TelegramChatID: 1}))
+
+	ctx, cancel := context.WithCancel(context.Background())
+	cancel()
+
+	_, err := db.GetUserRepositoryReader().GetAll(ctx)
+	assert.ErrorIs(t, err, context.Canceled)
+
+	_, err = db.GetUserRepositoryReader().GetAllByTelegramChatID(ctx, 1)
+	assert.ErrorIs(t, err, context.Canceled)
+
+	err = db.GetUserRepositoryWriter().Create(ctx, types.User{ID: "b", TelegramChatID: 1})
+	assert.ErrorIs(t, err, context.Canceled)
+
+	err = db.InTx(ctx, func(tx storage.Repositories) error {
+		return tx.GetUserRepositoryWriter().Delete(ctx, "a")
+	})
+	assert.ErrorIs(t, err, context.Canceled)
+
+	// nothing was written
+	all, err := db.GetUserRepositoryReader().GetAll(context.Background())
+	require.NoError(t, err)
+	assert.Equal(t, []types.User{{ID: "a", TelegramChatID: 1}}, all)
+}
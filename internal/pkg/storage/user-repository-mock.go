@@ -1,6 +1,8 @@
 package storage
 
 import (
+	"context"
+
 	"github.com/psyb0t/telegram-logger/internal/pkg/types"
 	"github.com/stretchr/testify/mock"
 )
@@ -11,43 +13,82 @@ type UserRepositoryReaderMock struct {
 }
 
 // Get retrieves a user by ID.
-func (r *UserRepositoryReaderMock) Get(id string) (types.User, error) {
+func (r *UserRepositoryReaderMock) Get(_ context.Context, id string) (types.User, error) {
 	args := r.Called(id)
 	return args.Get(0).(types.User), args.Error(1)
 }
 
 // GetAll retrieves all users from the database.
-func (r *UserRepositoryReaderMock) GetAll() ([]types.User, error) {
+func (r *UserRepositoryReaderMock) GetAll(_ context.Context) ([]types.User, error) {
 	args := r.Called()
 	return args.Get(0).([]types.User), args.Error(1)
 }
 
+// GetPage retrieves the page of users, sorted by ID, selected by the given cursor.
+func (r *UserRepositoryReaderMock) GetPage(_ context.Context, cursor Cursor) ([]types.User, error) {
+	args := r.Called(cursor)
+	return args.Get(0).([]types.User), args.Error(1)
+}
+
+// Iterate calls fn for every user returned by the mock
+// and then returns the mocked error.
+func (r *UserRepositoryReaderMock) Iterate(_ context.Context, fn func(user types.User) error) error {
+	args := r.Called()
+
+	for _, user := range args.Get(0).([]types.User) {
+		if err := fn(user); err != nil {
+			return err
+		}
+	}
+
+	return args.Error(1)
+}
+
 // GetByTelegramChatID retrieves a user by its Telegram chat ID.
-func (r *UserRepositoryReaderMock) GetByTelegramChatID(chatID int64) (types.User, error) {
+func (r *UserRepositoryReaderMock) GetByTelegramChatID(_ context.Context, chatID int64) (types.User, error) {
 	args := r.Called(chatID)
 	return args.Get(0).(types.User), args.Error(1)
 }
 
+// GetAllByTelegramChatID retrieves all users matching the given Telegram chat ID.
+func (r *UserRepositoryReaderMock) GetAllByTelegramChatID(_ context.Context, chatID int64) ([]types.User, error) {
+	args := r.Called(chatID)
+	return args.Get(0).([]types.User), args.Error(1)
+}
+
 // UserRepositoryWriterMock is a mock implementation of UserRepositoryWriter.
 type UserRepositoryWriterMock struct {
 	mock.Mock
 }
 
 // Create stores a new user in the database.
-func (r *UserRepositoryWriterMock) Create(user types.User) error {
+func (r *UserRepositoryWriterMock) Create(_ context.Context, user types.User) error {
+	args := r.Called(user)
+	return args.Error(0)
+}
+
+// Update replaces an existing user in the database.
+func (r *UserRepositoryWriterMock) Update(_ context.Context, user types.User) error {
 	args := r.Called(user)
 	return args.Error(0)
 }
 
 // Delete removes a user from the database by ID.
-func (r *UserRepositoryWriterMock) Delete(id string) error {
+func (r *UserRepositoryWriterMock) Delete(_ context.Context, id string) error {
 	args := r.Called(id)
 	return args.Error(0)
 }
 
 // DeleteAllByTelegramChatID removes all users from the
 // database matching the given Telegram chat ID.
-func (r *UserRepositoryWriterMock) DeleteAllByTelegramChatID(chatID int64) error {
+func (r *UserRepositoryWriterMock) DeleteAllByTelegramChatID(_ context.Context, chatID int64) error {
 	args := r.Called(chatID)
 	return args.Error(0)
 }
+
+// UpdateTelegramChatID replaces the Telegram chat ID of all of the
+// users matching oldChatID with newChatID in a single transaction.
+func (r *UserRepositoryWriterMock) UpdateTelegramChatID(_ context.Context, oldChatID, newChatID int64) error {
+	args := r.Called(oldChatID, newChatID)
+	return args.Error(0)
+}
@@ -1,30 +1,51 @@
 package storage
 
-import "github.com/psyb0t/telegram-logger/internal/pkg/types"
+import (
+	"context"
+
+	"github.com/psyb0t/telegram-logger/internal/pkg/types"
+)
 
 // UserRepositoryReader is an interface for reading
 // user data stored in the database.
 type UserRepositoryReader interface {
 	// Get retrieves a user by ID.
-	Get(id string) (types.User, error)
+	Get(ctx context.Context, id string) (types.User, error)
 
 	// GetAll retrieves all users from the database.
-	GetAll() ([]types.User, error)
+	GetAll(ctx context.Context) ([]types.User, error)
+
+	// GetPage retrieves the page of users, sorted by ID, selected by the given cursor.
+	GetPage(ctx context.Context, cursor Cursor) ([]types.User, error)
+
+	// Iterate calls fn for every user, sorted by ID, without holding all
+	// of them in memory. It stops at the first error returned by fn.
+	Iterate(ctx context.Context, fn func(user types.User) error) error
 
 	// GetByTelegramChatID retrieves a user by its Telegram chat ID.
-	GetByTelegramChatID(chatID int64) (types.User, error)
+	GetByTelegramChatID(ctx context.Context, chatID int64) (types.User, error)
+
+	// GetAllByTelegramChatID retrieves all users matching the given Telegram chat ID.
+	GetAllByTelegramChatID(ctx context.Context, chatID int64) ([]types.User, error)
 }
 
 // UserRepositoryWriter is an interface for writing
 // user data stored in the database.
 type UserRepositoryWriter interface {
 	// Create stores a new user in the database.
-	Create(user types.User) error
+	Create(ctx context.Context, user types.User) error
+
+	// Update replaces an existing user in the database.
+	Update(ctx context.Context, user types.User) error
 
 	// Delete removes a user from the database by ID.
-	Delete(id string) error
+	Delete(ctx context.Context, id string) error
 
 	// DeleteAllByTelegramChatID removes all users from the
 	// database matching the given Telegram chat ID.
-	DeleteAllByTelegramChatID(chatID int64) error
+	DeleteAllByTelegramChatID(ctx context.Context, chatID int64) error
+
+	// UpdateTelegramChatID replaces the Telegram chat ID of all of the
+	// users matching oldChatID with newChatID in a single transaction.
+	UpdateTelegramChatID(ctx context.Context, oldChatID, newChatID int64) error
 }
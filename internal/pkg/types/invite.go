@@ -0,0 +1,58 @@
+package types
+
+import "time"
+
+// Invite is an invite code that lets chats register without
+// an admin approving them.
+type Invite struct {
+	// ID is the unique identifier for the invite which is
+	// the hash of the invite code
+	ID string `json:"id"`
+	// CreatedBy is the telegram chat ID of the admin that created the invite
+	CreatedBy int64 `json:"createdBy"`
+	// CreatedAt is the time the invite was created at
+	CreatedAt time.Time `json:"createdAt"`
+	// ExpiresAt is the time after which the invite can no longer be used.
+	// The invite never expires if it's nil
+	ExpiresAt *time.Time `json:"expiresAt,omitempty"`
+	// MaxUses is the number of times the invite can be used
+	MaxUses int `json:"maxUses"`
+	// UserOptions are the key=value options applied
+	// to the users registering with the invite
+	UserOptions []string `json:"userOptions,omitempty"`
+	// Uses holds every registration made with the invite
+	Uses []InviteUse `json:"uses,omitempty"`
+	// RevokedAt is the time the invite was revoked at.
+	// It's nil if the invite was not revoked
+	RevokedAt *time.Time `json:"revokedAt,omitempty"`
+	// RevokedBy is the telegram chat ID of the admin that revoked the invite
+	RevokedBy int64 `json:"revokedBy,omitempty"`
+}
+
+// InviteUse is a registration made with an invite.
+type InviteUse struct {
+	// TelegramChatID is the telegram chat ID that registered
+	TelegramChatID int64 `json:"telegramChatID"`
+	// UsedAt is the time of the registration
+	UsedAt time.Time `json:"usedAt"`
+}
+
+// IsRevoked checks if the invite was revoked.
+func (i Invite) IsRevoked() bool {
+	return i.RevokedAt != nil
+}
+
+// IsExpired checks if the invite is expired at the given time.
+func (i Invite) IsExpired(t time.Time) bool {
+	return i.ExpiresAt != nil && !t.Before(*i.ExpiresAt)
+}
+
+// IsUsedUp checks if the invite was used as many times as it can be.
+func (i Invite) IsUsedUp() bool {
+	return len(i.Uses) >= i.MaxUses
+}
+
+// IsUsable checks if the invite can be used at the given time.
+func (i Invite) IsUsable(t time.Time) bool {
+	return !i.IsRevoked() && !i.IsExpired(t) && !i.IsUsedUp()
+}
@@ -0,0 +1,57 @@
+package types
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestInvite_IsUsable(t *testing.T) {
+	now := time.Now()
+	yesterday := now.Add(-24 * time.Hour)
+	tomorrow := now.Add(24 * time.Hour)
+
+	tests := []struct {
+		name     string
+		invite   Invite
+		expected bool
+	}{
+		{
+			name:     "unused",
+			invite:   Invite{MaxUses: 1},
+			expected: true,
+		},
+		{
+			name:     "not expired",
+			invite:   Invite{MaxUses: 1, ExpiresAt: &tomorrow},
+			expected: true,
+		},
+		{
+			name:     "partially used",
+			invite:   Invite{MaxUses: 2, Uses: []InviteUse{{TelegramChatID: 1}}},
+			expected: true,
+		},
+		{
+			name:     "used up",
+			invite:   Invite{MaxUses: 1, Uses: []InviteUse{{TelegramChatID: 1}}},
+			expected: false,
+		},
+		{
+			name:     "expired",
+			invite:   Invite{MaxUses: 1, ExpiresAt: &yesterday},
+			expected: false,
+		},
+		{
+			name:     "revoked",
+			invite:   Invite{MaxUses: 1, RevokedAt: &yesterday},
+			expected: false,
+		},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			assert.Equal(t, test.expected, test.invite.IsUsable(now))
+		})
+	}
+}
@@ -0,0 +1,40 @@
+package types
+
+// Role is the role of a Telegram chat which decides
+// what bot commands the chat can use.
+type Role string
+
+const (
+	// RoleMember is the role of every chat that doesn't have another role.
+	RoleMember Role = "member"
+	// RoleAdmin is the role of chats that can manage users.
+	RoleAdmin Role = "admin"
+	// RoleOwner is the role of chats that can also manage admins.
+	RoleOwner Role = "owner"
+)
+
+// roleRanks holds the rank of every role. Higher ranked
+// roles include the permissions of the lower ranked ones.
+var roleRanks = map[Role]int{
+	RoleMember: 0,
+	RoleAdmin:  1,
+	RoleOwner:  2,
+}
+
+// Includes checks if the role grants at least the permissions of the given role.
+func (r Role) Includes(role Role) bool {
+	rank, ok := roleRanks[r]
+	if !ok {
+		return false
+	}
+
+	return rank >= roleRanks[role]
+}
+
+// ChatRole is the role of a Telegram chat.
+type ChatRole struct {
+	// TelegramChatID is the telegram chat ID the role belongs to
+	TelegramChatID int64 `json:"telegramChatID"`
+	// Role is the role of the chat
+	Role Role `json:"role"`
+}
@@ -0,0 +1,55 @@
+package types
+
+// Permission is an action a user token can be allowed to do.
+type Permission string
+
+const (
+	// PermissionIngest allows sending log entries.
+	PermissionIngest Permission = "ingest"
+	// PermissionHistoryRead allows reading previously sent log entries.
+	// There's no history endpoint yet so it's only stored and validated
+	// for now and the endpoint has to check it once it exists.
+	PermissionHistoryRead Permission = "history:read"
+)
+
+// Permissions contains all of the known permissions.
+var Permissions = []Permission{
+	PermissionIngest,
+	PermissionHistoryRead,
+}
+
+// Scopes limits what a user token can be used for.
+// An empty list means there's no limit for that list's kind.
+type Scopes struct {
+	// Permissions is the list of actions the token is allowed to do
+	Permissions []Permission `json:"permissions,omitempty"`
+	// Levels is the list of log levels the token is allowed to send
+	Levels []string `json:"levels,omitempty"`
+	// Endpoints is the list of ingestion endpoint paths the token is allowed to use
+	Endpoints []string `json:"endpoints,omitempty"`
+}
+
+// HasPermission checks if the given permission is granted.
+func (s Scopes) HasPermission(permission Permission) bool {
+	return len(s.Permissions) == 0 || contains(s.Permissions, permission)
+}
+
+// AllowsLevel checks if the given log level is allowed.
+func (s Scopes) AllowsLevel(level string) bool {
+	return len(s.Levels) == 0 || contains(s.Levels, level)
+}
+
+// AllowsEndpoint checks if the given endpoint path is allowed.
+func (s Scopes) AllowsEndpoint(path string) bool {
+	return len(s.Endpoints) == 0 || contains(s.Endpoints, path)
+}
+
+func contains[T comparable](items []T, item T) bool {
+	for _, i := range items {
+		if i == item {
+			return true
+		}
+	}
+
+	return false
+}
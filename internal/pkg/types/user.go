@@ -1,9 +1,52 @@
 package types
 
+import "time"
+
+// UserStatus is the status of a user.
+type UserStatus string
+
+const (
+	// UserStatusActive is the status of users whose token can be used.
+	UserStatusActive UserStatus = "active"
+	// UserStatusPending is the status of users waiting for
+	// an admin to approve their registration.
+	UserStatusPending UserStatus = "pending"
+	// UserStatusInactive is the status of users whose chat can't be
+	// reached because the bot was blocked or removed from it.
+	UserStatusInactive UserStatus = "inactive"
+)
+
 // User represents a user in the system.
 type User struct {
-	// ID is the unique identifier for the user which acts like a token
+	// ID is the unique identifier for the user which is the hash of the
+	// token the user authenticates with
 	ID string `json:"id"`
 	// TelegramChatID is the telegram chat ID of the user
 	TelegramChatID int64 `json:"telegramChatID"`
+	// ChatTitle is the title of the telegram chat (or the name
+	// of the user for private chats) when the user was created
+	ChatTitle string `json:"chatTitle,omitempty"`
+	// Status is the status of the user. An empty status means active
+	Status UserStatus `json:"status,omitempty"`
+	// ExpiresAt is the time after which the token is no longer valid.
+	// The token never expires if it's nil
+	ExpiresAt *time.Time `json:"expiresAt,omitempty"`
+	// ExpiryWarningSent is true if the user was already
+	// warned that the token is about to expire
+	ExpiryWarningSent bool `json:"expiryWarningSent,omitempty"`
+	// Scopes limits what the token can be used for
+	Scopes Scopes `json:"scopes"`
+	// AllowedCIDRs is the list of IP ranges the token can be used from.
+	// The token can be used from anywhere if it's empty
+	AllowedCIDRs []string `json:"allowedCIDRs,omitempty"`
+}
+
+// IsExpired checks if the user's token is expired at the given time.
+func (u User) IsExpired(t time.Time) bool {
+	return u.ExpiresAt != nil && !t.Before(*u.ExpiresAt)
+}
+
+// IsActive checks if the user's token can be used.
+func (u User) IsActive() bool {
+	return u.Status == "" || u.Status == UserStatusActive
 }
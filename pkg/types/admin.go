@@ -0,0 +1,52 @@
+package types
+
+import "time"
+
+// AdminUserScopes limits what a user token can be used for.
+// An empty list means there's no limit for that list's kind.
+type AdminUserScopes struct {
+	// Permissions is the list of actions the token is allowed to do (ingest, history:read)
+	Permissions []string `json:"permissions,omitempty"`
+	// Levels is the list of log levels the token is allowed to send
+	Levels []string `json:"levels,omitempty"`
+	// Endpoints is the list of ingestion endpoint paths the token is allowed to use
+	Endpoints []string `json:"endpoints,omitempty"`
+}
+
+// AdminUser is the representation of a user in the admin API.
+type AdminUser struct {
+	// ID is the hash of the user's token which is also the
+	// key ID used for signing requests
+	ID             string `json:"id"`
+	TelegramChatID int64  `json:"telegramChatID"`
+	// ChatTitle is the title of the telegram chat, if known
+	ChatTitle string `json:"chatTitle,omitempty"`
+	// Status is the status of the user (active, pending or inactive)
+	Status       string          `json:"status"`
+	ExpiresAt    *time.Time      `json:"expiresAt,omitempty"`
+	Scopes       AdminUserScopes `json:"scopes"`
+	AllowedCIDRs []string        `json:"allowedCIDRs,omitempty"`
+}
+
+// AdminUserRequest is the body of the admin API requests
+// for creating and updating a user.
+type AdminUserRequest struct {
+	TelegramChatID int64           `json:"telegramChatID"`
+	ExpiresAt      *time.Time      `json:"expiresAt,omitempty"`
+	Scopes         AdminUserScopes `json:"scopes"`
+	AllowedCIDRs   []string        `json:"allowedCIDRs,omitempty"`
+}
+
+// AdminUserCreateResponse is the body of the admin API response for
+// creating a user. It is the only time the token and the signing
+// secret are shown.
+type AdminUserCreateResponse struct {
+	User          AdminUser `json:"user"`
+	Token         string    `json:"token"`
+	SigningSecret string    `json:"signingSecret"`
+}
+
+// AdminUserListResponse is the body of the admin API response for listing users.
+type AdminUserListResponse struct {
+	Users []AdminUser `json:"users"`
+}
@@ -1,7 +1,28 @@
 package types
 
+const (
+	// ResponseReasonInvalidToken is the reason given when
+	// the token is missing or doesn't exist.
+	ResponseReasonInvalidToken = "invalid_token"
+	// ResponseReasonTokenExpired is the reason given when the token is expired.
+	ResponseReasonTokenExpired = "token_expired"
+	// ResponseReasonInvalidSignature is the reason given when a signed
+	// request's signature, timestamp or nonce is not valid.
+	ResponseReasonInvalidSignature = "invalid_signature"
+	// ResponseReasonIPNotAllowed is the reason given when the request comes
+	// from an IP outside of the token's allowed IP ranges.
+	ResponseReasonIPNotAllowed = "ip_not_allowed"
+	// ResponseReasonInsufficientScope is the reason given when the
+	// token's scopes don't allow the request.
+	ResponseReasonInsufficientScope = "insufficient_scope"
+	// ResponseReasonChatUnreachable is the reason given when the token's chat
+	// can't be reached because the bot was blocked or removed from it.
+	ResponseReasonChatUnreachable = "chat_unreachable"
+)
+
 // Response is the struct representing the body of the HTTP response
 type Response struct {
 	Error   string `json:"error,omitempty"`
+	Reason  string `json:"reason,omitempty"`
 	Message string `json:"message,omitempty"`
 }
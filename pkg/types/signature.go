@@ -0,0 +1,71 @@
+package types
+
+import (
+	"crypto/hmac"
+	"crypto/rand"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"strconv"
+	"time"
+)
+
+const (
+	// HeaderNameKeyID is the header holding the key ID of a signed request.
+	HeaderNameKeyID = "X-Key-ID"
+	// HeaderNameTimestamp is the header holding the unix
+	// timestamp (in seconds) of a signed request.
+	HeaderNameTimestamp = "X-Timestamp"
+	// HeaderNameNonce is the header holding the unique nonce of a signed request.
+	HeaderNameNonce = "X-Nonce"
+	// HeaderNameSignature is the header holding the signature of a signed request.
+	HeaderNameSignature = "X-Signature"
+
+	nonceSize = 16
+)
+
+// SignedRequest is a serialized request body along with
+// the headers needed to send it as a signed request.
+type SignedRequest struct {
+	Body    []byte
+	Headers map[string]string
+}
+
+// Sign serializes the request and signs it with the given signing secret
+// for sending it to the given method and path at the given time.
+// A random nonce is generated for every call.
+func (r Request) Sign(keyID, signingSecret, method, path string, t time.Time) (SignedRequest, error) {
+	body, err := json.Marshal(r)
+	if err != nil {
+		return SignedRequest{}, err
+	}
+
+	nonceBytes := make([]byte, nonceSize)
+	if _, err := rand.Read(nonceBytes); err != nil {
+		return SignedRequest{}, err
+	}
+
+	nonce := hex.EncodeToString(nonceBytes)
+	timestamp := strconv.FormatInt(t.Unix(), 10)
+
+	return SignedRequest{
+		Body: body,
+		Headers: map[string]string{
+			HeaderNameKeyID:     keyID,
+			HeaderNameTimestamp: timestamp,
+			HeaderNameNonce:     nonce,
+			HeaderNameSignature: Signature(signingSecret, method, path, timestamp, nonce, body),
+		},
+	}, nil
+}
+
+// Signature returns the hex encoded HMAC-SHA256, keyed with the signing
+// secret, of the method, path, timestamp, nonce and body of a request
+// separated by new lines.
+func Signature(signingSecret, method, path, timestamp, nonce string, body []byte) string {
+	mac := hmac.New(sha256.New, []byte(signingSecret))
+	mac.Write([]byte(method + "\n" + path + "\n" + timestamp + "\n" + nonce + "\n"))
+	mac.Write(body)
+
+	return hex.EncodeToString(mac.Sum(nil))
+}
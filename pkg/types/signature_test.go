@@ -0,0 +1,44 @@
+package types
+
+import (
+	"strconv"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestSignature(t *testing.T) {
+	body := []byte(`{"message":"hello"}`)
+
+	signature := Signature("secret", "POST", "/", "1682942400", "abc", body)
+	assert.Len(t, signature, 64)
+	assert.Equal(t, signature, Signature("secret", "POST", "/", "1682942400", "abc", body))
+
+	assert.NotEqual(t, signature, Signature("other", "POST", "/", "1682942400", "abc", body))
+	assert.NotEqual(t, signature, Signature("secret", "PUT", "/", "1682942400", "abc", body))
+	assert.NotEqual(t, signature, Signature("secret", "POST", "/x", "1682942400", "abc", body))
+	assert.NotEqual(t, signature, Signature("secret", "POST", "/", "1682942401", "abc", body))
+	assert.NotEqual(t, signature, Signature("secret", "POST", "/", "1682942400", "abd", body))
+	assert.NotEqual(t, signature, Signature("secret", "POST", "/", "1682942400", "abc", []byte("{}")))
+}
+
+func TestRequest_Sign(t *testing.T) {
+	now := time.Date(2023, 5, 1, 12, 0, 0, 0, time.UTC)
+	request := Request{Level: "info", Message: "hello"}
+
+	signed, err := request.Sign("key-id", "secret", "POST", "/", now)
+	assert.NoError(t, err)
+
+	headers := signed.Headers
+	assert.Equal(t, "key-id", headers[HeaderNameKeyID])
+	assert.Equal(t, strconv.FormatInt(now.Unix(), 10), headers[HeaderNameTimestamp])
+	assert.NotEmpty(t, headers[HeaderNameNonce])
+	assert.Equal(t,
+		Signature("secret", "POST", "/", headers[HeaderNameTimestamp], headers[HeaderNameNonce], signed.Body),
+		headers[HeaderNameSignature])
+
+	other, err := request.Sign("key-id", "secret", "POST", "/", now)
+	assert.NoError(t, err)
+	assert.NotEqual(t, headers[HeaderNameNonce], other.Headers[HeaderNameNonce])
+}